@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"flag"
+	"io"
 	"log"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 
 	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/tlsconfig"
 )
 
 const (
@@ -20,33 +24,66 @@ const (
 func main() {
 	// get configuration
 	address := flag.String("server", "", "gRPC server in format host:port")
+	caFile := flag.String("tls-ca", "", "PEM file of CA certificates to verify server, system CAs are used if empty")
+	certFile := flag.String("tls-cert", "", "PEM file of client certificate chain for mutual TLS")
+	keyFile := flag.String("tls-key", "", "PEM file of client private key for mutual TLS")
+	minVersion := flag.String("tls-min-version", "1.2", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	insecure := flag.Bool("insecure", false, "Connect in plaintext without TLS, for local use only")
+	token := flag.String("token", "", "JWT bearer token to authenticate calls")
 	flag.Parse()
 
+	creds := grpc.WithInsecure()
+	if !*insecure {
+		version, err := tlsconfig.ParseVersion(*minVersion)
+		if err != nil {
+			log.Fatalf("invalid TLS version: %v", err)
+		}
+		tlsConfig, err := tlsconfig.ClientConfig(*certFile, *keyFile, *caFile, version)
+		if err != nil {
+			log.Fatalf("invalid TLS configuration: %v", err)
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
 	// Set up a connection to the server.
-	conn, err := grpc.Dial(*address, grpc.WithInsecure())
+	conn, err := grpc.Dial(*address, creds)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
 	defer conn.Close()
 
 	c := v1.NewProductServiceClient(conn)
+	categories := v1.NewCategoryServiceClient(conn)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	if len(*token) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
 
 	t := time.Now().In(time.UTC)
 	date, _ := ptypes.TimestampProto(t)
 
+	// Call CreateCategory
+	category, err := categories.CreateCategory(ctx, &v1.CreateCategoryRequest{
+		Api:      apiVersion,
+		Category: &v1.Category{Name: "vegetable"},
+	})
+	if err != nil {
+		log.Fatalf("CreateCategory failed: %v", err)
+	}
+	log.Printf("CreateCategory result: <%+v>\n\n", category)
+
 	// Call Create
 	req1 := v1.CreateRequest{
 		Api: apiVersion,
 		Product: &v1.ProductProto{
 			Name:        "Potato",
-			Price:       "5€",
+			Price:       &v1.Money{CurrencyCode: "EUR", Units: 5},
 			Creator:     "Marty",
-			Unit:        "Kg",
+			Unit:        v1.UnitOfMeasure_KILOGRAM,
 			Description: "Buy my Potato",
-			Category:    "vegetable",
+			CategoryId:  category.Id,
 			Date:        date,
 		},
 	}
@@ -79,9 +116,10 @@ func main() {
 			Creator:     res2.Product.Creator + " + updated",
 			Unit:        res2.Product.Unit,
 			Description: res2.Product.Description + " + updated",
-			Category:    res2.Product.Category,
+			CategoryId:  res2.Product.CategoryId,
 			Date:        res2.Product.Date,
 		},
+		ExpectedRevision: res2.Product.Revision,
 	}
 
 	res3, err := c.Update(ctx, &req3)
@@ -100,6 +138,25 @@ func main() {
 	}
 	log.Printf("ReadAll result: <%+v>\n\n", res4)
 
+	// Call StreamProducts
+	req6 := v1.StreamProductsRequest{
+		Api: apiVersion,
+	}
+	stream, err := c.StreamProducts(ctx, &req6)
+	if err != nil {
+		log.Fatalf("StreamProducts failed: %v", err)
+	}
+	for {
+		res6, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("StreamProducts failed: %v", err)
+		}
+		log.Printf("StreamProducts result: <%+v>\n", res6)
+	}
+
 	// // Delete
 	// req5 := v1.DeleteRequest{
 	// 	Api: apiVersion,
@@ -0,0 +1,1196 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: category-service.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Category of products, categories form a tree
+type Category struct {
+	Id   int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// ID of parent category, 0 for root category
+	ParentId             int64    `protobuf:"varint,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Category) Reset()         { *m = Category{} }
+func (m *Category) String() string { return proto.CompactTextString(m) }
+func (*Category) ProtoMessage()    {}
+func (*Category) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{0}
+}
+
+func (m *Category) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Category.Unmarshal(m, b)
+}
+func (m *Category) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Category.Marshal(b, m, deterministic)
+}
+func (m *Category) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Category.Merge(m, src)
+}
+func (m *Category) XXX_Size() int {
+	return xxx_messageInfo_Category.Size(m)
+}
+func (m *Category) XXX_DiscardUnknown() {
+	xxx_messageInfo_Category.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Category proto.InternalMessageInfo
+
+func (m *Category) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Category) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Category) GetParentId() int64 {
+	if m != nil {
+		return m.ParentId
+	}
+	return 0
+}
+
+// Category with its subcategories
+type CategoryNode struct {
+	Category             *Category       `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Children             []*CategoryNode `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CategoryNode) Reset()         { *m = CategoryNode{} }
+func (m *CategoryNode) String() string { return proto.CompactTextString(m) }
+func (*CategoryNode) ProtoMessage()    {}
+func (*CategoryNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{1}
+}
+
+func (m *CategoryNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CategoryNode.Unmarshal(m, b)
+}
+func (m *CategoryNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CategoryNode.Marshal(b, m, deterministic)
+}
+func (m *CategoryNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CategoryNode.Merge(m, src)
+}
+func (m *CategoryNode) XXX_Size() int {
+	return xxx_messageInfo_CategoryNode.Size(m)
+}
+func (m *CategoryNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_CategoryNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CategoryNode proto.InternalMessageInfo
+
+func (m *CategoryNode) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+func (m *CategoryNode) GetChildren() []*CategoryNode {
+	if m != nil {
+		return m.Children
+	}
+	return nil
+}
+
+// Request data to create new category
+type CreateCategoryRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string    `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Category             *Category `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CreateCategoryRequest) Reset()         { *m = CreateCategoryRequest{} }
+func (m *CreateCategoryRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateCategoryRequest) ProtoMessage()    {}
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{2}
+}
+
+func (m *CreateCategoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateCategoryRequest.Unmarshal(m, b)
+}
+func (m *CreateCategoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateCategoryRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateCategoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateCategoryRequest.Merge(m, src)
+}
+func (m *CreateCategoryRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateCategoryRequest.Size(m)
+}
+func (m *CreateCategoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateCategoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateCategoryRequest proto.InternalMessageInfo
+
+func (m *CreateCategoryRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CreateCategoryRequest) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+// Contains data of created category
+type CreateCategoryResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateCategoryResponse) Reset()         { *m = CreateCategoryResponse{} }
+func (m *CreateCategoryResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateCategoryResponse) ProtoMessage()    {}
+func (*CreateCategoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{3}
+}
+
+func (m *CreateCategoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateCategoryResponse.Unmarshal(m, b)
+}
+func (m *CreateCategoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateCategoryResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateCategoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateCategoryResponse.Merge(m, src)
+}
+func (m *CreateCategoryResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateCategoryResponse.Size(m)
+}
+func (m *CreateCategoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateCategoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateCategoryResponse proto.InternalMessageInfo
+
+func (m *CreateCategoryResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CreateCategoryResponse) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Request data to read category
+type ReadCategoryRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadCategoryRequest) Reset()         { *m = ReadCategoryRequest{} }
+func (m *ReadCategoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadCategoryRequest) ProtoMessage()    {}
+func (*ReadCategoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{4}
+}
+
+func (m *ReadCategoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadCategoryRequest.Unmarshal(m, b)
+}
+func (m *ReadCategoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadCategoryRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadCategoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadCategoryRequest.Merge(m, src)
+}
+func (m *ReadCategoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadCategoryRequest.Size(m)
+}
+func (m *ReadCategoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadCategoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadCategoryRequest proto.InternalMessageInfo
+
+func (m *ReadCategoryRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadCategoryRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Contains category specified by ID
+type ReadCategoryResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string    `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Category             *Category `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ReadCategoryResponse) Reset()         { *m = ReadCategoryResponse{} }
+func (m *ReadCategoryResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadCategoryResponse) ProtoMessage()    {}
+func (*ReadCategoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{5}
+}
+
+func (m *ReadCategoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadCategoryResponse.Unmarshal(m, b)
+}
+func (m *ReadCategoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadCategoryResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadCategoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadCategoryResponse.Merge(m, src)
+}
+func (m *ReadCategoryResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadCategoryResponse.Size(m)
+}
+func (m *ReadCategoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadCategoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadCategoryResponse proto.InternalMessageInfo
+
+func (m *ReadCategoryResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadCategoryResponse) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+// Request data to rename category, parent_id is ignored, use MoveCategory to change it
+type UpdateCategoryRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string    `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Category             *Category `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UpdateCategoryRequest) Reset()         { *m = UpdateCategoryRequest{} }
+func (m *UpdateCategoryRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateCategoryRequest) ProtoMessage()    {}
+func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{6}
+}
+
+func (m *UpdateCategoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateCategoryRequest.Unmarshal(m, b)
+}
+func (m *UpdateCategoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateCategoryRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateCategoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateCategoryRequest.Merge(m, src)
+}
+func (m *UpdateCategoryRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateCategoryRequest.Size(m)
+}
+func (m *UpdateCategoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateCategoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateCategoryRequest proto.InternalMessageInfo
+
+func (m *UpdateCategoryRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *UpdateCategoryRequest) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+// Contains updated category
+type UpdateCategoryResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string    `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Category             *Category `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *UpdateCategoryResponse) Reset()         { *m = UpdateCategoryResponse{} }
+func (m *UpdateCategoryResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateCategoryResponse) ProtoMessage()    {}
+func (*UpdateCategoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{7}
+}
+
+func (m *UpdateCategoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateCategoryResponse.Unmarshal(m, b)
+}
+func (m *UpdateCategoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateCategoryResponse.Marshal(b, m, deterministic)
+}
+func (m *UpdateCategoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateCategoryResponse.Merge(m, src)
+}
+func (m *UpdateCategoryResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateCategoryResponse.Size(m)
+}
+func (m *UpdateCategoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateCategoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateCategoryResponse proto.InternalMessageInfo
+
+func (m *UpdateCategoryResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *UpdateCategoryResponse) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+// Request data to delete category, it must have neither subcategories nor products
+type DeleteCategoryRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteCategoryRequest) Reset()         { *m = DeleteCategoryRequest{} }
+func (m *DeleteCategoryRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteCategoryRequest) ProtoMessage()    {}
+func (*DeleteCategoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{8}
+}
+
+func (m *DeleteCategoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteCategoryRequest.Unmarshal(m, b)
+}
+func (m *DeleteCategoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteCategoryRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteCategoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteCategoryRequest.Merge(m, src)
+}
+func (m *DeleteCategoryRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteCategoryRequest.Size(m)
+}
+func (m *DeleteCategoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteCategoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteCategoryRequest proto.InternalMessageInfo
+
+func (m *DeleteCategoryRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *DeleteCategoryRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Contains status of delete operation
+type DeleteCategoryResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Equals 1 in case of succesfull delete
+	Deleted              int64    `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteCategoryResponse) Reset()         { *m = DeleteCategoryResponse{} }
+func (m *DeleteCategoryResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteCategoryResponse) ProtoMessage()    {}
+func (*DeleteCategoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{9}
+}
+
+func (m *DeleteCategoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteCategoryResponse.Unmarshal(m, b)
+}
+func (m *DeleteCategoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteCategoryResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteCategoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteCategoryResponse.Merge(m, src)
+}
+func (m *DeleteCategoryResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteCategoryResponse.Size(m)
+}
+func (m *DeleteCategoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteCategoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteCategoryResponse proto.InternalMessageInfo
+
+func (m *DeleteCategoryResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *DeleteCategoryResponse) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+// Request data to move category with its subcategories under another parent
+type MoveCategoryRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id  int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// ID of new parent category, 0 makes category root,
+	// it must be neither the category nor any of its subcategories
+	ParentId             int64    `protobuf:"varint,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveCategoryRequest) Reset()         { *m = MoveCategoryRequest{} }
+func (m *MoveCategoryRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveCategoryRequest) ProtoMessage()    {}
+func (*MoveCategoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{10}
+}
+
+func (m *MoveCategoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveCategoryRequest.Unmarshal(m, b)
+}
+func (m *MoveCategoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveCategoryRequest.Marshal(b, m, deterministic)
+}
+func (m *MoveCategoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveCategoryRequest.Merge(m, src)
+}
+func (m *MoveCategoryRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveCategoryRequest.Size(m)
+}
+func (m *MoveCategoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveCategoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveCategoryRequest proto.InternalMessageInfo
+
+func (m *MoveCategoryRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *MoveCategoryRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *MoveCategoryRequest) GetParentId() int64 {
+	if m != nil {
+		return m.ParentId
+	}
+	return 0
+}
+
+// Contains moved category
+type MoveCategoryResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string    `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Category             *Category `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *MoveCategoryResponse) Reset()         { *m = MoveCategoryResponse{} }
+func (m *MoveCategoryResponse) String() string { return proto.CompactTextString(m) }
+func (*MoveCategoryResponse) ProtoMessage()    {}
+func (*MoveCategoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{11}
+}
+
+func (m *MoveCategoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveCategoryResponse.Unmarshal(m, b)
+}
+func (m *MoveCategoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveCategoryResponse.Marshal(b, m, deterministic)
+}
+func (m *MoveCategoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveCategoryResponse.Merge(m, src)
+}
+func (m *MoveCategoryResponse) XXX_Size() int {
+	return xxx_messageInfo_MoveCategoryResponse.Size(m)
+}
+func (m *MoveCategoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveCategoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveCategoryResponse proto.InternalMessageInfo
+
+func (m *MoveCategoryResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *MoveCategoryResponse) GetCategory() *Category {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+// Request data to read tree of categories
+type ReadCategoryTreeRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// ID of category to read with its subcategories, 0 reads the whole tree
+	RootId               int64    `protobuf:"varint,2,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadCategoryTreeRequest) Reset()         { *m = ReadCategoryTreeRequest{} }
+func (m *ReadCategoryTreeRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadCategoryTreeRequest) ProtoMessage()    {}
+func (*ReadCategoryTreeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{12}
+}
+
+func (m *ReadCategoryTreeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadCategoryTreeRequest.Unmarshal(m, b)
+}
+func (m *ReadCategoryTreeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadCategoryTreeRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadCategoryTreeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadCategoryTreeRequest.Merge(m, src)
+}
+func (m *ReadCategoryTreeRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadCategoryTreeRequest.Size(m)
+}
+func (m *ReadCategoryTreeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadCategoryTreeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadCategoryTreeRequest proto.InternalMessageInfo
+
+func (m *ReadCategoryTreeRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadCategoryTreeRequest) GetRootId() int64 {
+	if m != nil {
+		return m.RootId
+	}
+	return 0
+}
+
+// Contains tree of categories
+type ReadCategoryTreeResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Requested category or all root categories
+	Roots                []*CategoryNode `protobuf:"bytes,2,rep,name=roots,proto3" json:"roots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ReadCategoryTreeResponse) Reset()         { *m = ReadCategoryTreeResponse{} }
+func (m *ReadCategoryTreeResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadCategoryTreeResponse) ProtoMessage()    {}
+func (*ReadCategoryTreeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{13}
+}
+
+func (m *ReadCategoryTreeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadCategoryTreeResponse.Unmarshal(m, b)
+}
+func (m *ReadCategoryTreeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadCategoryTreeResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadCategoryTreeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadCategoryTreeResponse.Merge(m, src)
+}
+func (m *ReadCategoryTreeResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadCategoryTreeResponse.Size(m)
+}
+func (m *ReadCategoryTreeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadCategoryTreeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadCategoryTreeResponse proto.InternalMessageInfo
+
+func (m *ReadCategoryTreeResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadCategoryTreeResponse) GetRoots() []*CategoryNode {
+	if m != nil {
+		return m.Roots
+	}
+	return nil
+}
+
+// Request data to list all subcategories of category
+type ListDescendantsRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDescendantsRequest) Reset()         { *m = ListDescendantsRequest{} }
+func (m *ListDescendantsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDescendantsRequest) ProtoMessage()    {}
+func (*ListDescendantsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{14}
+}
+
+func (m *ListDescendantsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDescendantsRequest.Unmarshal(m, b)
+}
+func (m *ListDescendantsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDescendantsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDescendantsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDescendantsRequest.Merge(m, src)
+}
+func (m *ListDescendantsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDescendantsRequest.Size(m)
+}
+func (m *ListDescendantsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDescendantsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDescendantsRequest proto.InternalMessageInfo
+
+func (m *ListDescendantsRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListDescendantsRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Contains subcategories in depth-first order, parents go before their children
+type ListDescendantsResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string      `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Categories           []*Category `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListDescendantsResponse) Reset()         { *m = ListDescendantsResponse{} }
+func (m *ListDescendantsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDescendantsResponse) ProtoMessage()    {}
+func (*ListDescendantsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba5b77ac732ac7db, []int{15}
+}
+
+func (m *ListDescendantsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDescendantsResponse.Unmarshal(m, b)
+}
+func (m *ListDescendantsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDescendantsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDescendantsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDescendantsResponse.Merge(m, src)
+}
+func (m *ListDescendantsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDescendantsResponse.Size(m)
+}
+func (m *ListDescendantsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDescendantsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDescendantsResponse proto.InternalMessageInfo
+
+func (m *ListDescendantsResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListDescendantsResponse) GetCategories() []*Category {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Category)(nil), "v1.Category")
+	proto.RegisterType((*CategoryNode)(nil), "v1.CategoryNode")
+	proto.RegisterType((*CreateCategoryRequest)(nil), "v1.CreateCategoryRequest")
+	proto.RegisterType((*CreateCategoryResponse)(nil), "v1.CreateCategoryResponse")
+	proto.RegisterType((*ReadCategoryRequest)(nil), "v1.ReadCategoryRequest")
+	proto.RegisterType((*ReadCategoryResponse)(nil), "v1.ReadCategoryResponse")
+	proto.RegisterType((*UpdateCategoryRequest)(nil), "v1.UpdateCategoryRequest")
+	proto.RegisterType((*UpdateCategoryResponse)(nil), "v1.UpdateCategoryResponse")
+	proto.RegisterType((*DeleteCategoryRequest)(nil), "v1.DeleteCategoryRequest")
+	proto.RegisterType((*DeleteCategoryResponse)(nil), "v1.DeleteCategoryResponse")
+	proto.RegisterType((*MoveCategoryRequest)(nil), "v1.MoveCategoryRequest")
+	proto.RegisterType((*MoveCategoryResponse)(nil), "v1.MoveCategoryResponse")
+	proto.RegisterType((*ReadCategoryTreeRequest)(nil), "v1.ReadCategoryTreeRequest")
+	proto.RegisterType((*ReadCategoryTreeResponse)(nil), "v1.ReadCategoryTreeResponse")
+	proto.RegisterType((*ListDescendantsRequest)(nil), "v1.ListDescendantsRequest")
+	proto.RegisterType((*ListDescendantsResponse)(nil), "v1.ListDescendantsResponse")
+}
+
+func init() { proto.RegisterFile("category-service.proto", fileDescriptor_ba5b77ac732ac7db) }
+
+var fileDescriptor_ba5b77ac732ac7db = []byte{
+	// 505 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x14, 0x54, 0x9c, 0xd2, 0x26, 0xd3, 0x28, 0x8d, 0xb6, 0x4d, 0x62, 0x36, 0x1c, 0x22, 0x1f, 0x50,
+	0x0e, 0x25, 0x52, 0xc3, 0x01, 0xc1, 0x0d, 0x35, 0x52, 0x55, 0xa0, 0x1c, 0x9c, 0x70, 0xe0, 0x84,
+	0x4c, 0xf6, 0x01, 0x96, 0x8a, 0x6d, 0x6c, 0x13, 0x89, 0x1f, 0xc1, 0x7f, 0x46, 0xde, 0xf5, 0xa6,
+	0xfe, 0x58, 0x97, 0x56, 0xca, 0xcd, 0xce, 0xbc, 0x37, 0x3b, 0x6f, 0xf2, 0x66, 0x8d, 0xd1, 0xc6,
+	0x4b, 0xe9, 0x7b, 0x18, 0xff, 0x79, 0x91, 0x50, 0xbc, 0xf5, 0x37, 0x34, 0x8f, 0xe2, 0x30, 0x0d,
+	0x99, 0xb5, 0xbd, 0x70, 0xde, 0xa3, 0x73, 0x99, 0xa3, 0xac, 0x0f, 0xcb, 0x17, 0x76, 0x6b, 0xda,
+	0x9a, 0xb5, 0x5d, 0xcb, 0x17, 0x8c, 0xe1, 0x20, 0xf0, 0x7e, 0x92, 0x6d, 0x4d, 0x5b, 0xb3, 0xae,
+	0x2b, 0x9f, 0xd9, 0x04, 0xdd, 0xc8, 0x8b, 0x29, 0x48, 0xbf, 0xf8, 0xc2, 0x6e, 0xcb, 0xd2, 0x8e,
+	0xfa, 0xe1, 0x5a, 0x38, 0xdf, 0xd0, 0xd3, 0x64, 0x1f, 0x43, 0x41, 0x6c, 0x86, 0x8e, 0x3e, 0x5a,
+	0xd2, 0x1e, 0x2f, 0x7a, 0xf3, 0xed, 0xc5, 0x5c, 0xd7, 0xb8, 0x3b, 0x94, 0x9d, 0xa3, 0xb3, 0xf9,
+	0xe1, 0xdf, 0x8a, 0x98, 0x02, 0xdb, 0x9a, 0xb6, 0x67, 0xc7, 0x8b, 0x41, 0xb1, 0x32, 0x63, 0x73,
+	0x77, 0x15, 0xce, 0x0a, 0xc3, 0xcb, 0x98, 0xbc, 0x94, 0x76, 0x4c, 0xf4, 0xeb, 0x37, 0x25, 0x29,
+	0x1b, 0xa0, 0xed, 0x45, 0xbe, 0x3c, 0xab, 0xeb, 0x66, 0x8f, 0x25, 0x09, 0xd6, 0x7d, 0x12, 0x9c,
+	0x37, 0x18, 0x55, 0x49, 0x93, 0x28, 0x0c, 0x12, 0x32, 0xb0, 0x2a, 0xa7, 0x2c, 0xed, 0x94, 0xf3,
+	0x0a, 0xa7, 0x2e, 0x79, 0xe2, 0xff, 0x72, 0xaa, 0x8d, 0x2e, 0xce, 0xca, 0x8d, 0x8d, 0x47, 0x3e,
+	0x7c, 0x90, 0x15, 0x86, 0x9f, 0x22, 0xb1, 0x67, 0x77, 0xd6, 0x18, 0x55, 0x49, 0xf7, 0x20, 0xf5,
+	0x35, 0x86, 0x4b, 0xba, 0xa5, 0x94, 0x1e, 0xef, 0xdc, 0x12, 0xa3, 0x6a, 0x6b, 0xa3, 0x20, 0x1b,
+	0x47, 0x42, 0xd6, 0x6a, 0x02, 0xfd, 0xea, 0xac, 0x71, 0x7a, 0x13, 0x6e, 0x1f, 0x7f, 0xfc, 0xfd,
+	0x39, 0x70, 0x71, 0x56, 0x66, 0xdd, 0x83, 0x55, 0x4b, 0x8c, 0x8b, 0x9b, 0xb2, 0x8e, 0x89, 0x9a,
+	0xd5, 0x8e, 0x71, 0x14, 0x87, 0xa1, 0xd4, 0xa6, 0x24, 0x1f, 0x66, 0xaf, 0xd7, 0xd9, 0xbc, 0x76,
+	0x9d, 0xa5, 0x51, 0xdd, 0x73, 0x3c, 0xc9, 0xfa, 0x92, 0xc6, 0x48, 0x2a, 0x38, 0x8b, 0xce, 0x07,
+	0x3f, 0x49, 0x97, 0x94, 0x6c, 0x28, 0x10, 0x5e, 0x90, 0x26, 0x0f, 0xff, 0x1f, 0x3f, 0x63, 0x5c,
+	0xeb, 0x6d, 0x14, 0x74, 0x0e, 0xe4, 0x86, 0xf8, 0xa4, 0x55, 0x95, 0x0d, 0x2b, 0xe0, 0x8b, 0xbf,
+	0x07, 0x38, 0xd1, 0xc0, 0x4a, 0xdd, 0x7c, 0xec, 0x0a, 0xfd, 0x72, 0xca, 0xd9, 0x53, 0xd9, 0x6f,
+	0xba, 0x4e, 0x38, 0x37, 0x41, 0xb9, 0xb8, 0xb7, 0xe8, 0x15, 0x9d, 0x64, 0xe3, 0xac, 0xd6, 0x70,
+	0x09, 0x70, 0xbb, 0x0e, 0xe4, 0x14, 0x57, 0xe8, 0x97, 0x33, 0xa5, 0xb4, 0x18, 0xc3, 0xcb, 0xb9,
+	0x09, 0xba, 0x23, 0x2a, 0x67, 0x41, 0x11, 0x19, 0xa3, 0xc5, 0xb9, 0x09, 0xba, 0x1b, 0xaa, 0xb8,
+	0xb8, 0x6a, 0x28, 0x43, 0x40, 0xb8, 0x5d, 0x07, 0x72, 0x8a, 0x1b, 0x0c, 0xaa, 0x1b, 0xc6, 0x26,
+	0x55, 0x0b, 0x0a, 0xdb, 0xcb, 0x9f, 0x99, 0xc1, 0x9c, 0xee, 0x1d, 0x4e, 0x2a, 0xeb, 0xc1, 0xe4,
+	0x00, 0xe6, 0x7d, 0xe3, 0x13, 0x23, 0xa6, 0xb8, 0xbe, 0x1e, 0xca, 0xcf, 0xde, 0xcb, 0x7f, 0x03,
+	0x00, 0xad, 0xc9, 0x05, 0x0b, 0x10, 0x07, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CategoryServiceClient is the client API for CategoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CategoryServiceClient interface {
+	// Create new category
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
+	// Read category
+	ReadCategory(ctx context.Context, in *ReadCategoryRequest, opts ...grpc.CallOption) (*ReadCategoryResponse, error)
+	// Rename category
+	UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error)
+	// Delete category
+	DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error)
+	// Move category under another parent
+	MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*MoveCategoryResponse, error)
+	// Read category with all its subcategories as tree
+	ReadCategoryTree(ctx context.Context, in *ReadCategoryTreeRequest, opts ...grpc.CallOption) (*ReadCategoryTreeResponse, error)
+	// List all subcategories of category
+	ListDescendants(ctx context.Context, in *ListDescendantsRequest, opts ...grpc.CallOption) (*ListDescendantsResponse, error)
+}
+
+type categoryServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCategoryServiceClient(cc *grpc.ClientConn) CategoryServiceClient {
+	return &categoryServiceClient{cc}
+}
+
+func (c *categoryServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error) {
+	out := new(CreateCategoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/CreateCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ReadCategory(ctx context.Context, in *ReadCategoryRequest, opts ...grpc.CallOption) (*ReadCategoryResponse, error) {
+	out := new(ReadCategoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/ReadCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error) {
+	out := new(UpdateCategoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/UpdateCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error) {
+	out := new(DeleteCategoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/DeleteCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*MoveCategoryResponse, error) {
+	out := new(MoveCategoryResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/MoveCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ReadCategoryTree(ctx context.Context, in *ReadCategoryTreeRequest, opts ...grpc.CallOption) (*ReadCategoryTreeResponse, error) {
+	out := new(ReadCategoryTreeResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/ReadCategoryTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ListDescendants(ctx context.Context, in *ListDescendantsRequest, opts ...grpc.CallOption) (*ListDescendantsResponse, error) {
+	out := new(ListDescendantsResponse)
+	err := c.cc.Invoke(ctx, "/v1.CategoryService/ListDescendants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CategoryServiceServer is the server API for CategoryService service.
+type CategoryServiceServer interface {
+	// Create new category
+	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
+	// Read category
+	ReadCategory(context.Context, *ReadCategoryRequest) (*ReadCategoryResponse, error)
+	// Rename category
+	UpdateCategory(context.Context, *UpdateCategoryRequest) (*UpdateCategoryResponse, error)
+	// Delete category
+	DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteCategoryResponse, error)
+	// Move category under another parent
+	MoveCategory(context.Context, *MoveCategoryRequest) (*MoveCategoryResponse, error)
+	// Read category with all its subcategories as tree
+	ReadCategoryTree(context.Context, *ReadCategoryTreeRequest) (*ReadCategoryTreeResponse, error)
+	// List all subcategories of category
+	ListDescendants(context.Context, *ListDescendantsRequest) (*ListDescendantsResponse, error)
+}
+
+// UnimplementedCategoryServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedCategoryServiceServer struct {
+}
+
+func (*UnimplementedCategoryServiceServer) CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*CreateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (*UnimplementedCategoryServiceServer) ReadCategory(ctx context.Context, req *ReadCategoryRequest) (*ReadCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadCategory not implemented")
+}
+func (*UnimplementedCategoryServiceServer) UpdateCategory(ctx context.Context, req *UpdateCategoryRequest) (*UpdateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+func (*UnimplementedCategoryServiceServer) DeleteCategory(ctx context.Context, req *DeleteCategoryRequest) (*DeleteCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+func (*UnimplementedCategoryServiceServer) MoveCategory(ctx context.Context, req *MoveCategoryRequest) (*MoveCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCategory not implemented")
+}
+func (*UnimplementedCategoryServiceServer) ReadCategoryTree(ctx context.Context, req *ReadCategoryTreeRequest) (*ReadCategoryTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadCategoryTree not implemented")
+}
+func (*UnimplementedCategoryServiceServer) ListDescendants(ctx context.Context, req *ListDescendantsRequest) (*ListDescendantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDescendants not implemented")
+}
+
+func RegisterCategoryServiceServer(s *grpc.Server, srv CategoryServiceServer) {
+	s.RegisterService(&_CategoryService_serviceDesc, srv)
+}
+
+func _CategoryService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/CreateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ReadCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ReadCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/ReadCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ReadCategory(ctx, req.(*ReadCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/UpdateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).UpdateCategory(ctx, req.(*UpdateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/DeleteCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).DeleteCategory(ctx, req.(*DeleteCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_MoveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).MoveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/MoveCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).MoveCategory(ctx, req.(*MoveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ReadCategoryTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadCategoryTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ReadCategoryTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/ReadCategoryTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ReadCategoryTree(ctx, req.(*ReadCategoryTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ListDescendants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDescendantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ListDescendants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.CategoryService/ListDescendants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ListDescendants(ctx, req.(*ListDescendantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CategoryService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.CategoryService",
+	HandlerType: (*CategoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateCategory",
+			Handler:    _CategoryService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "ReadCategory",
+			Handler:    _CategoryService_ReadCategory_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _CategoryService_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _CategoryService_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "MoveCategory",
+			Handler:    _CategoryService_MoveCategory_Handler,
+		},
+		{
+			MethodName: "ReadCategoryTree",
+			Handler:    _CategoryService_ReadCategoryTree_Handler,
+		},
+		{
+			MethodName: "ListDescendants",
+			Handler:    _CategoryService_ListDescendants_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "category-service.proto",
+}
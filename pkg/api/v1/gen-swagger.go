@@ -0,0 +1,52 @@
+//go:build ignore
+// +build ignore
+
+// gen-swagger writes OpenAPI document to Go constant, so that it is embedded in server binary:
+//
+//	go run pkg/api/v1/gen-swagger.go <swagger.json> <output.go>
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: go run gen-swagger.go <swagger.json> <output.go>")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes content of swagger file to ProductServiceSwagger constant of output file
+func generate(swagger, output string) error {
+	doc, err := ioutil.ReadFile(swagger)
+	if err != nil {
+		return err
+	}
+
+	// raw string literal can't contain backquote, it is concatenated as interpreted string
+	literal := "`" + strings.Replace(string(doc), "`", "` + \"`\" + `", -1) + "`"
+	src := fmt.Sprintf(`// Code generated by gen-swagger.go. DO NOT EDIT.
+// source: %s
+
+package v1
+
+// ProductServiceSwagger is OpenAPI v2 document of Product service REST gateway
+const ProductServiceSwagger = %s
+`, filepath.ToSlash(swagger), literal)
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return ioutil.WriteFile(output, formatted, 0644)
+}
@@ -8,9 +8,13 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
+	status1 "google.golang.org/grpc/status"
 	math "math"
 )
 
@@ -25,15 +29,173 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// UnitOfMeasure is unit product is sold in, product price is price of one unit
+type UnitOfMeasure int32
+
+const (
+	UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED UnitOfMeasure = 0
+	// Mass
+	UnitOfMeasure_GRAM     UnitOfMeasure = 1
+	UnitOfMeasure_KILOGRAM UnitOfMeasure = 2
+	// Volume
+	UnitOfMeasure_MILLILITER UnitOfMeasure = 3
+	UnitOfMeasure_LITER      UnitOfMeasure = 4
+	// Count
+	UnitOfMeasure_PIECE UnitOfMeasure = 5
+	UnitOfMeasure_DOZEN UnitOfMeasure = 6
+	// Length
+	UnitOfMeasure_MILLIMETER UnitOfMeasure = 7
+	UnitOfMeasure_CENTIMETER UnitOfMeasure = 8
+	UnitOfMeasure_METER      UnitOfMeasure = 9
+)
+
+var UnitOfMeasure_name = map[int32]string{
+	0: "UNIT_OF_MEASURE_UNSPECIFIED",
+	1: "GRAM",
+	2: "KILOGRAM",
+	3: "MILLILITER",
+	4: "LITER",
+	5: "PIECE",
+	6: "DOZEN",
+	7: "MILLIMETER",
+	8: "CENTIMETER",
+	9: "METER",
+}
+
+var UnitOfMeasure_value = map[string]int32{
+	"UNIT_OF_MEASURE_UNSPECIFIED": 0,
+	"GRAM":                        1,
+	"KILOGRAM":                    2,
+	"MILLILITER":                  3,
+	"LITER":                       4,
+	"PIECE":                       5,
+	"DOZEN":                       6,
+	"MILLIMETER":                  7,
+	"CENTIMETER":                  8,
+	"METER":                       9,
+}
+
+func (x UnitOfMeasure) String() string {
+	return proto.EnumName(UnitOfMeasure_name, int32(x))
+}
+
+func (UnitOfMeasure) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{0}
+}
+
+// Kind of the Product change
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNSPECIFIED ChangeType = 0
+	ChangeType_CREATED                 ChangeType = 1
+	ChangeType_UPDATED                 ChangeType = 2
+	ChangeType_DELETED                 ChangeType = 3
+	ChangeType_UNDELETED               ChangeType = 4
+)
+
+var ChangeType_name = map[int32]string{
+	0: "CHANGE_TYPE_UNSPECIFIED",
+	1: "CREATED",
+	2: "UPDATED",
+	3: "DELETED",
+	4: "UNDELETED",
+}
+
+var ChangeType_value = map[string]int32{
+	"CHANGE_TYPE_UNSPECIFIED": 0,
+	"CREATED":                 1,
+	"UPDATED":                 2,
+	"DELETED":                 3,
+	"UNDELETED":               4,
+}
+
+func (x ChangeType) String() string {
+	return proto.EnumName(ChangeType_name, int32(x))
+}
+
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{1}
+}
+
+// Money is amount of money in currency, like google.type.Money
+type Money struct {
+	// ISO 4217 currency code, e.g. "EUR"
+	CurrencyCode string `protobuf:"bytes,1,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	// Whole units of the amount, e.g. 5 for 5.99 EUR
+	Units int64 `protobuf:"varint,2,opt,name=units,proto3" json:"units,omitempty"`
+	// Nano (10^-9) units of the amount, e.g. 990000000 for 5.99 EUR.
+	// It must have the same sign as units and it must fit into minor unit of the currency.
+	Nanos                int32    `protobuf:"varint,3,opt,name=nanos,proto3" json:"nanos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Money) Reset()         { *m = Money{} }
+func (m *Money) String() string { return proto.CompactTextString(m) }
+func (*Money) ProtoMessage()    {}
+func (*Money) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{0}
+}
+
+func (m *Money) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Money.Unmarshal(m, b)
+}
+func (m *Money) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Money.Marshal(b, m, deterministic)
+}
+func (m *Money) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Money.Merge(m, src)
+}
+func (m *Money) XXX_Size() int {
+	return xxx_messageInfo_Money.Size(m)
+}
+func (m *Money) XXX_DiscardUnknown() {
+	xxx_messageInfo_Money.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Money proto.InternalMessageInfo
+
+func (m *Money) GetCurrencyCode() string {
+	if m != nil {
+		return m.CurrencyCode
+	}
+	return ""
+}
+
+func (m *Money) GetUnits() int64 {
+	if m != nil {
+		return m.Units
+	}
+	return 0
+}
+
+func (m *Money) GetNanos() int32 {
+	if m != nil {
+		return m.Nanos
+	}
+	return 0
+}
+
 type ProductProto struct {
-	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Price                string               `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
-	Creator              string               `protobuf:"bytes,4,opt,name=creator,proto3" json:"creator,omitempty"`
-	Unit                 string               `protobuf:"bytes,5,opt,name=unit,proto3" json:"unit,omitempty"`
-	Description          string               `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
-	Category             string               `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
-	Date                 *timestamp.Timestamp `protobuf:"bytes,8,opt,name=date,proto3" json:"date,omitempty"`
+	Id          int64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string        `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Price       *Money        `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	Creator     string        `protobuf:"bytes,4,opt,name=creator,proto3" json:"creator,omitempty"`
+	Unit        UnitOfMeasure `protobuf:"varint,11,opt,name=unit,proto3,enum=v1.UnitOfMeasure" json:"unit,omitempty"`
+	Description string        `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// ID of product category, 0 if product has no category
+	CategoryId int64                `protobuf:"varint,13,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Date       *timestamp.Timestamp `protobuf:"bytes,8,opt,name=date,proto3" json:"date,omitempty"`
+	// Revision is maintained by server and incremented on every update
+	Revision int64 `protobuf:"varint,9,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Price per unit requested by ReadAllRequest.price_per, it is output only and it is set
+	// by ReadAll if product is priced and its unit measures the same dimension, e.g. mass
+	UnitPrice *Money `protobuf:"bytes,12,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	// Time product was deleted at, it is output only and it is set for deleted products
+	// listed by ReadAll with show_deleted
+	DeleteTime           *timestamp.Timestamp `protobuf:"bytes,14,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -43,7 +205,7 @@ func (m *ProductProto) Reset()         { *m = ProductProto{} }
 func (m *ProductProto) String() string { return proto.CompactTextString(m) }
 func (*ProductProto) ProtoMessage()    {}
 func (*ProductProto) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{0}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{1}
 }
 
 func (m *ProductProto) XXX_Unmarshal(b []byte) error {
@@ -78,11 +240,11 @@ func (m *ProductProto) GetName() string {
 	return ""
 }
 
-func (m *ProductProto) GetPrice() string {
+func (m *ProductProto) GetPrice() *Money {
 	if m != nil {
 		return m.Price
 	}
-	return ""
+	return nil
 }
 
 func (m *ProductProto) GetCreator() string {
@@ -92,11 +254,11 @@ func (m *ProductProto) GetCreator() string {
 	return ""
 }
 
-func (m *ProductProto) GetUnit() string {
+func (m *ProductProto) GetUnit() UnitOfMeasure {
 	if m != nil {
 		return m.Unit
 	}
-	return ""
+	return UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED
 }
 
 func (m *ProductProto) GetDescription() string {
@@ -106,11 +268,11 @@ func (m *ProductProto) GetDescription() string {
 	return ""
 }
 
-func (m *ProductProto) GetCategory() string {
+func (m *ProductProto) GetCategoryId() int64 {
 	if m != nil {
-		return m.Category
+		return m.CategoryId
 	}
-	return ""
+	return 0
 }
 
 func (m *ProductProto) GetDate() *timestamp.Timestamp {
@@ -120,21 +282,46 @@ func (m *ProductProto) GetDate() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *ProductProto) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ProductProto) GetUnitPrice() *Money {
+	if m != nil {
+		return m.UnitPrice
+	}
+	return nil
+}
+
+func (m *ProductProto) GetDeleteTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
 // Request data to create new todo task
 type CreateRequest struct {
 	// API versioning: it is my best practice to specify version explicitly
-	Api                  string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
-	Product              *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Api     string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Product *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	// Client-supplied unique ID of the request, e.g. UUID, retry of the request with the same ID
+	// returns the response of the first attempt instead of creating another product.
+	// Reuse of the ID for another request fails with ALREADY_EXISTS until the ID expires.
+	RequestId            string   `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{1}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{2}
 }
 
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
@@ -169,11 +356,20 @@ func (m *CreateRequest) GetProduct() *ProductProto {
 	return nil
 }
 
+func (m *CreateRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
 // Contains data of created todo task
 type CreateResponse struct {
 	// API versioning: it is my best practice to specify version explicitly
-	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
-	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id  int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Revision of created product
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -183,7 +379,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{2}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{3}
 }
 
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
@@ -218,6 +414,13 @@ func (m *CreateResponse) GetId() int64 {
 	return 0
 }
 
+func (m *CreateResponse) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
 // Request data to read todo task
 type ReadRequest struct {
 	// API versioning: it is my best practice to specify version explicitly
@@ -232,7 +435,7 @@ func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{3}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{4}
 }
 
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
@@ -281,7 +484,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{4}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{5}
 }
 
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
@@ -319,18 +522,24 @@ func (m *ReadResponse) GetProduct() *ProductProto {
 // Request data to update todo task
 type UpdateRequest struct {
 	// API versioning: it is my best practice to specify version explicitly
-	Api                  string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
-	Product              *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Api     string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Product *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	// Fields of product to update, e.g. "price", all fields are replaced if it is empty
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Update fails with ABORTED if product revision differs, 0 means update unconditionally
+	ExpectedRevision int64 `protobuf:"varint,4,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	// Client-supplied unique ID of the request, the same as CreateRequest.request_id
+	RequestId            string   `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *UpdateRequest) Reset()         { *m = UpdateRequest{} }
 func (m *UpdateRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateRequest) ProtoMessage()    {}
 func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{5}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{6}
 }
 
 func (m *UpdateRequest) XXX_Unmarshal(b []byte) error {
@@ -365,6 +574,27 @@ func (m *UpdateRequest) GetProduct() *ProductProto {
 	return nil
 }
 
+func (m *UpdateRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UpdateRequest) GetExpectedRevision() int64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+func (m *UpdateRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
 // Contains status of update operation
 type UpdateResponse struct {
 	// API versioning: it is my best practice to specify version explicitly
@@ -380,7 +610,7 @@ func (m *UpdateResponse) Reset()         { *m = UpdateResponse{} }
 func (m *UpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*UpdateResponse) ProtoMessage()    {}
 func (*UpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{6}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{7}
 }
 
 func (m *UpdateResponse) XXX_Unmarshal(b []byte) error {
@@ -418,8 +648,10 @@ func (m *UpdateResponse) GetUpdated() int64 {
 // Request data to delete todo task
 type DeleteRequest struct {
 	// API versioning: it is my best practice to specify version explicitly
-	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
-	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id  int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Delete fails with ABORTED if product revision differs, 0 means delete unconditionally
+	ExpectedRevision     int64    `protobuf:"varint,3,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -429,7 +661,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{7}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{8}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -464,6 +696,13 @@ func (m *DeleteRequest) GetId() int64 {
 	return 0
 }
 
+func (m *DeleteRequest) GetExpectedRevision() int64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
 // Contains status of delete operation
 type DeleteResponse struct {
 	// API versioning: it is my best practice to specify version explicitly
@@ -479,7 +718,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{8}
+	return fileDescriptor_44eb248bc1c5c9a9, []int{9}
 }
 
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
@@ -514,188 +753,1526 @@ func (m *DeleteResponse) GetDeleted() int64 {
 	return 0
 }
 
-// Request data to read all todo task
-type ReadAllRequest struct {
+// Request data to restore deleted product
+type UndeleteRequest struct {
 	// API versioning: it is my best practice to specify version explicitly
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ReadAllRequest) Reset()         { *m = ReadAllRequest{} }
-func (m *ReadAllRequest) String() string { return proto.CompactTextString(m) }
-func (*ReadAllRequest) ProtoMessage()    {}
-func (*ReadAllRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{9}
+func (m *UndeleteRequest) Reset()         { *m = UndeleteRequest{} }
+func (m *UndeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteRequest) ProtoMessage()    {}
+func (*UndeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{10}
 }
 
-func (m *ReadAllRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReadAllRequest.Unmarshal(m, b)
+func (m *UndeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteRequest.Unmarshal(m, b)
 }
-func (m *ReadAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReadAllRequest.Marshal(b, m, deterministic)
+func (m *UndeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteRequest.Marshal(b, m, deterministic)
 }
-func (m *ReadAllRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReadAllRequest.Merge(m, src)
+func (m *UndeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteRequest.Merge(m, src)
 }
-func (m *ReadAllRequest) XXX_Size() int {
-	return xxx_messageInfo_ReadAllRequest.Size(m)
+func (m *UndeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteRequest.Size(m)
 }
-func (m *ReadAllRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReadAllRequest.DiscardUnknown(m)
+func (m *UndeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ReadAllRequest proto.InternalMessageInfo
+var xxx_messageInfo_UndeleteRequest proto.InternalMessageInfo
 
-func (m *ReadAllRequest) GetApi() string {
+func (m *UndeleteRequest) GetApi() string {
 	if m != nil {
 		return m.Api
 	}
 	return ""
 }
 
-// Contains list of all todo tasks
-type ReadAllResponse struct {
+func (m *UndeleteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// Contains restored product
+type UndeleteResponse struct {
 	// API versioning: it is my best practice to specify version explicitly
-	Api                  string          `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
-	Products             []*ProductProto `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Api                  string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Product              *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-func (m *ReadAllResponse) Reset()         { *m = ReadAllResponse{} }
-func (m *ReadAllResponse) String() string { return proto.CompactTextString(m) }
-func (*ReadAllResponse) ProtoMessage()    {}
-func (*ReadAllResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_44eb248bc1c5c9a9, []int{10}
+func (m *UndeleteResponse) Reset()         { *m = UndeleteResponse{} }
+func (m *UndeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*UndeleteResponse) ProtoMessage()    {}
+func (*UndeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{11}
 }
 
-func (m *ReadAllResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReadAllResponse.Unmarshal(m, b)
+func (m *UndeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteResponse.Unmarshal(m, b)
 }
-func (m *ReadAllResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReadAllResponse.Marshal(b, m, deterministic)
+func (m *UndeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteResponse.Marshal(b, m, deterministic)
 }
-func (m *ReadAllResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReadAllResponse.Merge(m, src)
+func (m *UndeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteResponse.Merge(m, src)
 }
-func (m *ReadAllResponse) XXX_Size() int {
-	return xxx_messageInfo_ReadAllResponse.Size(m)
+func (m *UndeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_UndeleteResponse.Size(m)
 }
-func (m *ReadAllResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReadAllResponse.DiscardUnknown(m)
+func (m *UndeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ReadAllResponse proto.InternalMessageInfo
+var xxx_messageInfo_UndeleteResponse proto.InternalMessageInfo
 
-func (m *ReadAllResponse) GetApi() string {
+func (m *UndeleteResponse) GetApi() string {
 	if m != nil {
 		return m.Api
 	}
 	return ""
 }
 
-func (m *ReadAllResponse) GetProducts() []*ProductProto {
+func (m *UndeleteResponse) GetProduct() *ProductProto {
 	if m != nil {
-		return m.Products
+		return m.Product
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ProductProto)(nil), "v1.ProductProto")
-	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
-	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
-	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
-	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
-	proto.RegisterType((*ReadAllRequest)(nil), "v1.ReadAllRequest")
-	proto.RegisterType((*ReadAllResponse)(nil), "v1.ReadAllResponse")
+// Filter to select products, all specified conditions must match
+type ProductFilter struct {
+	// Product creator equals to creator
+	Creator string `protobuf:"bytes,2,opt,name=creator,proto3" json:"creator,omitempty"`
+	// Product date is equal to or after date_from
+	DateFrom *timestamp.Timestamp `protobuf:"bytes,4,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	// Product date is before date_to
+	DateTo *timestamp.Timestamp `protobuf:"bytes,5,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	// Product name starts with name_prefix
+	NamePrefix string `protobuf:"bytes,6,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// Product price is equal to or greater than price_from, in the same currency
+	PriceFrom *Money `protobuf:"bytes,7,opt,name=price_from,json=priceFrom,proto3" json:"price_from,omitempty"`
+	// Product price is less than price_to, in the same currency
+	PriceTo *Money `protobuf:"bytes,8,opt,name=price_to,json=priceTo,proto3" json:"price_to,omitempty"`
+	// Product unit equals to unit
+	Unit UnitOfMeasure `protobuf:"varint,9,opt,name=unit,proto3,enum=v1.UnitOfMeasure" json:"unit,omitempty"`
+	// Product category is category_id or any of its subcategories
+	CategoryId           int64    `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("product-service.proto", fileDescriptor_44eb248bc1c5c9a9) }
-
-var fileDescriptor_44eb248bc1c5c9a9 = []byte{
-	// 471 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xc1, 0x8e, 0xd3, 0x30,
-	0x10, 0x55, 0xd2, 0x6e, 0xd2, 0x9d, 0x6e, 0xb3, 0xc5, 0x80, 0x64, 0xe5, 0x42, 0x94, 0x53, 0x41,
-	0x90, 0xa8, 0xe1, 0xba, 0x17, 0x04, 0x47, 0x56, 0x5a, 0x02, 0x7c, 0x40, 0x36, 0x19, 0x2a, 0x4b,
-	0x6d, 0x1d, 0x12, 0xa7, 0x12, 0x57, 0xbe, 0x95, 0x0f, 0x41, 0xf6, 0xc4, 0xdd, 0x36, 0xda, 0xac,
-	0x84, 0xc4, 0xcd, 0x33, 0x7e, 0x6f, 0xe6, 0xcd, 0x1b, 0x1b, 0x5e, 0xd6, 0x8d, 0xac, 0xba, 0x52,
-	0xbd, 0x6b, 0xb1, 0x39, 0x88, 0x12, 0x93, 0xba, 0x91, 0x4a, 0x32, 0xf7, 0xb0, 0x0e, 0x5f, 0x6d,
-	0xa4, 0xdc, 0x6c, 0x31, 0x35, 0x99, 0xfb, 0xee, 0x47, 0xaa, 0xc4, 0x0e, 0x5b, 0x55, 0xec, 0x6a,
-	0x02, 0xc5, 0x7f, 0x1c, 0xb8, 0xba, 0x23, 0xfa, 0x9d, 0x61, 0x05, 0xe0, 0x8a, 0x8a, 0x3b, 0x91,
-	0xb3, 0x9a, 0xe4, 0xae, 0xa8, 0x18, 0x83, 0xe9, 0xbe, 0xd8, 0x21, 0x77, 0x23, 0x67, 0x75, 0x99,
-	0x9b, 0x33, 0x7b, 0x01, 0x17, 0x75, 0x23, 0x4a, 0xe4, 0x13, 0x93, 0xa4, 0x80, 0x71, 0xf0, 0xcb,
-	0x06, 0x0b, 0x25, 0x1b, 0x3e, 0x35, 0x79, 0x1b, 0xea, 0x1a, 0xdd, 0x5e, 0x28, 0x7e, 0x41, 0x35,
-	0xf4, 0x99, 0x45, 0x30, 0xaf, 0xb0, 0x2d, 0x1b, 0x51, 0x2b, 0x21, 0xf7, 0xdc, 0x33, 0x57, 0xa7,
-	0x29, 0x16, 0xc2, 0xac, 0x2c, 0x14, 0x6e, 0x64, 0xf3, 0x8b, 0xfb, 0xe6, 0xfa, 0x18, 0xb3, 0x04,
-	0xa6, 0x55, 0xa1, 0x90, 0xcf, 0x22, 0x67, 0x35, 0xcf, 0xc2, 0x84, 0xc6, 0x4c, 0xec, 0x98, 0xc9,
-	0x37, 0x3b, 0x66, 0x6e, 0x70, 0xf1, 0x2d, 0x2c, 0x3e, 0x6a, 0x31, 0x98, 0xe3, 0xcf, 0x0e, 0x5b,
-	0xc5, 0x96, 0x30, 0x29, 0x6a, 0x61, 0xe6, 0xbc, 0xcc, 0xf5, 0x91, 0xbd, 0x01, 0xbf, 0xf7, 0xd1,
-	0xcc, 0x3a, 0xcf, 0x96, 0xc9, 0x61, 0x9d, 0x9c, 0x7a, 0x93, 0x5b, 0x40, 0x9c, 0x41, 0x60, 0xcb,
-	0xb5, 0xb5, 0xdc, 0xb7, 0xf8, 0x48, 0x3d, 0x32, 0xd2, 0xb5, 0x46, 0xc6, 0x29, 0xcc, 0x73, 0x2c,
-	0xaa, 0x71, 0x01, 0x43, 0xc2, 0x67, 0xb8, 0x22, 0xc2, 0x68, 0x8b, 0x7f, 0x91, 0x7c, 0x0b, 0x8b,
-	0xef, 0x75, 0xf5, 0xdf, 0x1c, 0xb8, 0x81, 0xc0, 0x96, 0x1b, 0x95, 0xc7, 0xc1, 0xef, 0x0c, 0xc6,
-	0x4e, 0x65, 0xc3, 0x78, 0x0d, 0x8b, 0x4f, 0xb8, 0xc5, 0xa7, 0xc4, 0x0c, 0xdd, 0xb8, 0x81, 0xc0,
-	0x52, 0x9e, 0x6a, 0x58, 0x19, 0xcc, 0xb1, 0x61, 0x1f, 0xc6, 0x31, 0x04, 0xda, 0xcb, 0x0f, 0xdb,
-	0xed, 0x68, 0xc7, 0xf8, 0x0b, 0x5c, 0x1f, 0x31, 0xa3, 0x2d, 0xde, 0xc2, 0xac, 0xb7, 0xa0, 0xe5,
-	0x6e, 0x34, 0x79, 0xd4, 0xa4, 0x23, 0x22, 0xfb, 0xed, 0x42, 0xd0, 0x5f, 0x7d, 0xa5, 0xbf, 0xc9,
-	0x52, 0xf0, 0xe8, 0xe9, 0xb0, 0x67, 0x9a, 0x78, 0xf6, 0x2a, 0x43, 0x76, 0x9a, 0xea, 0x35, 0xbc,
-	0x86, 0xa9, 0x96, 0xc5, 0xae, 0xf5, 0xdd, 0xc9, 0x0b, 0x0a, 0x97, 0x0f, 0x89, 0x1e, 0x9a, 0x82,
-	0x47, 0x4b, 0xa1, 0xda, 0x67, 0xfb, 0xa6, 0xda, 0x83, 0x9d, 0xa5, 0xe0, 0x91, 0xa9, 0x44, 0x38,
-	0xdb, 0x09, 0x11, 0x06, 0x9e, 0x67, 0xe0, 0xf7, 0x1e, 0x31, 0x66, 0xdb, 0x3f, 0x98, 0x1a, 0x3e,
-	0x3f, 0xcb, 0x11, 0xe7, 0xde, 0x33, 0xbf, 0xf2, 0xfd, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x53,
-	0x2e, 0x93, 0xf6, 0xa7, 0x04, 0x00, 0x00,
+func (m *ProductFilter) Reset()         { *m = ProductFilter{} }
+func (m *ProductFilter) String() string { return proto.CompactTextString(m) }
+func (*ProductFilter) ProtoMessage()    {}
+func (*ProductFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{12}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *ProductFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductFilter.Unmarshal(m, b)
+}
+func (m *ProductFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductFilter.Marshal(b, m, deterministic)
+}
+func (m *ProductFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductFilter.Merge(m, src)
+}
+func (m *ProductFilter) XXX_Size() int {
+	return xxx_messageInfo_ProductFilter.Size(m)
+}
+func (m *ProductFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductFilter.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_ProductFilter proto.InternalMessageInfo
 
-// ProductServiceClient is the client API for ProductService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ProductServiceClient interface {
-	// Create new todo task
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
-	// Read todo task
-	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
-	// Update todo task
-	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
-	// Delete todo task
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// Read all todo tasks
-	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error)
+func (m *ProductFilter) GetCreator() string {
+	if m != nil {
+		return m.Creator
+	}
+	return ""
 }
 
-type productServiceClient struct {
-	cc *grpc.ClientConn
+func (m *ProductFilter) GetDateFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.DateFrom
+	}
+	return nil
 }
 
-func NewProductServiceClient(cc *grpc.ClientConn) ProductServiceClient {
-	return &productServiceClient{cc}
+func (m *ProductFilter) GetDateTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.DateTo
+	}
+	return nil
 }
 
-func (c *productServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
-	out := new(CreateResponse)
-	err := c.cc.Invoke(ctx, "/v1.ProductService/Create", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ProductFilter) GetNamePrefix() string {
+	if m != nil {
+		return m.NamePrefix
 	}
-	return out, nil
+	return ""
 }
 
-func (c *productServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
+func (m *ProductFilter) GetPriceFrom() *Money {
+	if m != nil {
+		return m.PriceFrom
+	}
+	return nil
+}
+
+func (m *ProductFilter) GetPriceTo() *Money {
+	if m != nil {
+		return m.PriceTo
+	}
+	return nil
+}
+
+func (m *ProductFilter) GetUnit() UnitOfMeasure {
+	if m != nil {
+		return m.Unit
+	}
+	return UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED
+}
+
+func (m *ProductFilter) GetCategoryId() int64 {
+	if m != nil {
+		return m.CategoryId
+	}
+	return 0
+}
+
+// Request data to search products by text
+type SearchRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Words to search in product name, description and category, e.g. "organic potato".
+	// Products containing any of the words are found, products containing more of them rank higher.
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of results to return, 0 means server default
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque token returned as next_page_token by the previous call.
+	// Pages are selected by position of the results, so products written between the calls can be skipped
+	// or returned again, and page has fewer results than page_size if its products are deleted meanwhile.
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
+func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchRequest) ProtoMessage()    {}
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{13}
+}
+
+func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchRequest.Unmarshal(m, b)
+}
+func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
+}
+func (m *SearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchRequest.Merge(m, src)
+}
+func (m *SearchRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchRequest.Size(m)
+}
+func (m *SearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
+
+func (m *SearchRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// Snippet of product field with matched words wrapped in <em></em>, the rest of the text is HTML escaped
+type Highlight struct {
+	// Name of the field, e.g. "description", or "category" for name of product category
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Snippet              string   `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Highlight) Reset()         { *m = Highlight{} }
+func (m *Highlight) String() string { return proto.CompactTextString(m) }
+func (*Highlight) ProtoMessage()    {}
+func (*Highlight) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{14}
+}
+
+func (m *Highlight) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Highlight.Unmarshal(m, b)
+}
+func (m *Highlight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Highlight.Marshal(b, m, deterministic)
+}
+func (m *Highlight) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Highlight.Merge(m, src)
+}
+func (m *Highlight) XXX_Size() int {
+	return xxx_messageInfo_Highlight.Size(m)
+}
+func (m *Highlight) XXX_DiscardUnknown() {
+	xxx_messageInfo_Highlight.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Highlight proto.InternalMessageInfo
+
+func (m *Highlight) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *Highlight) GetSnippet() string {
+	if m != nil {
+		return m.Snippet
+	}
+	return ""
+}
+
+// Product found by search
+type SearchResult struct {
+	Product *ProductProto `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Relevance of the product, it is comparable between results of the same query only
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// Snippets of matched name and description
+	Highlights           []*Highlight `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SearchResult) Reset()         { *m = SearchResult{} }
+func (m *SearchResult) String() string { return proto.CompactTextString(m) }
+func (*SearchResult) ProtoMessage()    {}
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{15}
+}
+
+func (m *SearchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResult.Unmarshal(m, b)
+}
+func (m *SearchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResult.Marshal(b, m, deterministic)
+}
+func (m *SearchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResult.Merge(m, src)
+}
+func (m *SearchResult) XXX_Size() int {
+	return xxx_messageInfo_SearchResult.Size(m)
+}
+func (m *SearchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResult proto.InternalMessageInfo
+
+func (m *SearchResult) GetProduct() *ProductProto {
+	if m != nil {
+		return m.Product
+	}
+	return nil
+}
+
+func (m *SearchResult) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *SearchResult) GetHighlights() []*Highlight {
+	if m != nil {
+		return m.Highlights
+	}
+	return nil
+}
+
+// Contains found products ordered by relevance
+type SearchResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api     string          `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Results []*SearchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	// Token to retrieve the next page, empty if this is the last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Total number of found products
+	TotalSize            int64    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
+func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse) ProtoMessage()    {}
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{16}
+}
+
+func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse.Unmarshal(m, b)
+}
+func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse.Merge(m, src)
+}
+func (m *SearchResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse.Size(m)
+}
+func (m *SearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+
+func (m *SearchResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *SearchResponse) GetResults() []*SearchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *SearchResponse) GetTotalSize() int64 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+// Request data to read all todo task
+type ReadAllRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Maximum number of products to return, 0 means server default
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque token returned as next_page_token by the previous call
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only products matching the filter are returned
+	Filter *ProductFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Sort order in format "<field> [asc|desc]", e.g. "date desc"
+	// Supported fields: id (default), name, date, price
+	// Prices are sorted by currency code first, amounts of different currencies aren't compared
+	OrderBy string `protobuf:"bytes,5,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// Normalizes prices to price per the unit, e.g. price per kilogram for products sold in grams,
+	// so that products can be compared, see ProductProto.unit_price
+	PricePer UnitOfMeasure `protobuf:"varint,6,opt,name=price_per,json=pricePer,proto3,enum=v1.UnitOfMeasure" json:"price_per,omitempty"`
+	// Lists deleted products too, they have delete_time set
+	ShowDeleted          bool     `protobuf:"varint,7,opt,name=show_deleted,json=showDeleted,proto3" json:"show_deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadAllRequest) Reset()         { *m = ReadAllRequest{} }
+func (m *ReadAllRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadAllRequest) ProtoMessage()    {}
+func (*ReadAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{17}
+}
+
+func (m *ReadAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadAllRequest.Unmarshal(m, b)
+}
+func (m *ReadAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadAllRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadAllRequest.Merge(m, src)
+}
+func (m *ReadAllRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadAllRequest.Size(m)
+}
+func (m *ReadAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadAllRequest proto.InternalMessageInfo
+
+func (m *ReadAllRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadAllRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ReadAllRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ReadAllRequest) GetFilter() *ProductFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ReadAllRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+func (m *ReadAllRequest) GetPricePer() UnitOfMeasure {
+	if m != nil {
+		return m.PricePer
+	}
+	return UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED
+}
+
+func (m *ReadAllRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
+// Contains list of all todo tasks
+type ReadAllResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api      string          `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Products []*ProductProto `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	// Token to retrieve the next page, empty if this is the last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Total number of products
+	TotalSize            int64    `protobuf:"varint,4,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadAllResponse) Reset()         { *m = ReadAllResponse{} }
+func (m *ReadAllResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadAllResponse) ProtoMessage()    {}
+func (*ReadAllResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{18}
+}
+
+func (m *ReadAllResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadAllResponse.Unmarshal(m, b)
+}
+func (m *ReadAllResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadAllResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadAllResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadAllResponse.Merge(m, src)
+}
+func (m *ReadAllResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadAllResponse.Size(m)
+}
+func (m *ReadAllResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadAllResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadAllResponse proto.InternalMessageInfo
+
+func (m *ReadAllResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadAllResponse) GetProducts() []*ProductProto {
+	if m != nil {
+		return m.Products
+	}
+	return nil
+}
+
+func (m *ReadAllResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ReadAllResponse) GetTotalSize() int64 {
+	if m != nil {
+		return m.TotalSize
+	}
+	return 0
+}
+
+// Request data to stream all products
+type StreamProductsRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Only products matching the filter are streamed
+	Filter *ProductFilter `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Sort order in format "<field> [asc|desc]", the same as in ReadAllRequest
+	OrderBy              string   `protobuf:"bytes,3,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamProductsRequest) Reset()         { *m = StreamProductsRequest{} }
+func (m *StreamProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamProductsRequest) ProtoMessage()    {}
+func (*StreamProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{19}
+}
+
+func (m *StreamProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamProductsRequest.Unmarshal(m, b)
+}
+func (m *StreamProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamProductsRequest.Merge(m, src)
+}
+func (m *StreamProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamProductsRequest.Size(m)
+}
+func (m *StreamProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamProductsRequest proto.InternalMessageInfo
+
+func (m *StreamProductsRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *StreamProductsRequest) GetFilter() *ProductFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *StreamProductsRequest) GetOrderBy() string {
+	if m != nil {
+		return m.OrderBy
+	}
+	return ""
+}
+
+// Contains one of the streamed products
+type StreamProductsResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api                  string        `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Product              *ProductProto `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *StreamProductsResponse) Reset()         { *m = StreamProductsResponse{} }
+func (m *StreamProductsResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamProductsResponse) ProtoMessage()    {}
+func (*StreamProductsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{20}
+}
+
+func (m *StreamProductsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamProductsResponse.Unmarshal(m, b)
+}
+func (m *StreamProductsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamProductsResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamProductsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamProductsResponse.Merge(m, src)
+}
+func (m *StreamProductsResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamProductsResponse.Size(m)
+}
+func (m *StreamProductsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamProductsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamProductsResponse proto.InternalMessageInfo
+
+func (m *StreamProductsResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *StreamProductsResponse) GetProduct() *ProductProto {
+	if m != nil {
+		return m.Product
+	}
+	return nil
+}
+
+// Request data to create several products in one transaction
+type BatchCreateRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api      string           `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Requests []*CreateRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+	// If false all products are created or none,
+	// if true products which can't be created are skipped and reported in statuses
+	BestEffort           bool     `protobuf:"varint,3,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchCreateRequest) Reset()         { *m = BatchCreateRequest{} }
+func (m *BatchCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateRequest) ProtoMessage()    {}
+func (*BatchCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{21}
+}
+
+func (m *BatchCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateRequest.Unmarshal(m, b)
+}
+func (m *BatchCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateRequest.Merge(m, src)
+}
+func (m *BatchCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateRequest.Size(m)
+}
+func (m *BatchCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateRequest proto.InternalMessageInfo
+
+func (m *BatchCreateRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchCreateRequest) GetRequests() []*CreateRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchCreateRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+// Contains results of batch create operation in order of requests
+type BatchCreateResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Empty response for every request which failed
+	Responses            []*CreateResponse `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+	Statuses             []*status.Status  `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BatchCreateResponse) Reset()         { *m = BatchCreateResponse{} }
+func (m *BatchCreateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateResponse) ProtoMessage()    {}
+func (*BatchCreateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{22}
+}
+
+func (m *BatchCreateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateResponse.Unmarshal(m, b)
+}
+func (m *BatchCreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateResponse.Merge(m, src)
+}
+func (m *BatchCreateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateResponse.Size(m)
+}
+func (m *BatchCreateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateResponse proto.InternalMessageInfo
+
+func (m *BatchCreateResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchCreateResponse) GetResponses() []*CreateResponse {
+	if m != nil {
+		return m.Responses
+	}
+	return nil
+}
+
+func (m *BatchCreateResponse) GetStatuses() []*status.Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+// Request data to read several products in one transaction
+type BatchReadRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api      string         `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Requests []*ReadRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+	// If false the batch fails if any product can't be read,
+	// if true products which can't be read are reported in statuses
+	BestEffort           bool     `protobuf:"varint,3,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchReadRequest) Reset()         { *m = BatchReadRequest{} }
+func (m *BatchReadRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchReadRequest) ProtoMessage()    {}
+func (*BatchReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{23}
+}
+
+func (m *BatchReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchReadRequest.Unmarshal(m, b)
+}
+func (m *BatchReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchReadRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchReadRequest.Merge(m, src)
+}
+func (m *BatchReadRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchReadRequest.Size(m)
+}
+func (m *BatchReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchReadRequest proto.InternalMessageInfo
+
+func (m *BatchReadRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchReadRequest) GetRequests() []*ReadRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchReadRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+// Contains results of batch read operation in order of requests
+type BatchReadResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Empty response for every request which failed
+	Responses            []*ReadResponse  `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+	Statuses             []*status.Status `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *BatchReadResponse) Reset()         { *m = BatchReadResponse{} }
+func (m *BatchReadResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchReadResponse) ProtoMessage()    {}
+func (*BatchReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{24}
+}
+
+func (m *BatchReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchReadResponse.Unmarshal(m, b)
+}
+func (m *BatchReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchReadResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchReadResponse.Merge(m, src)
+}
+func (m *BatchReadResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchReadResponse.Size(m)
+}
+func (m *BatchReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchReadResponse proto.InternalMessageInfo
+
+func (m *BatchReadResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchReadResponse) GetResponses() []*ReadResponse {
+	if m != nil {
+		return m.Responses
+	}
+	return nil
+}
+
+func (m *BatchReadResponse) GetStatuses() []*status.Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+// Request data to update several products in one transaction
+type BatchUpdateRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api      string           `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Requests []*UpdateRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+	// If false all products are updated or none,
+	// if true products which can't be updated are skipped and reported in statuses
+	BestEffort           bool     `protobuf:"varint,3,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchUpdateRequest) Reset()         { *m = BatchUpdateRequest{} }
+func (m *BatchUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateRequest) ProtoMessage()    {}
+func (*BatchUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{25}
+}
+
+func (m *BatchUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateRequest.Unmarshal(m, b)
+}
+func (m *BatchUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateRequest.Merge(m, src)
+}
+func (m *BatchUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateRequest.Size(m)
+}
+func (m *BatchUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateRequest proto.InternalMessageInfo
+
+func (m *BatchUpdateRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchUpdateRequest) GetRequests() []*UpdateRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchUpdateRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+// Contains results of batch update operation in order of requests
+type BatchUpdateResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Empty response for every request which failed
+	Responses            []*UpdateResponse `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+	Statuses             []*status.Status  `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BatchUpdateResponse) Reset()         { *m = BatchUpdateResponse{} }
+func (m *BatchUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateResponse) ProtoMessage()    {}
+func (*BatchUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{26}
+}
+
+func (m *BatchUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateResponse.Unmarshal(m, b)
+}
+func (m *BatchUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateResponse.Merge(m, src)
+}
+func (m *BatchUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateResponse.Size(m)
+}
+func (m *BatchUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateResponse proto.InternalMessageInfo
+
+func (m *BatchUpdateResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchUpdateResponse) GetResponses() []*UpdateResponse {
+	if m != nil {
+		return m.Responses
+	}
+	return nil
+}
+
+func (m *BatchUpdateResponse) GetStatuses() []*status.Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+// Request data to delete several products in one transaction
+type BatchDeleteRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api      string           `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Requests []*DeleteRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+	// If false all products are deleted or none,
+	// if true products which can't be deleted are skipped and reported in statuses
+	BestEffort           bool     `protobuf:"varint,3,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteRequest) Reset()         { *m = BatchDeleteRequest{} }
+func (m *BatchDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteRequest) ProtoMessage()    {}
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{27}
+}
+
+func (m *BatchDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteRequest.Unmarshal(m, b)
+}
+func (m *BatchDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteRequest.Merge(m, src)
+}
+func (m *BatchDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteRequest.Size(m)
+}
+func (m *BatchDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteRequest proto.InternalMessageInfo
+
+func (m *BatchDeleteRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchDeleteRequest) GetRequests() []*DeleteRequest {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchDeleteRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+// Contains results of batch delete operation in order of requests
+type BatchDeleteResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Empty response for every request which failed
+	Responses            []*DeleteResponse `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+	Statuses             []*status.Status  `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BatchDeleteResponse) Reset()         { *m = BatchDeleteResponse{} }
+func (m *BatchDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteResponse) ProtoMessage()    {}
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{28}
+}
+
+func (m *BatchDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteResponse.Unmarshal(m, b)
+}
+func (m *BatchDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteResponse.Merge(m, src)
+}
+func (m *BatchDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteResponse.Size(m)
+}
+func (m *BatchDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteResponse proto.InternalMessageInfo
+
+func (m *BatchDeleteResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchDeleteResponse) GetResponses() []*DeleteResponse {
+	if m != nil {
+		return m.Responses
+	}
+	return nil
+}
+
+func (m *BatchDeleteResponse) GetStatuses() []*status.Status {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+// Request data to watch changes of products
+type WatchRequest struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api string `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	// Resume token of the last event received before reconnect,
+	// empty to watch changes made from now on
+	ResumeToken          string   `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{29}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+// Contains one change of a product
+type WatchResponse struct {
+	// API versioning: it is my best practice to specify version explicitly
+	Api  string     `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Type ChangeType `protobuf:"varint,2,opt,name=type,proto3,enum=v1.ChangeType" json:"type,omitempty"`
+	// Product state after the change, only id and revision are set for deleted product.
+	// Changes of a product come in revision order, a change arriving late is skipped.
+	Product *ProductProto `protobuf:"bytes,3,opt,name=product,proto3" json:"product,omitempty"`
+	// Token to resume watching after this event
+	ResumeToken          string   `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44eb248bc1c5c9a9, []int{30}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *WatchResponse) GetType() ChangeType {
+	if m != nil {
+		return m.Type
+	}
+	return ChangeType_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (m *WatchResponse) GetProduct() *ProductProto {
+	if m != nil {
+		return m.Product
+	}
+	return nil
+}
+
+func (m *WatchResponse) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("v1.UnitOfMeasure", UnitOfMeasure_name, UnitOfMeasure_value)
+	proto.RegisterEnum("v1.ChangeType", ChangeType_name, ChangeType_value)
+	proto.RegisterType((*Money)(nil), "v1.Money")
+	proto.RegisterType((*ProductProto)(nil), "v1.ProductProto")
+	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
+	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
+	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
+	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
+	proto.RegisterType((*UndeleteRequest)(nil), "v1.UndeleteRequest")
+	proto.RegisterType((*UndeleteResponse)(nil), "v1.UndeleteResponse")
+	proto.RegisterType((*ProductFilter)(nil), "v1.ProductFilter")
+	proto.RegisterType((*SearchRequest)(nil), "v1.SearchRequest")
+	proto.RegisterType((*Highlight)(nil), "v1.Highlight")
+	proto.RegisterType((*SearchResult)(nil), "v1.SearchResult")
+	proto.RegisterType((*SearchResponse)(nil), "v1.SearchResponse")
+	proto.RegisterType((*ReadAllRequest)(nil), "v1.ReadAllRequest")
+	proto.RegisterType((*ReadAllResponse)(nil), "v1.ReadAllResponse")
+	proto.RegisterType((*StreamProductsRequest)(nil), "v1.StreamProductsRequest")
+	proto.RegisterType((*StreamProductsResponse)(nil), "v1.StreamProductsResponse")
+	proto.RegisterType((*BatchCreateRequest)(nil), "v1.BatchCreateRequest")
+	proto.RegisterType((*BatchCreateResponse)(nil), "v1.BatchCreateResponse")
+	proto.RegisterType((*BatchReadRequest)(nil), "v1.BatchReadRequest")
+	proto.RegisterType((*BatchReadResponse)(nil), "v1.BatchReadResponse")
+	proto.RegisterType((*BatchUpdateRequest)(nil), "v1.BatchUpdateRequest")
+	proto.RegisterType((*BatchUpdateResponse)(nil), "v1.BatchUpdateResponse")
+	proto.RegisterType((*BatchDeleteRequest)(nil), "v1.BatchDeleteRequest")
+	proto.RegisterType((*BatchDeleteResponse)(nil), "v1.BatchDeleteResponse")
+	proto.RegisterType((*WatchRequest)(nil), "v1.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "v1.WatchResponse")
+}
+
+func init() { proto.RegisterFile("product-service.proto", fileDescriptor_44eb248bc1c5c9a9) }
+
+var fileDescriptor_44eb248bc1c5c9a9 = []byte{
+	// 1807 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xdb, 0x6f, 0xe3, 0x58,
+	0x19, 0xc7, 0xb1, 0x93, 0x38, 0x5f, 0x2e, 0x75, 0xcf, 0x74, 0xb6, 0x9e, 0x0c, 0x68, 0xba, 0x66,
+	0x41, 0xa5, 0xbb, 0x4d, 0x76, 0x32, 0x42, 0xa0, 0x19, 0x04, 0xea, 0xb4, 0xe9, 0x4e, 0x4a, 0xdb,
+	0x89, 0xdc, 0x94, 0xab, 0x84, 0xe5, 0xb1, 0x4f, 0x53, 0x33, 0xa9, 0xed, 0xb5, 0x9d, 0xee, 0x74,
+	0xd1, 0x0a, 0x09, 0x89, 0x07, 0x5e, 0xb9, 0x48, 0x3c, 0xf0, 0xc8, 0x7f, 0x84, 0xc4, 0x13, 0x8f,
+	0xfc, 0x01, 0xbc, 0xf1, 0x8a, 0xce, 0xcd, 0xb1, 0x93, 0xb8, 0xa1, 0xbb, 0x7d, 0xaa, 0xbf, 0xef,
+	0x7c, 0xf7, 0xcb, 0xef, 0x9c, 0x14, 0x1e, 0x86, 0x51, 0xe0, 0x4e, 0x9d, 0x64, 0x37, 0xc6, 0xd1,
+	0xb5, 0xe7, 0xe0, 0x4e, 0x18, 0x05, 0x49, 0x80, 0x4a, 0xd7, 0x4f, 0xdb, 0x4f, 0xc6, 0x41, 0x30,
+	0x9e, 0xe0, 0x2e, 0xe5, 0xbc, 0x99, 0x5e, 0x74, 0x13, 0xef, 0x0a, 0xc7, 0x89, 0x7d, 0x15, 0x32,
+	0xa1, 0xf6, 0xd6, 0xbc, 0xc0, 0x85, 0x87, 0x27, 0xae, 0x75, 0x65, 0xc7, 0x6f, 0xb9, 0xc4, 0x26,
+	0x97, 0x88, 0x42, 0xa7, 0x1b, 0x27, 0x76, 0x32, 0x8d, 0xf9, 0xc1, 0xd7, 0xf9, 0x81, 0x1d, 0x7a,
+	0x5d, 0xdb, 0xf7, 0x83, 0xc4, 0x4e, 0xbc, 0xc0, 0x17, 0xa7, 0x1f, 0xd1, 0x3f, 0xce, 0xee, 0x18,
+	0xfb, 0xbb, 0xf1, 0x67, 0xf6, 0x78, 0x8c, 0xa3, 0x6e, 0x10, 0x52, 0x89, 0x45, 0x69, 0xe3, 0x67,
+	0x50, 0x3e, 0x09, 0x7c, 0x7c, 0x83, 0xbe, 0x09, 0x4d, 0x67, 0x1a, 0x45, 0xd8, 0x77, 0x6e, 0x2c,
+	0x27, 0x70, 0xb1, 0x2e, 0x6d, 0x49, 0xdb, 0x35, 0xb3, 0x21, 0x98, 0xfb, 0x81, 0x8b, 0xd1, 0x06,
+	0x94, 0xa7, 0xbe, 0x97, 0xc4, 0x7a, 0x69, 0x4b, 0xda, 0x96, 0x4d, 0x46, 0x10, 0xae, 0x6f, 0xfb,
+	0x41, 0xac, 0xcb, 0x5b, 0xd2, 0x76, 0xd9, 0x64, 0x84, 0xf1, 0x37, 0x19, 0x1a, 0x43, 0x56, 0x9f,
+	0x21, 0x2d, 0x4b, 0x0b, 0x4a, 0x9e, 0x4b, 0xcd, 0xca, 0x66, 0xc9, 0x73, 0x11, 0x02, 0xc5, 0xb7,
+	0xaf, 0x30, 0xb5, 0x55, 0x33, 0xe9, 0x37, 0x7a, 0x02, 0xe5, 0x30, 0xf2, 0x1c, 0xac, 0xc3, 0x96,
+	0xb4, 0x5d, 0xef, 0xd5, 0x3a, 0xd7, 0x4f, 0x3b, 0x34, 0x3e, 0x93, 0xf1, 0x91, 0x0e, 0x55, 0x27,
+	0xc2, 0x76, 0x12, 0x44, 0xba, 0x42, 0xf5, 0x04, 0x89, 0xbe, 0x05, 0x0a, 0x09, 0x47, 0xaf, 0x6f,
+	0x49, 0xdb, 0xad, 0xde, 0x3a, 0xd1, 0x3c, 0xf7, 0xbd, 0xe4, 0xf5, 0xc5, 0x09, 0xb6, 0xe3, 0x69,
+	0x84, 0x4d, 0x7a, 0x8c, 0xb6, 0xa0, 0xee, 0xe2, 0xd8, 0x89, 0x3c, 0x5a, 0x12, 0xbd, 0x42, 0x8d,
+	0x64, 0x59, 0xe8, 0x09, 0xd4, 0x1d, 0x3b, 0xc1, 0xe3, 0x20, 0xba, 0xb1, 0x3c, 0x57, 0x6f, 0xd2,
+	0x80, 0x41, 0xb0, 0x06, 0x2e, 0xea, 0x80, 0xe2, 0xda, 0x09, 0xd6, 0x55, 0x1a, 0x63, 0xbb, 0xc3,
+	0xda, 0xd1, 0x11, 0x9d, 0xec, 0x8c, 0x44, 0xab, 0x4d, 0x2a, 0x87, 0xda, 0xa0, 0x46, 0xf8, 0xda,
+	0x8b, 0x89, 0xbf, 0x1a, 0xb5, 0x96, 0xd2, 0x68, 0x1b, 0x80, 0x84, 0x65, 0xb1, 0xac, 0x1b, 0xf3,
+	0x59, 0xd7, 0xc8, 0xe1, 0x90, 0x66, 0xfe, 0x82, 0x04, 0x3e, 0xc1, 0x09, 0xb6, 0xc8, 0x28, 0xe9,
+	0xad, 0x95, 0xce, 0x81, 0x89, 0x13, 0xc6, 0x91, 0xa2, 0xca, 0x9a, 0x72, 0xa4, 0xa8, 0x65, 0xad,
+	0x72, 0xa4, 0xa8, 0x55, 0x4d, 0x35, 0x26, 0xd0, 0xdc, 0x27, 0x95, 0xc3, 0x26, 0xfe, 0x74, 0x8a,
+	0xe3, 0x04, 0x69, 0x20, 0xdb, 0xa1, 0xc7, 0xdb, 0x4e, 0x3e, 0xd1, 0x0e, 0x54, 0xf9, 0x80, 0xd3,
+	0x1e, 0xd5, 0x7b, 0x1a, 0x09, 0x2c, 0xdb, 0x53, 0x53, 0x08, 0xa0, 0x6f, 0x00, 0x44, 0xcc, 0x10,
+	0xa9, 0x99, 0x4c, 0x8d, 0xd4, 0x38, 0x67, 0xe0, 0x1a, 0xa7, 0xd0, 0x12, 0xde, 0xe2, 0x30, 0xf0,
+	0x63, 0xbc, 0xc4, 0x1d, 0x9b, 0x8f, 0x52, 0x3a, 0x1f, 0xd9, 0xb2, 0xc9, 0xf9, 0xb2, 0x19, 0x5d,
+	0xa8, 0x9b, 0xd8, 0x76, 0x8b, 0x63, 0x9f, 0x33, 0x66, 0x1c, 0x43, 0x83, 0x29, 0x14, 0xba, 0xbf,
+	0x43, 0xb6, 0xc6, 0x3f, 0x25, 0x68, 0x9e, 0x87, 0xee, 0xbd, 0x55, 0xef, 0x05, 0xd4, 0xa7, 0xd4,
+	0x1c, 0xdd, 0x7f, 0x5d, 0x2e, 0xe8, 0xed, 0x21, 0x81, 0x88, 0x13, 0x3b, 0x7e, 0x6b, 0x02, 0x13,
+	0x27, 0xdf, 0xe8, 0x43, 0x58, 0xc7, 0xef, 0x42, 0xec, 0x24, 0xd8, 0xb5, 0xd2, 0x82, 0x29, 0x34,
+	0x73, 0x4d, 0x1c, 0x98, 0x9c, 0x3f, 0xd7, 0xa7, 0xf2, 0x7c, 0x9f, 0x7e, 0x00, 0x2d, 0x91, 0x57,
+	0x61, 0xa1, 0x74, 0xa8, 0x32, 0xef, 0xa2, 0xbe, 0x82, 0x34, 0x7e, 0x05, 0xcd, 0x03, 0x3a, 0x73,
+	0xff, 0x77, 0x5f, 0x96, 0x07, 0x2f, 0x2f, 0x0f, 0x9e, 0x44, 0x27, 0xec, 0xdf, 0x16, 0x1d, 0x9b,
+	0xfb, 0x34, 0x3a, 0x4e, 0x1a, 0xcf, 0x60, 0xed, 0xdc, 0x77, 0xef, 0x16, 0x9f, 0x31, 0x04, 0x6d,
+	0xa6, 0x74, 0x2f, 0xb3, 0xf3, 0xaf, 0x12, 0x34, 0xf9, 0xc9, 0xa1, 0x37, 0x49, 0x70, 0x94, 0xc5,
+	0xb4, 0x52, 0x1e, 0xd3, 0xbe, 0x07, 0x35, 0x3a, 0x15, 0x17, 0x51, 0x70, 0xa5, 0x2b, 0x05, 0x53,
+	0x31, 0xdb, 0x78, 0x95, 0x08, 0x1f, 0x46, 0xc1, 0x15, 0x7a, 0x06, 0x55, 0xaa, 0x98, 0x04, 0x7a,
+	0x79, 0xa5, 0x5a, 0x85, 0x88, 0x8e, 0x02, 0x02, 0x7c, 0x04, 0x84, 0xad, 0x30, 0xc2, 0x17, 0xde,
+	0x3b, 0x0e, 0x8d, 0x40, 0x58, 0x43, 0xca, 0x21, 0x60, 0x45, 0x71, 0x8a, 0xc5, 0x53, 0x5d, 0x00,
+	0x2b, 0x7a, 0x48, 0xfd, 0x7f, 0x00, 0x2a, 0x93, 0x4c, 0x02, 0x5d, 0x9d, 0x97, 0xab, 0xd2, 0xa3,
+	0x51, 0x90, 0x42, 0x76, 0xed, 0x76, 0xc8, 0x9e, 0x03, 0x64, 0x98, 0x07, 0xe4, 0x23, 0x45, 0x95,
+	0xb4, 0x12, 0xc3, 0x38, 0x23, 0x86, 0xe6, 0x19, 0xb6, 0x23, 0xe7, 0xb2, 0xb8, 0xc7, 0x1b, 0x50,
+	0xfe, 0x74, 0x8a, 0xa3, 0x1b, 0x5e, 0x6d, 0x46, 0xa0, 0xc7, 0x50, 0x0b, 0xed, 0x31, 0xb6, 0x62,
+	0xef, 0x73, 0xcc, 0x6f, 0x32, 0x95, 0x30, 0xce, 0xbc, 0xcf, 0x31, 0x59, 0x1b, 0x7a, 0x98, 0x04,
+	0x6f, 0xb1, 0xcf, 0x6f, 0x1e, 0x2a, 0x3e, 0x22, 0x0c, 0xe3, 0x05, 0xd4, 0x5e, 0x79, 0xe3, 0xcb,
+	0x89, 0x37, 0xbe, 0x4c, 0x88, 0x79, 0x7a, 0x97, 0x73, 0x97, 0x8c, 0x20, 0x4d, 0x8e, 0x7d, 0x2f,
+	0x0c, 0x71, 0x22, 0x9a, 0xcc, 0x49, 0xe3, 0xb7, 0xd0, 0x10, 0x11, 0xc7, 0xd3, 0x49, 0x92, 0x1d,
+	0x26, 0x69, 0x15, 0x70, 0x6c, 0x40, 0x39, 0x76, 0x82, 0x88, 0x5d, 0xa2, 0x92, 0xc9, 0x08, 0xb4,
+	0x0b, 0x70, 0x29, 0xc2, 0x21, 0xb7, 0xb2, 0xbc, 0x5d, 0xef, 0x35, 0x89, 0x91, 0x34, 0x48, 0x33,
+	0x23, 0x60, 0xfc, 0x45, 0x82, 0x56, 0x1a, 0xc1, 0x2d, 0x23, 0x1e, 0xd1, 0xf8, 0xc8, 0xe5, 0x2f,
+	0x8b, 0xa8, 0xb2, 0x81, 0x9b, 0x42, 0x00, 0x7d, 0x1b, 0xd6, 0x7c, 0xfc, 0x2e, 0xb1, 0x32, 0x25,
+	0x63, 0x37, 0x42, 0x93, 0xb0, 0x87, 0xa2, 0x6c, 0xa4, 0xaa, 0x49, 0x90, 0xd8, 0x13, 0x56, 0x73,
+	0x06, 0x59, 0x35, 0xca, 0x21, 0x45, 0x37, 0xfe, 0x2b, 0x41, 0x8b, 0x80, 0xf6, 0xde, 0x64, 0x52,
+	0xdc, 0xcc, 0x5c, 0xdb, 0x4a, 0xb7, 0xb6, 0x4d, 0x9e, 0x6b, 0x1b, 0xfa, 0x0e, 0x54, 0x2e, 0xe8,
+	0x0a, 0xf2, 0xdd, 0x5a, 0xcf, 0x14, 0x9a, 0xed, 0xa6, 0xc9, 0x05, 0xd0, 0x23, 0x50, 0x83, 0xc8,
+	0xc5, 0x91, 0xf5, 0xe6, 0x86, 0xa3, 0x66, 0x95, 0xd2, 0x2f, 0x6f, 0x50, 0x07, 0xd8, 0xe0, 0x5b,
+	0x21, 0x8e, 0xf4, 0x4a, 0xd1, 0x28, 0xb3, 0x7d, 0x18, 0xe2, 0x08, 0xbd, 0x0f, 0x8d, 0xf8, 0x32,
+	0xf8, 0xcc, 0x12, 0x30, 0x45, 0xf6, 0x48, 0x35, 0xeb, 0x84, 0x77, 0xc0, 0xa1, 0xea, 0xaf, 0x12,
+	0xac, 0xa5, 0x99, 0x17, 0xb6, 0xe4, 0x23, 0xb2, 0x64, 0x34, 0xd8, 0x5c, 0x4f, 0x72, 0x93, 0x92,
+	0x4a, 0xdc, 0x57, 0x53, 0x02, 0x78, 0x78, 0x96, 0x44, 0xd8, 0xbe, 0xe2, 0x6e, 0xe2, 0xe2, 0xd6,
+	0xcc, 0xca, 0x5b, 0xba, 0x4b, 0x79, 0xe5, 0x5c, 0x79, 0x8d, 0x9f, 0xc0, 0x7b, 0xf3, 0x0e, 0xef,
+	0x05, 0x87, 0xaf, 0x01, 0xbd, 0xb4, 0x13, 0xe7, 0x72, 0xd5, 0x2b, 0x68, 0x97, 0x3c, 0x43, 0xe8,
+	0xa1, 0xa8, 0x32, 0xcd, 0x23, 0xa7, 0x66, 0xa6, 0x22, 0x04, 0xac, 0xde, 0x90, 0xdb, 0x15, 0x5f,
+	0x5c, 0x04, 0x51, 0x42, 0x93, 0x51, 0x4d, 0x20, 0xac, 0x3e, 0xe5, 0x18, 0x7f, 0x90, 0xe0, 0x41,
+	0xce, 0x71, 0x61, 0x36, 0x1f, 0x43, 0x2d, 0xe2, 0xa7, 0xc2, 0x35, 0xca, 0xba, 0x66, 0x47, 0xe6,
+	0x4c, 0x08, 0x75, 0x40, 0x65, 0xbf, 0x14, 0xb0, 0x58, 0x7b, 0x24, 0x70, 0x3f, 0x0a, 0x9d, 0xce,
+	0x19, 0x3d, 0x33, 0x53, 0x19, 0x23, 0x02, 0x8d, 0x86, 0x72, 0xfb, 0x5b, 0xea, 0xc3, 0x85, 0x0a,
+	0xac, 0x91, 0x30, 0x32, 0x4a, 0x77, 0xc9, 0xff, 0xf7, 0x12, 0xac, 0x67, 0x9c, 0x16, 0x66, 0xdf,
+	0x59, 0xcc, 0x5e, 0x9b, 0xb9, 0xfd, 0xea, 0xb9, 0x8b, 0xfe, 0xaf, 0x7a, 0xc7, 0x15, 0xf4, 0x3f,
+	0xa7, 0xf6, 0xa5, 0xfa, 0xbf, 0xf2, 0xa1, 0x55, 0xd4, 0xff, 0xbc, 0xe2, 0x7d, 0xd4, 0x60, 0xd5,
+	0xab, 0xad, 0xa0, 0x06, 0x39, 0xb5, 0x2f, 0x55, 0x83, 0x95, 0xcf, 0xb9, 0xa2, 0x1a, 0xe4, 0x15,
+	0xbf, 0x4a, 0x0d, 0xf6, 0xa1, 0xf1, 0x53, 0x36, 0x8e, 0x45, 0xd9, 0xbf, 0x0f, 0x0d, 0x72, 0xb3,
+	0x5d, 0x09, 0xd8, 0x64, 0xf7, 0x77, 0x9d, 0xf1, 0xd8, 0x03, 0xe0, 0xcf, 0x12, 0x34, 0xb9, 0x95,
+	0xc2, 0x54, 0x0c, 0x50, 0x92, 0x9b, 0x90, 0x5d, 0x52, 0xad, 0x5e, 0x8b, 0x6e, 0xf2, 0xa5, 0xed,
+	0x8f, 0xf1, 0xe8, 0x26, 0xc4, 0x26, 0x3d, 0xcb, 0x02, 0x98, 0xbc, 0xea, 0xee, 0x9f, 0x0f, 0x4b,
+	0x59, 0x08, 0x6b, 0xe7, 0xef, 0xe4, 0x77, 0x4a, 0xf6, 0x1a, 0x42, 0x4f, 0xe0, 0xf1, 0xf9, 0xe9,
+	0x60, 0x64, 0xbd, 0x3e, 0xb4, 0x4e, 0xfa, 0x7b, 0x67, 0xe7, 0x66, 0xdf, 0x3a, 0x3f, 0x3d, 0x1b,
+	0xf6, 0xf7, 0x07, 0x87, 0x83, 0xfe, 0x81, 0xf6, 0x35, 0xa4, 0x82, 0xf2, 0x89, 0xb9, 0x77, 0xa2,
+	0x49, 0xa8, 0x01, 0xea, 0x8f, 0x07, 0xc7, 0xaf, 0x29, 0x55, 0x42, 0x2d, 0x80, 0x93, 0xc1, 0xf1,
+	0xf1, 0xe0, 0x78, 0x30, 0xea, 0x9b, 0x9a, 0x8c, 0x6a, 0x50, 0x66, 0x9f, 0x0a, 0xf9, 0x1c, 0x0e,
+	0xfa, 0xfb, 0x7d, 0xad, 0x4c, 0x3e, 0x0f, 0x5e, 0xff, 0xa2, 0x7f, 0xaa, 0x55, 0x52, 0x85, 0x93,
+	0x3e, 0x91, 0xaa, 0x12, 0x7a, 0xbf, 0x7f, 0x3a, 0xe2, 0xb4, 0x4a, 0x44, 0xd9, 0x67, 0x6d, 0xc7,
+	0x02, 0x98, 0x55, 0x02, 0x3d, 0x86, 0xcd, 0xfd, 0x57, 0x7b, 0xa7, 0x9f, 0xf4, 0xad, 0xd1, 0xcf,
+	0x87, 0xf3, 0xe1, 0xd5, 0xa1, 0xba, 0x6f, 0xf6, 0xf7, 0x46, 0xfd, 0x03, 0x4d, 0x22, 0xc4, 0xf9,
+	0xf0, 0x80, 0x12, 0x25, 0x42, 0x1c, 0xf4, 0x8f, 0xfb, 0x84, 0x90, 0x51, 0x13, 0x6a, 0xe7, 0xa7,
+	0x82, 0x54, 0x7a, 0xff, 0xa9, 0x40, 0x8b, 0x17, 0xf1, 0x8c, 0xfd, 0xab, 0x06, 0xbd, 0x82, 0x0a,
+	0xc3, 0x51, 0xb4, 0x08, 0xe7, 0xed, 0x25, 0x30, 0x6b, 0x6c, 0xfe, 0xee, 0x1f, 0xff, 0xfe, 0x53,
+	0x69, 0xdd, 0x68, 0x74, 0xaf, 0x9f, 0x76, 0xc5, 0xad, 0xfa, 0x5c, 0xda, 0x41, 0x07, 0xa0, 0x10,
+	0x4c, 0x42, 0xf3, 0xa0, 0xd8, 0x5e, 0x80, 0x2b, 0xe3, 0x11, 0xb5, 0xf1, 0x00, 0xad, 0x67, 0x6d,
+	0x74, 0x7f, 0xe3, 0xb9, 0x5f, 0xa0, 0x5f, 0x42, 0x85, 0xed, 0x35, 0x5a, 0x84, 0x97, 0xf6, 0x92,
+	0xb5, 0x37, 0x76, 0xa8, 0xad, 0x0f, 0x7a, 0x8f, 0xf2, 0xb6, 0xf8, 0x57, 0xc7, 0x73, 0xbf, 0x78,
+	0x9e, 0x8e, 0xca, 0x11, 0x54, 0xd8, 0xc2, 0xa0, 0xc5, 0xbd, 0x6d, 0x2f, 0xd9, 0x27, 0x11, 0xe8,
+	0xce, 0x92, 0x40, 0xbf, 0x0b, 0xaa, 0xf8, 0x45, 0x84, 0x1e, 0xb0, 0x77, 0x4e, 0xee, 0x47, 0x55,
+	0x7b, 0x23, 0xcf, 0xe4, 0xfb, 0xf0, 0x0a, 0xaa, 0xfc, 0x45, 0x83, 0x90, 0xa8, 0xcb, 0xec, 0x61,
+	0xd7, 0x7e, 0x90, 0xe3, 0xf1, 0x28, 0x36, 0x68, 0x14, 0x2d, 0x94, 0x2b, 0x39, 0xea, 0x42, 0x85,
+	0x3d, 0x3b, 0x59, 0x32, 0xb9, 0xd7, 0x7e, 0x1b, 0x65, 0x59, 0xdc, 0xf5, 0x00, 0x5a, 0xf9, 0x17,
+	0x04, 0x7a, 0x44, 0xa5, 0x96, 0x3d, 0x63, 0xda, 0xed, 0x65, 0x47, 0xcc, 0xd0, 0xc7, 0x12, 0xea,
+	0x40, 0x99, 0xae, 0x39, 0xa2, 0xbd, 0xcd, 0xe2, 0x46, 0x7b, 0x3d, 0xc3, 0x49, 0xe5, 0x7f, 0x08,
+	0xf5, 0xcc, 0x5d, 0x8f, 0xde, 0x23, 0x32, 0x8b, 0xaf, 0x8e, 0xf6, 0xe6, 0x02, 0x9f, 0x87, 0xfe,
+	0x7d, 0xa8, 0xa5, 0x77, 0x25, 0xda, 0x48, 0xa5, 0xb2, 0x53, 0xf6, 0x70, 0x8e, 0xcb, 0x35, 0x85,
+	0x67, 0x3e, 0x54, 0x33, 0xcf, 0xf9, 0xc9, 0xda, 0x5c, 0xe0, 0xcf, 0xe9, 0xf3, 0xb9, 0x99, 0xe9,
+	0xe7, 0x87, 0x67, 0x73, 0x81, 0xcf, 0xf4, 0x5f, 0xbe, 0xfc, 0xe3, 0xde, 0x8f, 0xd0, 0x43, 0x58,
+	0xe3, 0x45, 0xdc, 0xe2, 0xff, 0x22, 0xed, 0x95, 0xae, 0x9f, 0xee, 0x94, 0x4a, 0x52, 0x4f, 0xb3,
+	0xc3, 0x70, 0xe2, 0x39, 0xf4, 0x9f, 0x91, 0xdd, 0x5f, 0xc7, 0x81, 0xff, 0x7c, 0x81, 0xf3, 0xa6,
+	0x42, 0x7f, 0xac, 0x3e, 0xfb, 0xdf, 0x00, 0xf7, 0x49, 0x58, 0x01, 0x68, 0x15, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProductServiceClient is the client API for ProductService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProductServiceClient interface {
+	// Create new todo task
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Read todo task
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// Update todo task
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	// Delete todo task, deleted product is kept until it is purged after retention period
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Restore deleted product which is not purged yet
+	Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error)
+	// Read all todo tasks
+	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error)
+	// Search products by text in order of relevance
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// Stream all products without buffering them on server
+	StreamProducts(ctx context.Context, in *StreamProductsRequest, opts ...grpc.CallOption) (ProductService_StreamProductsClient, error)
+	// Stream changes of products made by Create, Update, Delete and batch operations
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ProductService_WatchClient, error)
+	// Create several products in one transaction
+	BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error)
+	// Read several products in one transaction
+	BatchRead(ctx context.Context, in *BatchReadRequest, opts ...grpc.CallOption) (*BatchReadResponse, error)
+	// Update several products in one transaction
+	BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error)
+	// Delete several products in one transaction
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+}
+
+type productServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewProductServiceClient(cc *grpc.ClientConn) ProductServiceClient {
+	return &productServiceClient{cc}
+}
+
+func (c *productServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
 	out := new(ReadResponse)
 	err := c.cc.Invoke(ctx, "/v1.ProductService/Read", in, out, opts...)
 	if err != nil {
@@ -722,6 +2299,15 @@ func (c *productServiceClient) Delete(ctx context.Context, in *DeleteRequest, op
 	return out, nil
 }
 
+func (c *productServiceClient) Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error) {
+	out := new(UndeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/Undelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *productServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error) {
 	out := new(ReadAllResponse)
 	err := c.cc.Invoke(ctx, "/v1.ProductService/ReadAll", in, out, opts...)
@@ -731,6 +2317,115 @@ func (c *productServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest,
 	return out, nil
 }
 
+func (c *productServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) StreamProducts(ctx context.Context, in *StreamProductsRequest, opts ...grpc.CallOption) (ProductService_StreamProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ProductService_serviceDesc.Streams[0], "/v1.ProductService/StreamProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceStreamProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_StreamProductsClient interface {
+	Recv() (*StreamProductsResponse, error)
+	grpc.ClientStream
+}
+
+type productServiceStreamProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceStreamProductsClient) Recv() (*StreamProductsResponse, error) {
+	m := new(StreamProductsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *productServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ProductService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ProductService_serviceDesc.Streams[1], "/v1.ProductService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type productServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *productServiceClient) BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error) {
+	out := new(BatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BatchRead(ctx context.Context, in *BatchReadRequest, opts ...grpc.CallOption) (*BatchReadResponse, error) {
+	out := new(BatchReadResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/BatchRead", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error) {
+	out := new(BatchUpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ProductService/BatchDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 type ProductServiceServer interface {
 	// Create new todo task
@@ -739,10 +2434,26 @@ type ProductServiceServer interface {
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
 	// Update todo task
 	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
-	// Delete todo task
+	// Delete todo task, deleted product is kept until it is purged after retention period
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Restore deleted product which is not purged yet
+	Undelete(context.Context, *UndeleteRequest) (*UndeleteResponse, error)
 	// Read all todo tasks
 	ReadAll(context.Context, *ReadAllRequest) (*ReadAllResponse, error)
+	// Search products by text in order of relevance
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// Stream all products without buffering them on server
+	StreamProducts(*StreamProductsRequest, ProductService_StreamProductsServer) error
+	// Stream changes of products made by Create, Update, Delete and batch operations
+	Watch(*WatchRequest, ProductService_WatchServer) error
+	// Create several products in one transaction
+	BatchCreate(context.Context, *BatchCreateRequest) (*BatchCreateResponse, error)
+	// Read several products in one transaction
+	BatchRead(context.Context, *BatchReadRequest) (*BatchReadResponse, error)
+	// Update several products in one transaction
+	BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchUpdateResponse, error)
+	// Delete several products in one transaction
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
 }
 
 // UnimplementedProductServiceServer can be embedded to have forward compatible implementations.
@@ -750,19 +2461,43 @@ type UnimplementedProductServiceServer struct {
 }
 
 func (*UnimplementedProductServiceServer) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method Create not implemented")
 }
 func (*UnimplementedProductServiceServer) Read(ctx context.Context, req *ReadRequest) (*ReadResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method Read not implemented")
 }
 func (*UnimplementedProductServiceServer) Update(ctx context.Context, req *UpdateRequest) (*UpdateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method Update not implemented")
 }
 func (*UnimplementedProductServiceServer) Delete(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedProductServiceServer) Undelete(ctx context.Context, req *UndeleteRequest) (*UndeleteResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method Undelete not implemented")
 }
 func (*UnimplementedProductServiceServer) ReadAll(ctx context.Context, req *ReadAllRequest) (*ReadAllResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadAll not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method ReadAll not implemented")
+}
+func (*UnimplementedProductServiceServer) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (*UnimplementedProductServiceServer) StreamProducts(req *StreamProductsRequest, srv ProductService_StreamProductsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method StreamProducts not implemented")
+}
+func (*UnimplementedProductServiceServer) Watch(req *WatchRequest, srv ProductService_WatchServer) error {
+	return status1.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (*UnimplementedProductServiceServer) BatchCreate(ctx context.Context, req *BatchCreateRequest) (*BatchCreateResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (*UnimplementedProductServiceServer) BatchRead(ctx context.Context, req *BatchReadRequest) (*BatchReadResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchRead not implemented")
+}
+func (*UnimplementedProductServiceServer) BatchUpdate(ctx context.Context, req *BatchUpdateRequest) (*BatchUpdateResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchUpdate not implemented")
+}
+func (*UnimplementedProductServiceServer) BatchDelete(ctx context.Context, req *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
 }
 
 func RegisterProductServiceServer(s *grpc.Server, srv ProductServiceServer) {
@@ -841,6 +2576,24 @@ func _ProductService_Delete_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_Undelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Undelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/Undelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Undelete(ctx, req.(*UndeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProductService_ReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadAllRequest)
 	if err := dec(in); err != nil {
@@ -859,6 +2612,138 @@ func _ProductService_ReadAll_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_StreamProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).StreamProducts(m, &productServiceStreamProductsServer{stream})
+}
+
+type ProductService_StreamProductsServer interface {
+	Send(*StreamProductsResponse) error
+	grpc.ServerStream
+}
+
+type productServiceStreamProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *productServiceStreamProductsServer) Send(m *StreamProductsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ProductService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).Watch(m, &productServiceWatchServer{stream})
+}
+
+type ProductService_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type productServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *productServiceWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ProductService_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BatchCreate(ctx, req.(*BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_BatchRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BatchRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/BatchRead",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BatchRead(ctx, req.(*BatchReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BatchUpdate(ctx, req.(*BatchUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ProductService/BatchDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ProductService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.ProductService",
 	HandlerType: (*ProductServiceServer)(nil),
@@ -879,11 +2764,46 @@ var _ProductService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ProductService_Delete_Handler,
 		},
+		{
+			MethodName: "Undelete",
+			Handler:    _ProductService_Undelete_Handler,
+		},
 		{
 			MethodName: "ReadAll",
 			Handler:    _ProductService_ReadAll_Handler,
 		},
+		{
+			MethodName: "Search",
+			Handler:    _ProductService_Search_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _ProductService_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchRead",
+			Handler:    _ProductService_BatchRead_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _ProductService_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _ProductService_BatchDelete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamProducts",
+			Handler:       _ProductService_StreamProducts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _ProductService_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "product-service.proto",
 }
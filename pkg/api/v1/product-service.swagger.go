@@ -0,0 +1,966 @@
+// Code generated by gen-swagger.go. DO NOT EDIT.
+// source: api/swagger/v1/product-service.swagger.json
+
+package v1
+
+// ProductServiceSwagger is OpenAPI v2 document of Product service REST gateway
+const ProductServiceSwagger = `{
+  "swagger": "2.0",
+  "info": {
+    "title": "Product service",
+    "version": "v1"
+  },
+  "schemes": [
+    "https",
+    "http"
+  ],
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/v1/products": {
+      "get": {
+        "summary": "Read all todo tasks",
+        "operationId": "ReadAll",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/v1ReadAllResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "api",
+            "description": "API versioning: it is my best practice to specify version explicitly.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "page_size",
+            "description": "Maximum number of products to return, 0 means server default.",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int32"
+          },
+          {
+            "name": "page_token",
+            "description": "Opaque token returned as next_page_token by the previous call.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "filter.creator",
+            "description": "Product creator equals to creator.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "filter.date_from",
+            "description": "Product date is equal to or after date_from.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "date-time"
+          },
+          {
+            "name": "filter.date_to",
+            "description": "Product date is before date_to.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "date-time"
+          },
+          {
+            "name": "filter.name_prefix",
+            "description": "Product name starts with name_prefix.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "filter.price_from.currency_code",
+            "description": "ISO 4217 currency code, e.g. \"EUR\".",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "filter.price_from.units",
+            "description": "Whole units of the amount, e.g. 5 for 5.99 EUR.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "filter.price_from.nanos",
+            "description": "Nano (10^-9) units of the amount, e.g. 990000000 for 5.99 EUR.\r\nIt must have the same sign as units and it must fit into minor unit of the currency.",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int32"
+          },
+          {
+            "name": "filter.price_to.currency_code",
+            "description": "ISO 4217 currency code, e.g. \"EUR\".",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "filter.price_to.units",
+            "description": "Whole units of the amount, e.g. 5 for 5.99 EUR.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "filter.price_to.nanos",
+            "description": "Nano (10^-9) units of the amount, e.g. 990000000 for 5.99 EUR.\r\nIt must have the same sign as units and it must fit into minor unit of the currency.",
+            "in": "query",
+            "required": false,
+            "type": "integer",
+            "format": "int32"
+          },
+          {
+            "name": "filter.unit",
+            "description": "Product unit equals to unit.\n\n - GRAM: Mass\n - MILLILITER: Volume\n - PIECE: Count\n - MILLIMETER: Length",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "enum": [
+              "UNIT_OF_MEASURE_UNSPECIFIED",
+              "GRAM",
+              "KILOGRAM",
+              "MILLILITER",
+              "LITER",
+              "PIECE",
+              "DOZEN",
+              "MILLIMETER",
+              "CENTIMETER",
+              "METER"
+            ],
+            "default": "UNIT_OF_MEASURE_UNSPECIFIED"
+          },
+          {
+            "name": "filter.category_id",
+            "description": "Product category is category_id or any of its subcategories.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "order_by",
+            "description": "Sort order in format \"\u003cfield\u003e [asc|desc]\", e.g. \"date desc\"\r\nSupported fields: id (default), name, date, price\r\nPrices are sorted by currency code first, amounts of different currencies aren't compared.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "price_per",
+            "description": "Normalizes prices to price per the unit, e.g. price per kilogram for products sold in grams,\r\nso that products can be compared, see ProductProto.unit_price.\n\n - GRAM: Mass\n - MILLILITER: Volume\n - PIECE: Count\n - MILLIMETER: Length",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "enum": [
+              "UNIT_OF_MEASURE_UNSPECIFIED",
+              "GRAM",
+              "KILOGRAM",
+              "MILLILITER",
+              "LITER",
+              "PIECE",
+              "DOZEN",
+              "MILLIMETER",
+              "CENTIMETER",
+              "METER"
+            ],
+            "default": "UNIT_OF_MEASURE_UNSPECIFIED"
+          },
+          {
+            "name": "show_deleted",
+            "description": "Lists deleted products too, they have delete_time set.",
+            "in": "query",
+            "required": false,
+            "type": "boolean",
+            "format": "boolean"
+          }
+        ],
+        "tags": [
+          "ProductService"
+        ]
+      },
+      "post": {
+        "summary": "Create new todo task",
+        "operationId": "Create",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/v1CreateResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/v1CreateRequest"
+            }
+          }
+        ],
+        "tags": [
+          "ProductService"
+        ]
+      }
+    },
+    "/v1/products/{id}": {
+      "get": {
+        "summary": "Read todo task",
+        "operationId": "Read",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/v1ReadResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "api",
+            "description": "API versioning: it is my best practice to specify version explicitly.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "ProductService"
+        ]
+      },
+      "delete": {
+        "summary": "Delete todo task, deleted product is kept until it is purged after retention period",
+        "operationId": "Delete",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/v1DeleteResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "api",
+            "description": "API versioning: it is my best practice to specify version explicitly.",
+            "in": "query",
+            "required": false,
+            "type": "string"
+          },
+          {
+            "name": "expected_revision",
+            "description": "Delete fails with ABORTED if product revision differs, 0 means delete unconditionally.",
+            "in": "query",
+            "required": false,
+            "type": "string",
+            "format": "int64"
+          }
+        ],
+        "tags": [
+          "ProductService"
+        ]
+      }
+    },
+    "/v1/products/{product.id}": {
+      "patch": {
+        "summary": "Update todo task",
+        "operationId": "Update",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/v1UpdateResponse"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "product.id",
+            "in": "path",
+            "required": true,
+            "type": "string",
+            "format": "int64"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/v1ProductProto"
+            }
+          }
+        ],
+        "tags": [
+          "ProductService"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "protobufAny": {
+      "type": "object",
+      "properties": {
+        "type_url": {
+          "type": "string",
+          "description": "A URL/resource name that uniquely identifies the type of the serialized\nprotocol buffer message. This string must contain at least\none \"/\" character. The last segment of the URL's path must represent\nthe fully qualified name of the type (as in\n` + "`" + `path/google.protobuf.Duration` + "`" + `). The name should be in a canonical form\n(e.g., leading \".\" is not accepted).\n\nIn practice, teams usually precompile into the binary all types that they\nexpect it to use in the context of Any. However, for URLs which use the\nscheme ` + "`" + `http` + "`" + `, ` + "`" + `https` + "`" + `, or no scheme, one can optionally set up a type\nserver that maps type URLs to message definitions as follows:\n\n* If no scheme is provided, ` + "`" + `https` + "`" + ` is assumed.\n* An HTTP GET on the URL must yield a [google.protobuf.Type][]\n  value in binary format, or produce an error.\n* Applications are allowed to cache lookup results based on the\n  URL, or have them precompiled into a binary to avoid any\n  lookup. Therefore, binary compatibility needs to be preserved\n  on changes to types. (Use versioned type names to manage\n  breaking changes.)\n\nNote: this functionality is not currently available in the official\nprotobuf release, and it is not used for type URLs beginning with\ntype.googleapis.com.\n\nSchemes other than ` + "`" + `http` + "`" + `, ` + "`" + `https` + "`" + ` (or the empty scheme) might be\nused with implementation specific semantics."
+        },
+        "value": {
+          "type": "string",
+          "format": "byte",
+          "description": "Must be a valid serialized protocol buffer of the above specified type."
+        }
+      },
+      "description": "` + "`" + `Any` + "`" + ` contains an arbitrary serialized protocol buffer message along with a\nURL that describes the type of the serialized message.\n\nProtobuf library provides support to pack/unpack Any values in the form\nof utility functions or additional generated methods of the Any type.\n\nExample 1: Pack and unpack a message in C++.\n\n    Foo foo = ...;\n    Any any;\n    any.PackFrom(foo);\n    ...\n    if (any.UnpackTo(\u0026foo)) {\n      ...\n    }\n\nExample 2: Pack and unpack a message in Java.\n\n    Foo foo = ...;\n    Any any = Any.pack(foo);\n    ...\n    if (any.is(Foo.class)) {\n      foo = any.unpack(Foo.class);\n    }\n\n Example 3: Pack and unpack a message in Python.\n\n    foo = Foo(...)\n    any = Any()\n    any.Pack(foo)\n    ...\n    if any.Is(Foo.DESCRIPTOR):\n      any.Unpack(foo)\n      ...\n\n Example 4: Pack and unpack a message in Go\n\n     foo := \u0026pb.Foo{...}\n     any, err := ptypes.MarshalAny(foo)\n     ...\n     foo := \u0026pb.Foo{}\n     if err := ptypes.UnmarshalAny(any, foo); err != nil {\n       ...\n     }\n\nThe pack methods provided by protobuf library will by default use\n'type.googleapis.com/full.type.name' as the type URL and the unpack\nmethods only use the fully qualified type name after the last '/'\nin the type URL, for example \"foo.bar.com/x/y.z\" will yield type\nname \"y.z\".\n\n\nJSON\n====\nThe JSON representation of an ` + "`" + `Any` + "`" + ` value uses the regular\nrepresentation of the deserialized, embedded message, with an\nadditional field ` + "`" + `@type` + "`" + ` which contains the type URL. Example:\n\n    package google.profile;\n    message Person {\n      string first_name = 1;\n      string last_name = 2;\n    }\n\n    {\n      \"@type\": \"type.googleapis.com/google.profile.Person\",\n      \"firstName\": \u003cstring\u003e,\n      \"lastName\": \u003cstring\u003e\n    }\n\nIf the embedded message type is well-known and has a custom JSON\nrepresentation, that representation will be embedded adding a field\n` + "`" + `value` + "`" + ` which holds the custom JSON in addition to the ` + "`" + `@type` + "`" + `\nfield. Example (for message [google.protobuf.Duration][]):\n\n    {\n      \"@type\": \"type.googleapis.com/google.protobuf.Duration\",\n      \"value\": \"1.212s\"\n    }"
+    },
+    "protobufFieldMask": {
+      "type": "object",
+      "properties": {
+        "paths": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "description": "The set of field mask paths."
+        }
+      },
+      "description": "paths: \"f.a\"\n    paths: \"f.b.d\"\n\nHere ` + "`" + `f` + "`" + ` represents a field in some root message, ` + "`" + `a` + "`" + ` and ` + "`" + `b` + "`" + `\nfields in the message found in ` + "`" + `f` + "`" + `, and ` + "`" + `d` + "`" + ` a field found in the\nmessage in ` + "`" + `f.b` + "`" + `.\n\nField masks are used to specify a subset of fields that should be\nreturned by a get operation or modified by an update operation.\nField masks also have a custom JSON encoding (see below).\n\n# Field Masks in Projections\n\nWhen used in the context of a projection, a response message or\nsub-message is filtered by the API to only contain those fields as\nspecified in the mask. For example, if the mask in the previous\nexample is applied to a response message as follows:\n\n    f {\n      a : 22\n      b {\n        d : 1\n        x : 2\n      }\n      y : 13\n    }\n    z: 8\n\nThe result will not contain specific values for fields x,y and z\n(their value will be set to the default, and omitted in proto text\noutput):\n\n\n    f {\n      a : 22\n      b {\n        d : 1\n      }\n    }\n\nA repeated field is not allowed except at the last position of a\npaths string.\n\nIf a FieldMask object is not present in a get operation, the\noperation applies to all fields (as if a FieldMask of all fields\nhad been specified).\n\nNote that a field mask does not necessarily apply to the\ntop-level response message. In case of a REST get operation, the\nfield mask applies directly to the response, but in case of a REST\nlist operation, the mask instead applies to each individual message\nin the returned resource list. In case of a REST custom method,\nother definitions may be used. Where the mask applies will be\nclearly documented together with its declaration in the API.  In\nany case, the effect on the returned resource/resources is required\nbehavior for APIs.\n\n# Field Masks in Update Operations\n\nA field mask in update operations specifies which fields of the\ntargeted resource are going to be updated. The API is required\nto only change the values of the fields as specified in the mask\nand leave the others untouched. If a resource is passed in to\ndescribe the updated values, the API ignores the values of all\nfields not covered by the mask.\n\nIf a repeated field is specified for an update operation, new values will\nbe appended to the existing repeated field in the target resource. Note that\na repeated field is only allowed in the last position of a ` + "`" + `paths` + "`" + ` string.\n\nIf a sub-message is specified in the last position of the field mask for an\nupdate operation, then new value will be merged into the existing sub-message\nin the target resource.\n\nFor example, given the target message:\n\n    f {\n      b {\n        d: 1\n        x: 2\n      }\n      c: [1]\n    }\n\nAnd an update message:\n\n    f {\n      b {\n        d: 10\n      }\n      c: [2]\n    }\n\nthen if the field mask is:\n\n paths: [\"f.b\", \"f.c\"]\n\nthen the result will be:\n\n    f {\n      b {\n        d: 10\n        x: 2\n      }\n      c: [1, 2]\n    }\n\nAn implementation may provide options to override this default behavior for\nrepeated and message fields.\n\nIn order to reset a field's value to the default, the field must\nbe in the mask and set to the default value in the provided resource.\nHence, in order to reset all fields of a resource, provide a default\ninstance of the resource and set all fields in the mask, or do\nnot provide a mask as described below.\n\nIf a field mask is not present on update, the operation applies to\nall fields (as if a field mask of all fields has been specified).\nNote that in the presence of schema evolution, this may mean that\nfields the client does not know and has therefore not filled into\nthe request will be reset to their default. If this is unwanted\nbehavior, a specific service may require a client to always specify\na field mask, producing an error if not.\n\nAs with get operations, the location of the resource which\ndescribes the updated values in the request message depends on the\noperation kind. In any case, the effect of the field mask is\nrequired to be honored by the API.\n\n## Considerations for HTTP REST\n\nThe HTTP kind of an update operation which uses a field mask must\nbe set to PATCH instead of PUT in order to satisfy HTTP semantics\n(PUT must only be used for full updates).\n\n# JSON Encoding of Field Masks\n\nIn JSON, a field mask is encoded as a single string where paths are\nseparated by a comma. Fields name in each path are converted\nto/from lower-camel naming conventions.\n\nAs an example, consider the following message declarations:\n\n    message Profile {\n      User user = 1;\n      Photo photo = 2;\n    }\n    message User {\n      string display_name = 1;\n      string address = 2;\n    }\n\nIn proto a field mask for ` + "`" + `Profile` + "`" + ` may look as such:\n\n    mask {\n      paths: \"user.display_name\"\n      paths: \"photo\"\n    }\n\nIn JSON, the same mask is represented as below:\n\n    {\n      mask: \"user.displayName,photo\"\n    }\n\n# Field Masks and Oneof Fields\n\nField masks treat fields in oneofs just as regular fields. Consider the\nfollowing message:\n\n    message SampleMessage {\n      oneof test_oneof {\n        string name = 4;\n        SubMessage sub_message = 9;\n      }\n    }\n\nThe field mask can be:\n\n    mask {\n      paths: \"name\"\n    }\n\nOr:\n\n    mask {\n      paths: \"sub_message\"\n    }\n\nNote that oneof type names (\"test_oneof\" in this case) cannot be used in\npaths.\n\n## Field Mask Verification\n\nThe implementation of any API method which has a FieldMask type field in the\nrequest should verify the included field paths, and return an\n` + "`" + `INVALID_ARGUMENT` + "`" + ` error if any path is unmappable.",
+      "title": "` + "`" + `FieldMask` + "`" + ` represents a set of symbolic field paths, for example:"
+    },
+    "rpcStatus": {
+      "type": "object",
+      "properties": {
+        "code": {
+          "type": "integer",
+          "format": "int32",
+          "description": "The status code, which should be an enum value of [google.rpc.Code][google.rpc.Code]."
+        },
+        "message": {
+          "type": "string",
+          "description": "A developer-facing error message, which should be in English. Any\nuser-facing error message should be localized and sent in the\n[google.rpc.Status.details][google.rpc.Status.details] field, or localized by the client."
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          },
+          "description": "A list of messages that carry the error details.  There is a common set of\nmessage types for APIs to use."
+        }
+      },
+      "description": "- Simple to use and understand for most users\n- Flexible enough to meet unexpected needs\n\n# Overview\n\nThe ` + "`" + `Status` + "`" + ` message contains three pieces of data: error code, error message,\nand error details. The error code should be an enum value of\n[google.rpc.Code][google.rpc.Code], but it may accept additional error codes if needed.  The\nerror message should be a developer-facing English message that helps\ndevelopers *understand* and *resolve* the error. If a localized user-facing\nerror message is needed, put the localized message in the error details or\nlocalize it in the client. The optional error details may contain arbitrary\ninformation about the error. There is a predefined set of error detail types\nin the package ` + "`" + `google.rpc` + "`" + ` that can be used for common error conditions.\n\n# Language mapping\n\nThe ` + "`" + `Status` + "`" + ` message is the logical representation of the error model, but it\nis not necessarily the actual wire format. When the ` + "`" + `Status` + "`" + ` message is\nexposed in different client libraries and different wire protocols, it can be\nmapped differently. For example, it will likely be mapped to some exceptions\nin Java, but more likely mapped to some error codes in C.\n\n# Other uses\n\nThe error model and the ` + "`" + `Status` + "`" + ` message can be used in a variety of\nenvironments, either with or without APIs, to provide a\nconsistent developer experience across different environments.\n\nExample uses of this error model include:\n\n- Partial errors. If a service needs to return partial errors to the client,\n    it may embed the ` + "`" + `Status` + "`" + ` in the normal response to indicate the partial\n    errors.\n\n- Workflow errors. A typical workflow has multiple steps. Each step may\n    have a ` + "`" + `Status` + "`" + ` message for error reporting.\n\n- Batch operations. If a client uses batch request and batch response, the\n    ` + "`" + `Status` + "`" + ` message should be used directly inside batch response, one for\n    each error sub-response.\n\n- Asynchronous operations. If an API call embeds asynchronous operation\n    results in its response, the status of those operations should be\n    represented directly using the ` + "`" + `Status` + "`" + ` message.\n\n- Logging. If some API errors are stored in logs, the message ` + "`" + `Status` + "`" + ` could\n    be used directly after any stripping needed for security/privacy reasons.",
+      "title": "The ` + "`" + `Status` + "`" + ` type defines a logical error model that is suitable for different\nprogramming environments, including REST APIs and RPC APIs. It is used by\n[gRPC](https://github.com/grpc). The error model is designed to be:"
+    },
+    "runtimeError": {
+      "type": "object",
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    },
+    "runtimeStreamError": {
+      "type": "object",
+      "properties": {
+        "grpc_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "http_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "http_status": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    },
+    "v1BatchCreateResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "responses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1CreateResponse"
+          },
+          "title": "Empty response for every request which failed"
+        },
+        "statuses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/rpcStatus"
+          }
+        }
+      },
+      "title": "Contains results of batch create operation in order of requests"
+    },
+    "v1BatchDeleteResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "responses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1DeleteResponse"
+          },
+          "title": "Empty response for every request which failed"
+        },
+        "statuses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/rpcStatus"
+          }
+        }
+      },
+      "title": "Contains results of batch delete operation in order of requests"
+    },
+    "v1BatchReadResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "responses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1ReadResponse"
+          },
+          "title": "Empty response for every request which failed"
+        },
+        "statuses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/rpcStatus"
+          }
+        }
+      },
+      "title": "Contains results of batch read operation in order of requests"
+    },
+    "v1BatchUpdateResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "responses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1UpdateResponse"
+          },
+          "title": "Empty response for every request which failed"
+        },
+        "statuses": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/rpcStatus"
+          }
+        }
+      },
+      "title": "Contains results of batch update operation in order of requests"
+    },
+    "v1ChangeType": {
+      "type": "string",
+      "enum": [
+        "CHANGE_TYPE_UNSPECIFIED",
+        "CREATED",
+        "UPDATED",
+        "DELETED",
+        "UNDELETED"
+      ],
+      "default": "CHANGE_TYPE_UNSPECIFIED",
+      "title": "Kind of the Product change"
+    },
+    "v1CreateRequest": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        },
+        "request_id": {
+          "type": "string",
+          "description": "Client-supplied unique ID of the request, e.g. UUID, retry of the request with the same ID\r\nreturns the response of the first attempt instead of creating another product.\r\nReuse of the ID for another request fails with ALREADY_EXISTS until the ID expires."
+        }
+      },
+      "title": "Request data to create new todo task"
+    },
+    "v1CreateResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "id": {
+          "type": "string",
+          "format": "int64"
+        },
+        "revision": {
+          "type": "string",
+          "format": "int64",
+          "title": "Revision of created product"
+        }
+      },
+      "title": "Contains data of created todo task"
+    },
+    "v1DeleteRequest": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "id": {
+          "type": "string",
+          "format": "int64"
+        },
+        "expected_revision": {
+          "type": "string",
+          "format": "int64",
+          "title": "Delete fails with ABORTED if product revision differs, 0 means delete unconditionally"
+        }
+      },
+      "title": "Request data to delete todo task"
+    },
+    "v1DeleteResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "deleted": {
+          "type": "string",
+          "format": "int64",
+          "title": "Equals 1 in case of succesfull delete"
+        }
+      },
+      "title": "Contains status of delete operation"
+    },
+    "v1Highlight": {
+      "type": "object",
+      "properties": {
+        "field": {
+          "type": "string",
+          "title": "Name of the field, e.g. \"description\", or \"category\" for name of product category"
+        },
+        "snippet": {
+          "type": "string"
+        }
+      },
+      "title": "Snippet of product field with matched words wrapped in \u003cem\u003e\u003c/em\u003e, the rest of the text is HTML escaped"
+    },
+    "v1Money": {
+      "type": "object",
+      "properties": {
+        "currency_code": {
+          "type": "string",
+          "title": "ISO 4217 currency code, e.g. \"EUR\""
+        },
+        "units": {
+          "type": "string",
+          "format": "int64",
+          "title": "Whole units of the amount, e.g. 5 for 5.99 EUR"
+        },
+        "nanos": {
+          "type": "integer",
+          "format": "int32",
+          "description": "Nano (10^-9) units of the amount, e.g. 990000000 for 5.99 EUR.\r\nIt must have the same sign as units and it must fit into minor unit of the currency."
+        }
+      },
+      "title": "Money is amount of money in currency, like google.type.Money"
+    },
+    "v1ProductFilter": {
+      "type": "object",
+      "properties": {
+        "creator": {
+          "type": "string",
+          "title": "Product creator equals to creator"
+        },
+        "date_from": {
+          "type": "string",
+          "format": "date-time",
+          "title": "Product date is equal to or after date_from"
+        },
+        "date_to": {
+          "type": "string",
+          "format": "date-time",
+          "title": "Product date is before date_to"
+        },
+        "name_prefix": {
+          "type": "string",
+          "title": "Product name starts with name_prefix"
+        },
+        "price_from": {
+          "$ref": "#/definitions/v1Money",
+          "title": "Product price is equal to or greater than price_from, in the same currency"
+        },
+        "price_to": {
+          "$ref": "#/definitions/v1Money",
+          "title": "Product price is less than price_to, in the same currency"
+        },
+        "unit": {
+          "$ref": "#/definitions/v1UnitOfMeasure",
+          "title": "Product unit equals to unit"
+        },
+        "category_id": {
+          "type": "string",
+          "format": "int64",
+          "title": "Product category is category_id or any of its subcategories"
+        }
+      },
+      "title": "Filter to select products, all specified conditions must match"
+    },
+    "v1ProductProto": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string",
+          "format": "int64"
+        },
+        "name": {
+          "type": "string"
+        },
+        "price": {
+          "$ref": "#/definitions/v1Money"
+        },
+        "creator": {
+          "type": "string"
+        },
+        "unit": {
+          "$ref": "#/definitions/v1UnitOfMeasure"
+        },
+        "description": {
+          "type": "string"
+        },
+        "category_id": {
+          "type": "string",
+          "format": "int64",
+          "title": "ID of product category, 0 if product has no category"
+        },
+        "date": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "revision": {
+          "type": "string",
+          "format": "int64",
+          "title": "Revision is maintained by server and incremented on every update"
+        },
+        "unit_price": {
+          "$ref": "#/definitions/v1Money",
+          "title": "Price per unit requested by ReadAllRequest.price_per, it is output only and it is set\r\nby ReadAll if product is priced and its unit measures the same dimension, e.g. mass"
+        },
+        "delete_time": {
+          "type": "string",
+          "format": "date-time",
+          "title": "Time product was deleted at, it is output only and it is set for deleted products\r\nlisted by ReadAll with show_deleted"
+        }
+      }
+    },
+    "v1ReadAllResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "products": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1ProductProto"
+          }
+        },
+        "next_page_token": {
+          "type": "string",
+          "title": "Token to retrieve the next page, empty if this is the last page"
+        },
+        "total_size": {
+          "type": "string",
+          "format": "int64",
+          "title": "Total number of products"
+        }
+      },
+      "title": "Contains list of all todo tasks"
+    },
+    "v1ReadRequest": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "id": {
+          "type": "string",
+          "format": "int64"
+        }
+      },
+      "title": "Request data to read todo task"
+    },
+    "v1ReadResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        }
+      },
+      "title": "Contains todo task data specified in by ID request"
+    },
+    "v1SearchResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "results": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1SearchResult"
+          }
+        },
+        "next_page_token": {
+          "type": "string",
+          "title": "Token to retrieve the next page, empty if this is the last page"
+        },
+        "total_size": {
+          "type": "string",
+          "format": "int64",
+          "title": "Total number of found products"
+        }
+      },
+      "title": "Contains found products ordered by relevance"
+    },
+    "v1SearchResult": {
+      "type": "object",
+      "properties": {
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        },
+        "score": {
+          "type": "number",
+          "format": "double",
+          "title": "Relevance of the product, it is comparable between results of the same query only"
+        },
+        "highlights": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/v1Highlight"
+          },
+          "title": "Snippets of matched name and description"
+        }
+      },
+      "title": "Product found by search"
+    },
+    "v1StreamProductsResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        }
+      },
+      "title": "Contains one of the streamed products"
+    },
+    "v1UndeleteResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        }
+      },
+      "title": "Contains restored product"
+    },
+    "v1UnitOfMeasure": {
+      "type": "string",
+      "enum": [
+        "UNIT_OF_MEASURE_UNSPECIFIED",
+        "GRAM",
+        "KILOGRAM",
+        "MILLILITER",
+        "LITER",
+        "PIECE",
+        "DOZEN",
+        "MILLIMETER",
+        "CENTIMETER",
+        "METER"
+      ],
+      "default": "UNIT_OF_MEASURE_UNSPECIFIED",
+      "description": "- GRAM: Mass\n - MILLILITER: Volume\n - PIECE: Count\n - MILLIMETER: Length",
+      "title": "UnitOfMeasure is unit product is sold in, product price is price of one unit"
+    },
+    "v1UpdateRequest": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto"
+        },
+        "update_mask": {
+          "$ref": "#/definitions/protobufFieldMask",
+          "title": "Fields of product to update, e.g. \"price\", all fields are replaced if it is empty"
+        },
+        "expected_revision": {
+          "type": "string",
+          "format": "int64",
+          "title": "Update fails with ABORTED if product revision differs, 0 means update unconditionally"
+        },
+        "request_id": {
+          "type": "string",
+          "title": "Client-supplied unique ID of the request, the same as CreateRequest.request_id"
+        }
+      },
+      "title": "Request data to update todo task"
+    },
+    "v1UpdateResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "updated": {
+          "type": "string",
+          "format": "int64",
+          "title": "Equals 1 in case of succesfull update"
+        }
+      },
+      "title": "Contains status of update operation"
+    },
+    "v1WatchResponse": {
+      "type": "object",
+      "properties": {
+        "api": {
+          "type": "string",
+          "title": "API versioning: it is my best practice to specify version explicitly"
+        },
+        "type": {
+          "$ref": "#/definitions/v1ChangeType"
+        },
+        "product": {
+          "$ref": "#/definitions/v1ProductProto",
+          "description": "Product state after the change, only id and revision are set for deleted product.\r\nChanges of a product come in revision order, a change arriving late is skipped."
+        },
+        "resume_token": {
+          "type": "string",
+          "title": "Token to resume watching after this event"
+        }
+      },
+      "title": "Contains one change of a product"
+    }
+  }
+}
+`
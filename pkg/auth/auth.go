@@ -0,0 +1,26 @@
+// Package auth verifies JWT bearer tokens and keeps the verified principal in the context of a call.
+package auth
+
+import "context"
+
+// Principal is verified identity of caller
+type Principal struct {
+	// Subject is "sub" claim of the token
+	Subject string
+	// Claims are all claims of the token
+	Claims map[string]interface{}
+}
+
+// principalKey is context key of Principal
+type principalKey struct{}
+
+// NewContext returns copy of ctx carrying principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns principal of ctx, false if the call is anonymous
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+)
+
+// encodeInt encodes integer as JWK parameter
+func encodeInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+// writeJWKS writes JWK Set of the keys to temporary file
+func writeJWKS(t *testing.T, keys ...jwk) string {
+	b, err := json.Marshal(map[string][]jwk{"keys": keys})
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+	f, err := ioutil.TempFile("", "jwks")
+	if err != nil {
+		t.Fatalf("failed to create JWKS file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(b); err != nil {
+		t.Fatalf("failed to write JWKS file: %v", err)
+	}
+	return f.Name()
+}
+
+func TestLoadJWKS(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	ec := jwk{Kty: "EC", Kid: "ec", Crv: "P-256", X: encodeInt(ecKey.X), Y: encodeInt(ecKey.Y)}
+
+	tests := []struct {
+		name     string
+		keys     []jwk
+		wantKeys int
+		wantErr  bool
+	}{
+		{name: "Encryption keys are skipped", keys: []jwk{ec, {Kty: "RSA", Kid: "enc", Use: "enc"}}, wantKeys: 1},
+		{name: "Unsupported curve", keys: []jwk{{Kty: "EC", Crv: "P-384", X: ec.X, Y: ec.Y}}, wantErr: true},
+		{name: "Point not on curve", keys: []jwk{{Kty: "EC", Crv: "P-256", X: ec.X, Y: ec.X}}, wantErr: true},
+		{name: "Unsupported key type", keys: []jwk{{Kty: "oct"}}, wantErr: true},
+		{name: "Duplicate key ID", keys: []jwk{ec, ec}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := writeJWKS(t, tt.keys...)
+			defer os.Remove(file)
+			got, err := LoadJWKS(file)
+			if (err != nil) != tt.wantErr || len(got) != tt.wantKeys {
+				t.Errorf("LoadJWKS() = %d keys, %v, want %d keys, wantErr %v", len(got), err, tt.wantKeys, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Verifier verifies JWTs signed by HS256 with shared secret, or by RS256 or ES256 with private keys matching
+// public keys of JWK Set
+type Verifier struct {
+	secret []byte
+	keys   map[string]crypto.PublicKey
+	parser *jwt.Parser
+}
+
+// NewVerifier returns verifier of tokens signed with secret or keys, HS256 tokens aren't accepted if secret is empty
+func NewVerifier(secret []byte, keys map[string]crypto.PublicKey) *Verifier {
+	return &Verifier{
+		secret: secret,
+		keys:   keys,
+		parser: &jwt.Parser{ValidMethods: []string{"HS256", "RS256", "ES256"}},
+	}
+}
+
+// key returns key to verify signature of token, by "kid" header if the token has it.
+// Key must match algorithm of the token, so that public key can't be used as HMAC secret.
+func (v *Verifier) key(t *jwt.Token) (interface{}, error) {
+	if t.Method.Alg() == "HS256" {
+		if len(v.secret) == 0 {
+			return nil, errors.New("HS256 tokens aren't accepted")
+		}
+		return v.secret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key '%s'", kid)
+	}
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if t.Method.Alg() == "RS256" {
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if t.Method.Alg() == "ES256" {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("key '%s' doesn't match algorithm %s", kid, t.Method.Alg())
+}
+
+// Verify verifies signature and validity period of token, and returns its principal.
+// Token must have "sub" and "exp" claims.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims["sub"].(string)
+	if len(sub) == 0 {
+		return nil, errors.New("token has no subject")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("token has no expiration time")
+	}
+	return &Principal{Subject: sub, Claims: claims}, nil
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// sign returns token of claims signed by method with key, kid header is set unless it is empty
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if len(kid) > 0 {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return s
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	file := writeJWKS(t,
+		jwk{Kty: "RSA", Kid: "rsa", N: encodeInt(rsaKey.N), E: encodeInt(big.NewInt(int64(rsaKey.E)))},
+		jwk{Kty: "EC", Kid: "ec", Crv: "P-256", X: encodeInt(ecKey.X), Y: encodeInt(ecKey.Y)},
+	)
+	defer os.Remove(file)
+	keys, err := LoadJWKS(file)
+	if err != nil {
+		t.Fatalf("LoadJWKS() error = %v", err)
+	}
+	v := NewVerifier([]byte("secret"), keys)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := jwt.MapClaims{"sub": "marty", "exp": exp}
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "HS256", token: sign(t, jwt.SigningMethodHS256, "", []byte("secret"), valid)},
+		{name: "RS256", token: sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, valid)},
+		{name: "ES256", token: sign(t, jwt.SigningMethodES256, "ec", ecKey, valid)},
+		{
+			name:    "Wrong secret",
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte("guess"), valid),
+			wantErr: true,
+		},
+		{
+			name:    "Unknown key",
+			token:   sign(t, jwt.SigningMethodRS256, "other", rsaKey, valid),
+			wantErr: true,
+		},
+		{
+			name:    "Key of other algorithm",
+			token:   sign(t, jwt.SigningMethodES256, "rsa", ecKey, valid),
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported algorithm",
+			token:   sign(t, jwt.SigningMethodHS512, "", []byte("secret"), valid),
+			wantErr: true,
+		},
+		{
+			name:    "Expired",
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"sub": "marty", "exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "No expiration time",
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"sub": "marty"}),
+			wantErr: true,
+		},
+		{
+			name:    "No subject",
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte("secret"), jwt.MapClaims{"exp": exp}),
+			wantErr: true,
+		},
+		{
+			name:    "Malformed",
+			token:   "potato",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Subject != "marty" {
+				t.Errorf("Verifier.Verify() subject = %q, want marty", got.Subject)
+			}
+		})
+	}
+
+	// HS256 tokens are rejected without secret
+	if _, err := NewVerifier(nil, keys).Verify(tests[0].token); err == nil {
+		t.Errorf("Verifier.Verify() of HS256 token without secret error = nil, want error")
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MartyKuentzel/projectX/pkg/logger"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// runMigrate runs migrate command with arguments: up, down [n] or status
+func runMigrate(ctx context.Context, m *sqldb.Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate command requires argument: up, down [n] or status")
+	}
+
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, m)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: '%s'", args[1])
+			}
+		}
+		done, err := m.Down(ctx, steps)
+		for _, mg := range done {
+			logger.Log.Info("reverted migration", zap.Int64("version", mg.Version), zap.String("name", mg.Name))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to revert migrations: %v", err)
+		}
+		return nil
+
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get status of migrations: %v", err)
+		}
+		for _, s := range list {
+			applied := "pending"
+			if !s.AppliedAt.IsZero() {
+				applied = "applied at " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate argument: '%s'", args[0])
+}
+
+// migrateUp applies pending migrations
+func migrateUp(ctx context.Context, m *sqldb.Migrator) error {
+	done, err := m.Up(ctx)
+	for _, mg := range done {
+		logger.Log.Info("applied migration", zap.Int64("version", mg.Version), zap.String("name", mg.Name))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %v", err)
+	}
+	return nil
+}
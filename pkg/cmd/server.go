@@ -2,26 +2,95 @@ package cmd
 
 import (
 	"context"
+	"crypto"
+	"crypto/tls"
 	"database/sql"
 	"flag"
 	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	// mysql driver
 	_ "github.com/go-sql-driver/mysql"
+	// postgres driver
+	_ "github.com/lib/pq"
 	//	"github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/dialers/mysql"
+	"github.com/MartyKuentzel/projectX/pkg/auth"
 	"github.com/MartyKuentzel/projectX/pkg/logger"
 	"github.com/MartyKuentzel/projectX/pkg/protocol/grpc"
+	"github.com/MartyKuentzel/projectX/pkg/protocol/grpc/middleware"
+	"github.com/MartyKuentzel/projectX/pkg/protocol/rest"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/memory"
+	"github.com/MartyKuentzel/projectX/pkg/repository/mysql"
+	"github.com/MartyKuentzel/projectX/pkg/repository/postgres"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqlite"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+	memindex "github.com/MartyKuentzel/projectX/pkg/search/memory"
 	v1 "github.com/MartyKuentzel/projectX/pkg/service/v1"
+	"github.com/MartyKuentzel/projectX/pkg/tlsconfig"
 )
 
+// tlsReloadInterval is period between checks of TLS files for changes
+const tlsReloadInterval = 30 * time.Second
+
 // Config is configuration for Server
 type Config struct {
 	// gRPC server start parameters section
 	// gRPC is TCP port to listen by gRPC server
 	GRPCPort string
 
+	// HTTP/REST gateway start parameters section
+	// HTTPPort is TCP port to listen by HTTP/REST gateway
+	HTTPPort string
+
+	// gRPC-Web server start parameters section
+	// GRPCWebPort is TCP port to listen by gRPC-Web server for browser clients, gRPC-Web is disabled if it is empty,
+	// which is the default
+	GRPCWebPort string
+	// GRPCWebAllowedOrigins are origins of web pages allowed to call gRPC-Web server, "*" allows any origin
+	GRPCWebAllowedOrigins []string
+
+	// TLS parameters section, gRPC server, HTTP gateway and gRPC-Web server listen by the same TLS configuration
+	// TLSCertFile is PEM file of server certificate chain, it is reloaded when it changes
+	TLSCertFile string
+	// TLSKeyFile is PEM file of server private key, it is reloaded when it changes
+	TLSKeyFile string
+	// TLSClientCAFile is PEM file of CA certificates of clients, mutual TLS is required if it is set
+	TLSClientCAFile string
+	// TLSMinVersion is minimum TLS version: 1.0, 1.1, 1.2 or 1.3
+	TLSMinVersion string
+	// Insecure disables TLS, servers listen in plaintext
+	Insecure bool
+
+	// Auth parameters section, calls are authenticated by JWT bearer tokens
+	// AuthHMACSecret is secret of HS256 tokens, HS256 tokens are rejected if it is empty
+	AuthHMACSecret string
+	// AuthJWKSFile is JWK Set file of public keys of RS256 and ES256 tokens
+	AuthJWKSFile string
+	// AuthPublicMethods are full names of methods which can be called without token, e.g. /v1.ProductService/Read
+	AuthPublicMethods []string
+	// AuthDisabled turns authentication off, any caller can call every method
+	AuthDisabled bool
+
+	// Store parameters section
+	// Store is backend to keep Products in: db or memory
+	Store string
+	// StoreFile is file to snapshot in-memory store to, Products are kept in memory only if it is empty
+	StoreFile string
+
 	// DB Datastore parameters section
-	// DatastoreDBHost is host of database
+	// DatastoreDBDriver is database driver: mysql, postgres or sqlite
+	DatastoreDBDriver string
+	// DatastoreDBFile is database file of sqlite driver
+	DatastoreDBFile string
+	// DatastoreDBHost is host of database, default port of the driver is used if it is empty
 	DatastoreDBHost string
 	// DatastoreDBUser is username to connect to database
 	DatastoreDBUser string
@@ -29,6 +98,18 @@ type Config struct {
 	DatastoreDBPassword string
 	// DatastoreDBName is name of database
 	DatastoreDBName string
+	// DatastoreDBMigrate enables applying of pending database migrations at startup
+	DatastoreDBMigrate bool
+
+	// Purge parameters section
+	// PurgeRetention is period deleted Products are kept for before they are purged, 0 disables purging
+	PurgeRetention time.Duration
+	// PurgeInterval is period between runs of purge, expired idempotency keys are purged by every run too
+	PurgeInterval time.Duration
+
+	// Paging parameters section
+	// PageTokenSecret is secret to sign page tokens, must be the same for all server instances
+	PageTokenSecret string
 
 	// Log parameters section
 	// LogLevel is global log level: Debug(-1), Info(0), Warn(1), Error(2), DPanic(3), Panic(4), Fatal(5)
@@ -37,55 +118,295 @@ type Config struct {
 	LogTimeFormat string
 }
 
-// RunServer runs gRPC server and HTTP gateway
+// RunServer runs gRPC server and HTTP gateway,
+// or database migrations if it is started with migrate up|down [n]|status command
 func RunServer() error {
 	ctx := context.Background()
 
 	// get configuration
 	var cfg Config
 	flag.StringVar(&cfg.GRPCPort, "grpc-port", "8080", "gRPC port to bind")
-	flag.StringVar(&cfg.DatastoreDBHost, "db-host", "127.0.0.1:3306", "Database host")
+	flag.StringVar(&cfg.HTTPPort, "http-port", "8081", "HTTP/REST gateway port to bind")
+	flag.StringVar(&cfg.GRPCWebPort, "grpc-web-port", "", "gRPC-Web port to bind, e.g. 8082, gRPC-Web is disabled if it is empty")
+	var allowedOrigins string
+	flag.StringVar(&allowedOrigins, "grpc-web-allowed-origins", "",
+		"Comma-separated origins allowed to call gRPC-Web server, e.g. https://admin.example.com, * allows any origin")
+	flag.StringVar(&cfg.TLSCertFile, "tls-cert", "", "PEM file of server certificate chain")
+	flag.StringVar(&cfg.TLSKeyFile, "tls-key", "", "PEM file of server private key")
+	flag.StringVar(&cfg.TLSClientCAFile, "tls-client-ca", "", "PEM file of client CA certificates, requires mutual TLS if set")
+	flag.StringVar(&cfg.TLSMinVersion, "tls-min-version", "1.2", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	flag.BoolVar(&cfg.Insecure, "insecure", false, "Listen in plaintext without TLS, for local use only")
+	flag.StringVar(&cfg.AuthHMACSecret, "auth-hmac-secret", "", "Secret of HS256 bearer tokens")
+	flag.StringVar(&cfg.AuthJWKSFile, "auth-jwks-file", "", "JWK Set file of public keys of RS256 and ES256 bearer tokens")
+	var publicMethods string
+	flag.StringVar(&publicMethods, "auth-public-methods", "",
+		"Comma-separated methods which can be called without token, e.g. /v1.ProductService/Read,/v1.ProductService/ReadAll")
+	flag.BoolVar(&cfg.AuthDisabled, "auth-disabled", false, "Don't authenticate calls, for local use only")
+	flag.StringVar(&cfg.Store, "store", "db", "Store backend: db or memory")
+	flag.StringVar(&cfg.StoreFile, "store-file", "", "File to snapshot in-memory store to")
+	flag.StringVar(&cfg.DatastoreDBDriver, "db-driver", "mysql", "Database driver: mysql, postgres or sqlite")
+	flag.StringVar(&cfg.DatastoreDBFile, "db-file", "products.db", "Database file of sqlite driver")
+	flag.StringVar(&cfg.DatastoreDBHost, "db-host", "", "Database host, 127.0.0.1 with default port of the driver if empty")
 	flag.StringVar(&cfg.DatastoreDBUser, "db-user", "root", "Database user")
 	flag.StringVar(&cfg.DatastoreDBPassword, "db-password", "", "Database password")
 	flag.StringVar(&cfg.DatastoreDBName, "db-name", "DB_1", "Database Name")
+	flag.BoolVar(&cfg.DatastoreDBMigrate, "db-migrate", true, "Apply pending database migrations at startup")
+	flag.DurationVar(&cfg.PurgeRetention, "purge-retention", 30*24*time.Hour, "Period to keep deleted Products for, 0 disables purging")
+	flag.DurationVar(&cfg.PurgeInterval, "purge-interval", time.Hour, "Period between purges of deleted Products and expired request IDs")
+	flag.StringVar(&cfg.PageTokenSecret, "page-token-secret", "", "Secret to sign page tokens")
 	flag.IntVar(&cfg.LogLevel, "log-level", 0, "Global log level")
 	flag.StringVar(&cfg.LogTimeFormat, "log-time-format", "",
 		"Print time format for logger e.g. 2006-01-02T15:04:05Z07:00")
 	flag.Parse()
+	cfg.GRPCWebAllowedOrigins = splitList(allowedOrigins)
+	cfg.AuthPublicMethods = splitList(publicMethods)
 
 	if len(cfg.GRPCPort) == 0 {
 		return fmt.Errorf("invalid TCP port for gRPC server: '%s'", cfg.GRPCPort)
 	}
 
-	if len(cfg.DatastoreDBPassword) == 0 {
+	if len(cfg.HTTPPort) == 0 || cfg.HTTPPort == cfg.GRPCPort {
+		return fmt.Errorf("invalid TCP port for HTTP gateway: '%s'", cfg.HTTPPort)
+	}
+
+	if cfg.GRPCWebPort == cfg.GRPCPort || cfg.GRPCWebPort == cfg.HTTPPort {
+		return fmt.Errorf("invalid TCP port for gRPC-Web server: '%s'", cfg.GRPCWebPort)
+	}
+
+	// migrate command doesn't listen
+	if flag.Arg(0) != "migrate" && !cfg.Insecure && (len(cfg.TLSCertFile) == 0 || len(cfg.TLSKeyFile) == 0) {
+		return fmt.Errorf("tls-cert and tls-key arguments missing, use -insecure to listen in plaintext")
+	}
+
+	hasAuthKeys := len(cfg.AuthHMACSecret) > 0 || len(cfg.AuthJWKSFile) > 0
+	if flag.Arg(0) != "migrate" && !cfg.AuthDisabled && !hasAuthKeys {
+		return fmt.Errorf("auth-hmac-secret or auth-jwks-file argument missing, use -auth-disabled to disable authentication")
+	}
+
+	if cfg.AuthDisabled && hasAuthKeys {
+		return fmt.Errorf("auth-disabled argument conflicts with auth-hmac-secret and auth-jwks-file arguments")
+	}
+
+	tlsVersion, err := tlsconfig.ParseVersion(cfg.TLSMinVersion)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Store != "db" && cfg.Store != "memory" {
+		return fmt.Errorf("invalid store: '%s'", cfg.Store)
+	}
+
+	if cfg.Store == "db" && cfg.DatastoreDBDriver != "mysql" && cfg.DatastoreDBDriver != "postgres" &&
+		cfg.DatastoreDBDriver != "sqlite" {
+		return fmt.Errorf("invalid database driver: '%s'", cfg.DatastoreDBDriver)
+	}
+
+	if cfg.Store == "db" && cfg.DatastoreDBDriver != "sqlite" && len(cfg.DatastoreDBPassword) == 0 {
 		return fmt.Errorf("db-password argument missing")
 	}
 
+	if cfg.PurgeRetention < 0 || cfg.PurgeInterval <= 0 {
+		return fmt.Errorf("invalid purge retention '%s' or interval '%s'", cfg.PurgeRetention, cfg.PurgeInterval)
+	}
+
+	if flag.NArg() > 0 && flag.Arg(0) != "migrate" {
+		return fmt.Errorf("unknown command: '%s'", flag.Arg(0))
+	}
+
+	if flag.Arg(0) == "migrate" && cfg.Store != "db" {
+		return fmt.Errorf("migrate command is supported by db store only")
+	}
+
 	// initialize logger
 	if err := logger.Init(cfg.LogLevel, cfg.LogTimeFormat); err != nil {
 		return fmt.Errorf("failed to initialize logger: %v", err)
 	}
 
-	// add MySQL driver specific parameter to parse date/time
-	// Drop it for another database
-	param := "parseTime=true"
+	if len(cfg.PageTokenSecret) == 0 {
+		logger.Log.Warn("page-token-secret argument missing: page tokens are signed with random key")
+	}
 
-	// db, err := mysql.DialCfg(dns)
+	var repo repository.ProductRepository
+	var categories repository.CategoryRepository
+	// index is FULLTEXT index of MySQL database or in-process index built at startup
+	var index search.Index
+	if cfg.Store == "memory" {
+		r, err := memory.NewRepository(cfg.StoreFile)
+		if err != nil {
+			return fmt.Errorf("failed to open in-memory store: %v", err)
+		}
+		repo, categories = r, r
+	} else {
+		db, err := openDB(cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?%s",
-		cfg.DatastoreDBUser,
-		cfg.DatastoreDBPassword,
-		cfg.DatastoreDBHost,
-		cfg.DatastoreDBName,
-		param)
+		var m *sqldb.Migrator
+		switch cfg.DatastoreDBDriver {
+		case "postgres":
+			repo, categories, m = postgres.NewProductRepository(db), postgres.NewCategoryRepository(db), postgres.NewMigrator(db)
+		case "sqlite":
+			repo, categories, m = sqlite.NewProductRepository(db), sqlite.NewCategoryRepository(db), sqlite.NewMigrator(db)
+		default:
+			repo, categories, m = mysql.NewProductRepository(db), mysql.NewCategoryRepository(db), mysql.NewMigrator(db)
+			index = mysql.NewSearchIndex(db)
+		}
 
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
+		if flag.Arg(0) == "migrate" {
+			return runMigrate(ctx, m, flag.Args()[1:])
+		}
+
+		if cfg.DatastoreDBMigrate {
+			if err := migrateUp(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	if index == nil {
+		index = memindex.NewIndex(categories)
+		if err := search.Rebuild(ctx, index, repo); err != nil {
+			return fmt.Errorf("failed to build search index: %v", err)
+		}
+	}
+
+	// purge and reload of certificates stop when server stops
+	bgCtx, cancel := context.WithCancel(ctxzap.ToContext(ctx, logger.Log))
+	defer cancel()
+	go v1.RunPurge(bgCtx, repo, cfg.PurgeRetention, cfg.PurgeInterval)
+
+	// gateway connects to gRPC server by TLS too
+	var serverTLS, gatewayTLS *tls.Config
+	if !cfg.Insecure {
+		certs, err := tlsconfig.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+		if err != nil {
+			return err
+		}
+		go certs.Run(bgCtx, tlsReloadInterval)
+		serverTLS, gatewayTLS = certs.ServerConfig(tlsVersion), certs.LoopbackConfig(tlsVersion)
+	} else {
+		logger.Log.Warn("insecure argument is set: servers listen in plaintext")
+	}
+
+	var authn *middleware.Auth
+	if !cfg.AuthDisabled {
+		if authn, err = newAuth(cfg); err != nil {
+			return err
+		}
+	} else {
+		logger.Log.Warn("auth-disabled argument is set: calls are not authenticated")
 	}
-	defer db.Close()
 
-	v1API := v1.NewProductServiceServer(db)
+	v1API := v1.NewProductServiceServer(repo, categories, []byte(cfg.PageTokenSecret), index)
+	categoryAPI := v1.NewCategoryServiceServer(categories, repo, index)
+
+	// interrupt stops gateway, gRPC server is stopped after gateway finished requests it forwards
+	gatewayCtx, stopGateway := context.WithCancel(ctx)
+	defer stopGateway()
+	grpcCtx, stopGRPC := context.WithCancel(ctx)
+	defer stopGRPC()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(c)
+		select {
+		case <-c:
+			stopGateway()
+		case <-gatewayCtx.Done():
+		}
+	}()
+
+	gatewayErr := make(chan error, 1)
+	go func() {
+		err := rest.RunServer(gatewayCtx, cfg.GRPCPort, cfg.HTTPPort, serverTLS, gatewayTLS)
+		stopGRPC()
+		gatewayErr <- err
+	}()
+
+	err = grpc.RunServer(grpcCtx, v1API, categoryAPI, cfg.GRPCPort, cfg.GRPCWebPort, cfg.GRPCWebAllowedOrigins, serverTLS, authn)
+	// gateway is useless without gRPC server
+	stopGateway()
+	if gwErr := <-gatewayErr; err == nil {
+		err = gwErr
+	}
+	return err
+}
 
-	return grpc.RunServer(ctx, v1API, cfg.GRPCPort)
+// splitList splits comma-separated list skipping empty items
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// newAuth returns authentication of calls by keys of the configuration, it fails if there are no keys
+func newAuth(cfg Config) (*middleware.Auth, error) {
+	var keys map[string]crypto.PublicKey
+	if len(cfg.AuthJWKSFile) > 0 {
+		var err error
+		if keys, err = auth.LoadJWKS(cfg.AuthJWKSFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(cfg.AuthHMACSecret) == 0 && len(keys) == 0 {
+		return nil, fmt.Errorf("no auth keys: auth-hmac-secret is empty and auth-jwks-file has no signature keys")
+	}
+	return middleware.NewAuth(auth.NewVerifier([]byte(cfg.AuthHMACSecret), keys), cfg.AuthPublicMethods), nil
+}
+
+// openDB opens database of the driver
+func openDB(cfg Config) (*sql.DB, error) {
+	if cfg.DatastoreDBDriver == "sqlite" {
+		db, err := sqlite.Open(cfg.DatastoreDBFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %v", err)
+		}
+		return db, nil
+	}
+
+	var dsn string
+	if cfg.DatastoreDBDriver == "postgres" {
+		if len(cfg.DatastoreDBHost) == 0 {
+			cfg.DatastoreDBHost = "127.0.0.1:5432"
+		}
+
+		// connection is not encrypted like for MySQL,
+		// use Cloud SQL proxy to connect to remote database
+		dsn = (&url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.DatastoreDBUser, cfg.DatastoreDBPassword),
+			Host:     cfg.DatastoreDBHost,
+			Path:     "/" + cfg.DatastoreDBName,
+			RawQuery: "sslmode=disable",
+		}).String()
+	} else {
+		if len(cfg.DatastoreDBHost) == 0 {
+			cfg.DatastoreDBHost = "127.0.0.1:3306"
+		}
+
+		// add MySQL driver specific parameter to parse date/time
+		// and to report matched (not only changed) rows for UPDATE
+		param := "parseTime=true&clientFoundRows=true"
+
+		// db, err := mysql.DialCfg(dns)
+
+		dsn = fmt.Sprintf("%s:%s@tcp(%s)/%s?%s",
+			cfg.DatastoreDBUser,
+			cfg.DatastoreDBPassword,
+			cfg.DatastoreDBHost,
+			cfg.DatastoreDBName,
+			param)
+	}
+
+	db, err := sql.Open(cfg.DatastoreDBDriver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+	return db, nil
 }
@@ -0,0 +1,93 @@
+package measure
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Dimension is kind of quantity measured by units
+type Dimension int
+
+const (
+	// Mass is measured in grams
+	Mass Dimension = iota + 1
+	// Volume is measured in milliliters
+	Volume
+	// Count is measured in pieces
+	Count
+	// Length is measured in millimeters
+	Length
+)
+
+// Unit is unit of measure Products are sold in
+type Unit struct {
+	// Code identifies unit in storage, e.g. "kg"
+	Code      string
+	Dimension Dimension
+	// base is number of the smallest units of the dimension in the unit, e.g. 1000 grams in kilogram
+	base int64
+}
+
+// units are all known units of measure
+var units = []Unit{
+	{Code: "g", Dimension: Mass, base: 1},
+	{Code: "kg", Dimension: Mass, base: 1000},
+	{Code: "ml", Dimension: Volume, base: 1},
+	{Code: "l", Dimension: Volume, base: 1000},
+	{Code: "pc", Dimension: Count, base: 1},
+	{Code: "dozen", Dimension: Count, base: 12},
+	{Code: "mm", Dimension: Length, base: 1},
+	{Code: "cm", Dimension: Length, base: 10},
+	{Code: "m", Dimension: Length, base: 1000},
+}
+
+// aliases maps lower case names of units written as free text to unit codes
+var aliases = map[string]string{
+	"gr": "g", "gram": "g", "grams": "g", "gramm": "g",
+	"kgs": "kg", "kilo": "kg", "kilos": "kg", "kilogram": "kg", "kilograms": "kg", "kilogramm": "kg",
+	"milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"ltr": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"pcs": "pc", "piece": "pc", "pieces": "pc", "stk": "pc", "stück": "pc",
+	"dz": "dozen", "dozens": "dozen",
+	"millimeter": "mm", "millimeters": "mm", "millimetre": "mm", "millimetres": "mm",
+	"centimeter": "cm", "centimeters": "cm", "centimetre": "cm", "centimetres": "cm",
+	"meter": "m", "meters": "m", "metre": "m", "metres": "m",
+}
+
+// Lookup returns unit by its code, ok is false if unit is not known
+func Lookup(code string) (u Unit, ok bool) {
+	for _, u := range units {
+		if u.Code == code {
+			return u, true
+		}
+	}
+	return Unit{}, false
+}
+
+// Parse recognizes unit written as free text, e.g. "Kg" or "kilo", ok is false if unit is not known
+func Parse(s string) (u Unit, ok bool) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if code, ok := aliases[name]; ok {
+		name = code
+	}
+	return Lookup(name)
+}
+
+// Ratio returns number of units to in one unit from, e.g. 1000 for "kg" and "g".
+// It fails if units measure different dimensions.
+func Ratio(from, to Unit) (*big.Rat, error) {
+	if from.Dimension != to.Dimension || from.base == 0 || to.base == 0 {
+		return nil, fmt.Errorf("unit '%s' can't be converted to '%s'", from.Code, to.Code)
+	}
+	return big.NewRat(from.base, to.base), nil
+}
+
+// Convert converts quantity measured in unit from to unit to
+func Convert(quantity *big.Rat, from, to Unit) (*big.Rat, error) {
+	r, err := Ratio(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return r.Mul(r, quantity), nil
+}
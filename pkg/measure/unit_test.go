@@ -0,0 +1,67 @@
+package measure
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s      string
+		want   string
+		wantOK bool
+	}{
+		{s: "kg", want: "kg", wantOK: true},
+		{s: "Kg", want: "kg", wantOK: true},
+		{s: " kilo ", want: "kg", wantOK: true},
+		{s: "Litre", want: "l", wantOK: true},
+		{s: "pcs", want: "pc", wantOK: true},
+		{s: "bag", wantOK: false},
+		{s: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, ok := Parse(tt.s)
+			if ok != tt.wantOK || got.Code != tt.want {
+				t.Errorf("Parse() = %v, %v, want %v, %v", got.Code, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	unit := func(code string) Unit {
+		u, ok := Lookup(code)
+		if !ok {
+			t.Fatalf("Lookup(%s) failed", code)
+		}
+		return u
+	}
+
+	tests := []struct {
+		name     string
+		quantity *big.Rat
+		from, to Unit
+		want     *big.Rat
+		wantErr  bool
+	}{
+		{name: "kg to g", quantity: big.NewRat(3, 2), from: unit("kg"), to: unit("g"), want: big.NewRat(1500, 1)},
+		{name: "g to kg", quantity: big.NewRat(250, 1), from: unit("g"), to: unit("kg"), want: big.NewRat(1, 4)},
+		{name: "dozen to pc", quantity: big.NewRat(2, 1), from: unit("dozen"), to: unit("pc"), want: big.NewRat(24, 1)},
+		{name: "cm to m", quantity: big.NewRat(5, 1), from: unit("cm"), to: unit("m"), want: big.NewRat(1, 20)},
+		{name: "Different dimensions", quantity: big.NewRat(1, 1), from: unit("kg"), to: unit("l"), wantErr: true},
+		{name: "Unknown unit", quantity: big.NewRat(1, 1), from: unit("kg"), to: Unit{Code: "bag"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.quantity, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Cmp(tt.want) != 0 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// pow10 are powers of 10 up to nanos
+var pow10 = [...]int64{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000, 1000000000}
+
+// Amount is amount of money in minor units of currency, e.g. cents of EUR.
+// Zero Amount has no currency and means that amount is not set.
+type Amount struct {
+	// Currency is ISO 4217 currency code
+	Currency string
+	// Minor is number of minor units
+	Minor int64
+}
+
+// IsZero checks if amount is not set
+func (a Amount) IsZero() bool {
+	return len(a.Currency) == 0 && a.Minor == 0
+}
+
+// FromUnits converts amount in whole units and nano (10^-9) units of currency, like in google.type.Money,
+// it fails if the currency is not known or amount can't be represented in minor units of the currency
+func FromUnits(currency string, units int64, nanos int32) (Amount, error) {
+	digits, ok := Digits(currency)
+	if !ok {
+		return Amount{}, fmt.Errorf("currency code '%s' is not valid ISO 4217 code", currency)
+	}
+	if nanos <= -1e9 || nanos >= 1e9 {
+		return Amount{}, fmt.Errorf("nanos %d must be greater than -1e9 and less than 1e9", nanos)
+	}
+	if (units > 0 && nanos < 0) || (units < 0 && nanos > 0) {
+		return Amount{}, errors.New("units and nanos must have the same sign")
+	}
+
+	step := pow10[9-digits]
+	if int64(nanos)%step != 0 {
+		return Amount{}, fmt.Errorf("amount has more than %d fractional digits allowed for currency %s", digits, currency)
+	}
+	scale := pow10[digits]
+	if units > math.MaxInt64/scale-1 || units < math.MinInt64/scale+1 {
+		return Amount{}, errors.New("amount is out of range")
+	}
+	return Amount{Currency: currency, Minor: units*scale + int64(nanos)/step}, nil
+}
+
+// Units returns amount in whole units and nano (10^-9) units of currency
+func (a Amount) Units() (units int64, nanos int32) {
+	digits, _ := Digits(a.Currency)
+	scale := pow10[digits]
+	return a.Minor / scale, int32(a.Minor % scale * pow10[9-digits])
+}
+
+// Mul multiplies amount by r and returns result in whole units and nano (10^-9) units of currency,
+// result is rounded half away from zero to nanos
+func (a Amount) Mul(r *big.Rat) (units int64, nanos int32, err error) {
+	digits, _ := Digits(a.Currency)
+	x := new(big.Rat).SetFrac(big.NewInt(a.Minor), big.NewInt(pow10[digits]))
+	x.Mul(x, r)
+
+	// whole units are truncated, fraction is rounded to nanos
+	whole := new(big.Int).Quo(x.Num(), x.Denom())
+	fraction := new(big.Rat).Sub(x, new(big.Rat).SetInt(whole))
+	fraction.Mul(fraction, big.NewRat(1e9, 1))
+	n, rem := new(big.Int).QuoRem(fraction.Num(), fraction.Denom(), new(big.Int))
+	if rem.Mul(rem.Abs(rem), big.NewInt(2)).Cmp(fraction.Denom()) >= 0 {
+		n.Add(n, big.NewInt(int64(fraction.Sign())))
+	}
+	if n.CmpAbs(big.NewInt(1e9)) == 0 {
+		whole.Add(whole, big.NewInt(int64(n.Sign())))
+		n.SetInt64(0)
+	}
+	if !whole.IsInt64() {
+		return 0, 0, errors.New("amount is out of range")
+	}
+	return whole.Int64(), int32(n.Int64()), nil
+}
+
+// String formats amount with all fractional digits of the currency, e.g. "5.00 EUR"
+func (a Amount) String() string {
+	digits, _ := Digits(a.Currency)
+	sign := ""
+	minor := a.Minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	s := strconv.FormatInt(minor, 10)
+	if digits > 0 {
+		if len(s) <= digits {
+			s = strings.Repeat("0", digits-len(s)+1) + s
+		}
+		s = s[:len(s)-digits] + "." + s[len(s)-digits:]
+	}
+	return sign + s + " " + a.Currency
+}
+
+// Parse parses price written as free text, e.g. "5€", "€ 5,50", "5.50 EUR" or "USD 3".
+// Currency must be given either by symbol or by ISO 4217 code.
+func Parse(s string) (Amount, error) {
+	number := strings.TrimSpace(s)
+
+	var currency string
+	for symbol, code := range symbols {
+		if strings.HasPrefix(number, symbol) {
+			currency, number = code, strings.TrimPrefix(number, symbol)
+			break
+		}
+		if strings.HasSuffix(number, symbol) {
+			currency, number = code, strings.TrimSuffix(number, symbol)
+			break
+		}
+	}
+	if len(currency) == 0 && len(number) > 3 {
+		if code := number[:3]; isCode(code) {
+			currency, number = code, number[3:]
+		} else if code := number[len(number)-3:]; isCode(code) {
+			currency, number = code, number[:len(number)-3]
+		}
+	}
+	if len(currency) == 0 {
+		return Amount{}, fmt.Errorf("price '%s' has no currency", s)
+	}
+	digits, ok := Digits(currency)
+	if !ok {
+		return Amount{}, fmt.Errorf("currency code '%s' is not valid ISO 4217 code", currency)
+	}
+
+	// decimal comma is accepted as well
+	number = strings.Replace(strings.TrimSpace(number), ",", ".", 1)
+	whole, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		whole, fraction = number[:i], number[i+1:]
+	}
+	if len(fraction) > digits {
+		return Amount{}, fmt.Errorf("price '%s' has more than %d fractional digits allowed for currency %s", s, digits, currency)
+	}
+	if digitsOnly := strings.TrimPrefix(whole, "-"); len(digitsOnly) == 0 || !isDigits(digitsOnly) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf("price '%s' is not a number", s)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", digits-len(fraction)), 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("price '%s' is out of range", s)
+	}
+	return Amount{Currency: currency, Minor: minor}, nil
+}
+
+// isCode checks if s looks like currency code
+func isCode(s string) bool {
+	for _, r := range s {
+		if !unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits checks if s consists of decimal digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
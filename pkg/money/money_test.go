@@ -0,0 +1,131 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFromUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		units    int64
+		nanos    int32
+		want     Amount
+		wantErr  bool
+	}{
+		{name: "EUR", currency: "EUR", units: 5, nanos: 990000000, want: Amount{Currency: "EUR", Minor: 599}},
+		{name: "Negative", currency: "EUR", units: -1, nanos: -500000000, want: Amount{Currency: "EUR", Minor: -150}},
+		{name: "JPY", currency: "JPY", units: 500, want: Amount{Currency: "JPY", Minor: 500}},
+		{name: "BHD", currency: "BHD", units: 1, nanos: 5000000, want: Amount{Currency: "BHD", Minor: 1005}},
+		{name: "Unknown currency", currency: "XYZ", units: 1, wantErr: true},
+		{name: "Lowercase currency", currency: "eur", units: 1, wantErr: true},
+		{name: "Too precise", currency: "EUR", units: 1, nanos: 1000000, wantErr: true},
+		{name: "Sign mismatch", currency: "EUR", units: 1, nanos: -10000000, wantErr: true},
+		{name: "Nanos out of range", currency: "EUR", nanos: 1000000000, wantErr: true},
+		{name: "Out of range", currency: "EUR", units: 1 << 62, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromUnits(tt.currency, tt.units, tt.nanos)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromUnits() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FromUnits() = %v, want %v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if units, nanos := got.Units(); units != tt.units || nanos != tt.nanos {
+				t.Errorf("Amount.Units() = %d, %d, want %d, %d", units, nanos, tt.units, tt.nanos)
+			}
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := []struct {
+		a    Amount
+		want string
+	}{
+		{Amount{Currency: "EUR", Minor: 500}, "5.00 EUR"},
+		{Amount{Currency: "EUR", Minor: 5}, "0.05 EUR"},
+		{Amount{Currency: "EUR", Minor: -150}, "-1.50 EUR"},
+		{Amount{Currency: "JPY", Minor: 500}, "500 JPY"},
+		{Amount{Currency: "BHD", Minor: 1005}, "1.005 BHD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("Amount.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Amount
+		wantErr bool
+	}{
+		{s: "5€", want: Amount{Currency: "EUR", Minor: 500}},
+		{s: "€ 5,50", want: Amount{Currency: "EUR", Minor: 550}},
+		{s: " 5.5 EUR ", want: Amount{Currency: "EUR", Minor: 550}},
+		{s: "USD 3", want: Amount{Currency: "USD", Minor: 300}},
+		{s: "$0.99", want: Amount{Currency: "USD", Minor: 99}},
+		{s: "-2 GBP", want: Amount{Currency: "GBP", Minor: -200}},
+		{s: "1000¥", want: Amount{Currency: "JPY", Minor: 1000}},
+		{s: "5", wantErr: true},
+		{s: "cheap", wantErr: true},
+		{s: "5.999 EUR", wantErr: true},
+		{s: "1.000,50 EUR", wantErr: true},
+		{s: "5 XYZ", wantErr: true},
+		{s: "- EUR", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := Parse(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmount_Mul(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         Amount
+		r         *big.Rat
+		wantUnits int64
+		wantNanos int32
+		wantErr   bool
+	}{
+		{name: "Scale up", a: Amount{Currency: "EUR", Minor: 199}, r: big.NewRat(1000, 1), wantUnits: 1990},
+		{name: "Scale down", a: Amount{Currency: "EUR", Minor: 599}, r: big.NewRat(1, 1000), wantNanos: 5990000},
+		{name: "Round", a: Amount{Currency: "EUR", Minor: 100}, r: big.NewRat(2, 3), wantNanos: 666666667},
+		{name: "Round to whole unit", a: Amount{Currency: "EUR", Minor: 100}, r: big.NewRat(1999999999, 2000000000), wantUnits: 1},
+		{name: "Negative", a: Amount{Currency: "EUR", Minor: -150}, r: big.NewRat(1, 3), wantNanos: -500000000},
+		{name: "JPY", a: Amount{Currency: "JPY", Minor: 500}, r: big.NewRat(1, 4), wantUnits: 125},
+		{name: "Out of range", a: Amount{Currency: "EUR", Minor: 1 << 62}, r: big.NewRat(1000, 1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units, nanos, err := tt.a.Mul(tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Amount.Mul() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if units != tt.wantUnits || nanos != tt.wantNanos {
+				t.Errorf("Amount.Mul() = %d, %d, want %d, %d", units, nanos, tt.wantUnits, tt.wantNanos)
+			}
+		})
+	}
+}
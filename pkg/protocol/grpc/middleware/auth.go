@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MartyKuentzel/projectX/pkg/auth"
+)
+
+// Auth authenticates calls by JWT bearer token of "authorization" metadata
+type Auth struct {
+	verifier *auth.Verifier
+	// public are full names of methods which can be called without token, e.g. "/v1.ProductService/Read"
+	public map[string]bool
+}
+
+// NewAuth returns authentication by tokens of verifier, publicMethods can be called without token
+func NewAuth(verifier *auth.Verifier, publicMethods []string) *Auth {
+	public := make(map[string]bool, len(publicMethods))
+	for _, m := range publicMethods {
+		public[m] = true
+	}
+	return &Auth{verifier: verifier, public: public}
+}
+
+// authenticate returns context with principal of the call. Calls of public methods without token are anonymous,
+// token is verified if they have it.
+func (a *Auth) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if md, _ := metadata.FromIncomingContext(ctx); a.public[method] && len(md["authorization"]) == 0 {
+		return ctx, nil
+	}
+
+	token, err := grpc_auth.AuthFromMD(ctx, "bearer")
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token in authorization metadata")
+	}
+	p, err := a.verifier.Verify(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token-> "+err.Error())
+	}
+
+	// principal is logged with the call
+	grpc_ctxtags.Extract(ctx).Set("auth.sub", p.Subject)
+	return auth.NewContext(ctx, p), nil
+}
+
+// UnaryServerInterceptor rejects unary calls without valid token with Unauthenticated status
+func (a *Auth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streaming calls without valid token with Unauthenticated status
+func (a *Auth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MartyKuentzel/projectX/pkg/auth"
+)
+
+func TestAuth_UnaryServerInterceptor(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "marty", "exp": time.Now().Add(time.Hour).Unix()}).
+		SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	interceptor := NewAuth(auth.NewVerifier([]byte("secret"), nil), []string{"/v1.ProductService/Read"}).UnaryServerInterceptor()
+
+	// handler returns subject of the principal, empty for anonymous calls
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		p, ok := auth.FromContext(ctx)
+		if !ok {
+			return "", nil
+		}
+		return p.Subject, nil
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		want          string
+		wantCode      codes.Code
+	}{
+		{name: "Token", method: "/v1.ProductService/Delete", authorization: "Bearer " + token, want: "marty"},
+		{name: "No token", method: "/v1.ProductService/Delete", wantCode: codes.Unauthenticated},
+		{name: "Invalid token", method: "/v1.ProductService/Delete", authorization: "Bearer " + token + "x", wantCode: codes.Unauthenticated},
+		{name: "Other scheme", method: "/v1.ProductService/Delete", authorization: "Basic " + token, wantCode: codes.Unauthenticated},
+		{name: "Public method without token", method: "/v1.ProductService/Read"},
+		{name: "Public method with token", method: "/v1.ProductService/Read", authorization: "Bearer " + token, want: "marty"},
+		{name: "Public method with invalid token", method: "/v1.ProductService/Read", authorization: "Bearer x", wantCode: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if len(tt.authorization) > 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+			got, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("interceptor() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("interceptor() principal = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
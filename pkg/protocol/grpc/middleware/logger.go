@@ -21,8 +21,9 @@ func codeToLevel(code codes.Code) zapcore.Level {
 	return grpc_zap.DefaultCodeToLevel(code)
 }
 
-// AddLogging returns grpc.Server config option that turn on logging.
-func AddLogging(logger *zap.Logger, opts []grpc.ServerOption) []grpc.ServerOption {
+// AddLogging returns grpc.Server config option that turn on logging,
+// and authentication of calls unless authn is nil. Failed authentication is logged too.
+func AddLogging(logger *zap.Logger, authn *Auth, opts []grpc.ServerOption) []grpc.ServerOption {
 	// Shared options for the logger, with a custom gRPC code to log level function.
 	o := []grpc_zap.Option{
 		grpc_zap.WithLevels(codeToLevel),
@@ -30,17 +31,24 @@ func AddLogging(logger *zap.Logger, opts []grpc.ServerOption) []grpc.ServerOptio
 	// Make sure that log statements internal to gRPC library are logged using the zapLogger as well.
 	grpc_zap.ReplaceGrpcLogger(logger)
 
-	// Add unary interceptor
-	opts = append(opts, grpc_middleware.WithUnaryServerChain(
+	unary := []grpc.UnaryServerInterceptor{
 		grpc_ctxtags.UnaryServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
 		grpc_zap.UnaryServerInterceptor(logger, o...),
-	))
-
-	// Add stream interceptor (added as an example here)
-	opts = append(opts, grpc_middleware.WithStreamServerChain(
+	}
+	stream := []grpc.StreamServerInterceptor{
 		grpc_ctxtags.StreamServerInterceptor(grpc_ctxtags.WithFieldExtractor(grpc_ctxtags.CodeGenRequestFieldExtractor)),
 		grpc_zap.StreamServerInterceptor(logger, o...),
-	))
+	}
+	if authn != nil {
+		unary = append(unary, authn.UnaryServerInterceptor())
+		stream = append(stream, authn.StreamServerInterceptor())
+	}
+
+	// Add unary interceptor
+	opts = append(opts, grpc_middleware.WithUnaryServerChain(unary...))
+
+	// Add stream interceptor
+	opts = append(opts, grpc_middleware.WithStreamServerChain(stream...))
 
 	return opts
-}
\ No newline at end of file
+}
@@ -2,48 +2,83 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 
 	"net"
-	"os"
-	"os/signal"
+	"net/http"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
 	"github.com/MartyKuentzel/projectX/pkg/logger"
 	"github.com/MartyKuentzel/projectX/pkg/protocol/grpc/middleware"
 )
 
-// RunServer runs gRPC service to publish Product service
-func RunServer(ctx context.Context, v1API v1.ProductServiceServer, port string) error {
+// RunServer runs gRPC service to publish Product and Category services,
+// and serves them to browsers by gRPC-Web on webPort too unless it is empty.
+// Both listen by TLS of tlsConfig, or in plaintext if it is nil, and authenticate calls by authn unless it is nil.
+// In-flight calls are finished and server stops when ctx is done.
+func RunServer(ctx context.Context, v1API v1.ProductServiceServer, categoryAPI v1.CategoryServiceServer, port, webPort string,
+	allowedOrigins []string, tlsConfig *tls.Config, authn *middleware.Auth) error {
 	listen, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		return err
 	}
 
+	var webListen net.Listener
+	if len(webPort) > 0 {
+		webListen, err = net.Listen("tcp", ":"+webPort)
+		if err != nil {
+			listen.Close()
+			return err
+		}
+	}
+
 	// gRPC server statup options
 	opts := []grpc.ServerOption{}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
 
 	// add middleware
-	opts = middleware.AddLogging(logger.Log, opts)
+	opts = middleware.AddLogging(logger.Log, authn, opts)
 
 	// register service
 	server := grpc.NewServer(opts...)
 
 	v1.RegisterProductServiceServer(server, v1API)
+	v1.RegisterCategoryServiceServer(server, categoryAPI)
 
-	// graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	go func() {
-		for range c {
-			// sig is a ^C, handle it
-			logger.Log.Warn("shutting down gRPC server...")
-
-			server.GracefulStop()
+	// gRPC-Web calls are calls of gRPC server too
+	var web *http.Server
+	if webListen != nil {
+		web = &http.Server{Handler: newWebHandler(server, allowedOrigins)}
+		serve := web.Serve
+		if tlsConfig != nil {
+			// certificate is given by tlsConfig
+			web.TLSConfig = tlsConfig.Clone()
+			serve = func(l net.Listener) error { return web.ServeTLS(l, "", "") }
+		}
+		go func() {
+			logger.Log.Info("starting gRPC-Web server...")
+			if err := serve(webListen); err != http.ErrServerClosed {
+				logger.Log.Error("gRPC-Web server failed", zap.Error(err))
+			}
+		}()
+	}
 
-			<-ctx.Done()
+	// graceful shutdown, gRPC server waits for in-flight gRPC-Web calls too
+	go func() {
+		<-ctx.Done()
+		if web != nil {
+			logger.Log.Warn("shutting down gRPC-Web server...")
+			shutdownWeb(web)
 		}
+		logger.Log.Warn("shutting down gRPC server...")
+
+		server.GracefulStop()
 	}()
 
 	// start gRPC server
@@ -0,0 +1,37 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// webShutdownTimeout is time in-flight gRPC-Web calls are given to finish when server stops,
+// streams still open after it are closed
+const webShutdownTimeout = 10 * time.Second
+
+// newWebHandler returns HTTP handler translating gRPC-Web calls of browsers, unary and server-streaming ones,
+// to calls of the server. Cross-origin calls are allowed from allowedOrigins only, "*" allows any origin.
+func newWebHandler(server *grpc.Server, allowedOrigins []string) http.Handler {
+	origins := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		origins[o] = true
+	}
+	return grpcweb.WrapServer(server,
+		grpcweb.WithOriginFunc(func(origin string) bool {
+			return origins["*"] || origins[origin]
+		}),
+	)
+}
+
+// shutdownWeb stops gRPC-Web server waiting for in-flight calls to finish
+func shutdownWeb(web *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), webShutdownTimeout)
+	defer cancel()
+	if err := web.Shutdown(ctx); err != nil {
+		web.Close()
+	}
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+// stubServer serves the same Products to any request
+type stubServer struct {
+	v1.UnimplementedProductServiceServer
+}
+
+func (s *stubServer) Read(ctx context.Context, req *v1.ReadRequest) (*v1.ReadResponse, error) {
+	return &v1.ReadResponse{Api: "v1", Product: &v1.ProductProto{Id: req.Id, Name: "Potato"}}, nil
+}
+
+func (s *stubServer) StreamProducts(req *v1.StreamProductsRequest, stream v1.ProductService_StreamProductsServer) error {
+	for _, name := range []string{"Potato", "Tomato"} {
+		if err := stream.Send(&v1.StreamProductsResponse{Api: "v1", Product: &v1.ProductProto{Name: name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callWeb calls method by gRPC-Web and returns messages and trailer of the response
+func callWeb(t *testing.T, url, method string, req proto.Message) ([][]byte, string) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	frame := make([]byte, 5, 5+len(b))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(b)))
+	res, err := http.Post(url+"/v1.ProductService/"+method, "application/grpc-web+proto", bytes.NewReader(append(frame, b...)))
+	if err != nil {
+		t.Fatalf("failed to call %s: %v", method, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("failed to read response of %s: %v", method, err)
+	}
+
+	var msgs [][]byte
+	var trailer string
+	for len(body) >= 5 {
+		n := 5 + int(binary.BigEndian.Uint32(body[1:5]))
+		if body[0]&0x80 != 0 {
+			trailer = string(body[5:n])
+		} else {
+			msgs = append(msgs, body[5:n])
+		}
+		body = body[n:]
+	}
+	return msgs, trailer
+}
+
+func Test_newWebHandler(t *testing.T) {
+	server := grpc.NewServer()
+	v1.RegisterProductServiceServer(server, &stubServer{})
+	web := httptest.NewServer(newWebHandler(server, []string{"https://admin.example.com"}))
+	defer web.Close()
+
+	msgs, trailer := callWeb(t, web.URL, "Read", &v1.ReadRequest{Api: "v1", Id: 3})
+	var read v1.ReadResponse
+	if len(msgs) != 1 || proto.Unmarshal(msgs[0], &read) != nil || read.Product.GetId() != 3 {
+		t.Errorf("Read() messages = %q, want ReadResponse of Product 3", msgs)
+	}
+	if !strings.Contains(trailer, "grpc-status: 0") {
+		t.Errorf("Read() trailer = %q, want status 0", trailer)
+	}
+
+	// messages of server-streaming call are framed one by one
+	msgs, trailer = callWeb(t, web.URL, "StreamProducts", &v1.StreamProductsRequest{Api: "v1"})
+	var names []string
+	for _, m := range msgs {
+		var res v1.StreamProductsResponse
+		if err := proto.Unmarshal(m, &res); err != nil {
+			t.Fatalf("failed to unmarshal StreamProductsResponse: %v", err)
+		}
+		names = append(names, res.Product.Name)
+	}
+	if want := []string{"Potato", "Tomato"}; !reflect.DeepEqual(names, want) || !strings.Contains(trailer, "grpc-status: 0") {
+		t.Errorf("StreamProducts() = %v, trailer %q, want %v and status 0", names, trailer, want)
+	}
+
+	tests := []struct {
+		name   string
+		origin string
+		want   string
+	}{
+		{name: "Allowed origin", origin: "https://admin.example.com", want: "https://admin.example.com"},
+		{name: "Other origin", origin: "https://example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("OPTIONS", web.URL+"/v1.ProductService/Read", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("failed to send preflight request: %v", err)
+			}
+			res.Body.Close()
+			if got := res.Header.Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
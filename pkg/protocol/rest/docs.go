@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+const (
+	// swaggerPath is path OpenAPI document of the gateway is served on
+	swaggerPath = "/v1/swagger.json"
+	// docsPath is path of page rendering OpenAPI document
+	docsPath = "/docs"
+)
+
+// docsPage renders OpenAPI document of the server by Swagger UI the server serves itself
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Product service</title>
+  <link rel="stylesheet" href="` + docsPath + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + docsPath + `/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "` + swaggerPath + `", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// docsAsset is file of the docs page
+type docsAsset struct {
+	contentType string
+	content     string
+}
+
+// docsAssets are Swagger UI files embedded in the binary by paths under docsPath, see gen-docs.go
+var docsAssets = map[string]docsAsset{
+	"/swagger-ui-bundle.js": {contentType: "application/javascript; charset=utf-8", content: swaggerUIBundle},
+	"/swagger-ui.css":       {contentType: "text/css; charset=utf-8", content: swaggerUICSS},
+}
+
+// serveSwagger replies with OpenAPI document embedded in the binary, so that it matches API the server implements
+func serveSwagger(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, v1.ProductServiceSwagger)
+}
+
+// serveDocs replies with the docs page
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, docsPage)
+}
+
+// serveDocsAsset replies with Swagger UI file of the docs page
+func serveDocsAsset(w http.ResponseWriter, r *http.Request) {
+	asset, ok := docsAssets[strings.TrimPrefix(r.URL.Path, docsPath)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", asset.contentType)
+	io.WriteString(w, asset.content)
+}
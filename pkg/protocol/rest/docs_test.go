@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+func Test_swaggerIsEmbedded(t *testing.T) {
+	doc, err := ioutil.ReadFile("../../../api/swagger/v1/product-service.swagger.json")
+	if err != nil {
+		t.Fatalf("failed to read OpenAPI document: %v", err)
+	}
+	if string(doc) != v1.ProductServiceSwagger {
+		t.Errorf("ProductServiceSwagger differs from api/swagger/v1/product-service.swagger.json, run third_party/protoc-gen.sh")
+	}
+}
+
+func Test_swaggerUIIsEmbedded(t *testing.T) {
+	for path, asset := range docsAssets {
+		file, err := ioutil.ReadFile("../../../third_party/swagger-ui" + path)
+		if err != nil {
+			t.Fatalf("failed to read Swagger UI file: %v", err)
+		}
+		if string(file) != asset.content {
+			t.Errorf("asset %s differs from third_party/swagger-ui, run pkg/protocol/rest/gen-docs.go", path)
+		}
+	}
+}
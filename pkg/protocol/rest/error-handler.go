@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MartyKuentzel/projectX/pkg/logger"
+)
+
+// statusClientClosedRequest is non-standard HTTP status of requests cancelled by client
+const statusClientClosedRequest = 499
+
+// httpStatus maps gRPC status code to HTTP status as documented in google/rpc/code.proto
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return statusClientClosedRequest
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		// Unknown, Internal, DataLoss
+		return http.StatusInternalServerError
+	}
+}
+
+// retryAfter returns delay of google.rpc.RetryInfo detail of the status in whole seconds, 0 if there is none
+func retryAfter(s *status.Status) int {
+	for _, d := range s.Details() {
+		info, ok := d.(*errdetails.RetryInfo)
+		if !ok {
+			continue
+		}
+		delay, err := ptypes.Duration(info.RetryDelay)
+		if err != nil || delay <= 0 {
+			return 0
+		}
+		return int(math.Ceil(delay.Seconds()))
+	}
+	return 0
+}
+
+// errorHandler replies to failed request with gRPC status marshaled to the body, HTTP status mapped from its code
+// and Retry-After header if the status suggests to retry
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	s := status.Convert(err)
+	if err == runtime.ErrUnknownURI {
+		s = status.New(codes.NotFound, "no method is bound to "+r.Method+" "+r.URL.Path)
+	}
+
+	body, err := marshaler.Marshal(s.Proto())
+	if err != nil {
+		logger.Log.Error("failed to marshal error", zap.Error(err))
+		s = status.New(codes.Internal, "failed to marshal error")
+		body = []byte(`{"code": 13, "message": "failed to marshal error"}`)
+	}
+
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", marshaler.ContentType())
+	if seconds := retryAfter(s); seconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	w.WriteHeader(httpStatus(s.Code()))
+	if _, err := w.Write(body); err != nil {
+		logger.Log.Debug("failed to write error response", zap.Error(err))
+	}
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func Test_httpStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{code: codes.OK, want: http.StatusOK},
+		{code: codes.Canceled, want: statusClientClosedRequest},
+		{code: codes.InvalidArgument, want: http.StatusBadRequest},
+		{code: codes.FailedPrecondition, want: http.StatusBadRequest},
+		{code: codes.NotFound, want: http.StatusNotFound},
+		{code: codes.AlreadyExists, want: http.StatusConflict},
+		{code: codes.Aborted, want: http.StatusConflict},
+		{code: codes.Unauthenticated, want: http.StatusUnauthorized},
+		{code: codes.ResourceExhausted, want: http.StatusTooManyRequests},
+		{code: codes.Unavailable, want: http.StatusServiceUnavailable},
+		{code: codes.DeadlineExceeded, want: http.StatusGatewayTimeout},
+		{code: codes.DataLoss, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := httpStatus(tt.code); got != tt.want {
+				t.Errorf("httpStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+//go:build ignore
+// +build ignore
+
+// gen-docs writes Swagger UI files to Go constants, so that the docs page is served by the server itself:
+//
+//	go run pkg/protocol/rest/gen-docs.go <swagger-ui dir> <output.go>
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// assets are constants written to the output by names of the files they keep
+var assets = []struct {
+	constant string
+	file     string
+}{
+	{constant: "swaggerUIBundle", file: "swagger-ui-bundle.js"},
+	{constant: "swaggerUICSS", file: "swagger-ui.css"},
+}
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: go run gen-docs.go <swagger-ui dir> <output.go>")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1], os.Args[2]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes content of Swagger UI files of dir to constants of output file
+func generate(dir, output string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by gen-docs.go. DO NOT EDIT.\n// source: %s\n\npackage rest\n", filepath.ToSlash(dir))
+	for _, a := range assets {
+		content, err := ioutil.ReadFile(filepath.Join(dir, a.file))
+		if err != nil {
+			return err
+		}
+		// raw string literal can't contain backquote, it is concatenated as interpreted string
+		literal := "`" + strings.Replace(string(content), "`", "` + \"`\" + `", -1) + "`"
+		fmt.Fprintf(&b, "\n// %s is %s of Swagger UI\nconst %s = %s\n", a.constant, a.file, a.constant, literal)
+	}
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return ioutil.WriteFile(output, formatted, 0644)
+}
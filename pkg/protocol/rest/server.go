@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/logger"
+)
+
+// shutdownTimeout is time in-flight requests are given to finish when gateway stops
+const shutdownTimeout = 10 * time.Second
+
+// NewHandler returns HTTP handler translating REST/JSON requests to calls of Product service on conn,
+// it serves OpenAPI document of the service and its docs page too
+func NewHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	gw := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		runtime.WithProtoErrorHandler(errorHandler),
+	)
+	if err := v1.RegisterProductServiceHandler(ctx, gw, conn); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(swaggerPath, serveSwagger)
+	mux.HandleFunc(docsPath, serveDocs)
+	mux.HandleFunc(docsPath+"/", serveDocsAsset)
+	mux.Handle("/", gw)
+	return mux, nil
+}
+
+// RunServer runs HTTP/REST gateway forwarding requests to gRPC server on grpcPort of localhost,
+// in-flight requests are finished when ctx is done. Gateway listens by TLS of serverTLS and connects
+// to gRPC server by TLS of clientTLS, both are in plaintext if the configurations are nil.
+func RunServer(ctx context.Context, grpcPort, httpPort string, serverTLS, clientTLS *tls.Config) error {
+	dialOpt := grpc.WithInsecure()
+	if clientTLS != nil {
+		dialOpt = grpc.WithTransportCredentials(credentials.NewTLS(clientTLS))
+	}
+	conn, err := grpc.Dial("localhost:"+grpcPort, dialOpt)
+	if err != nil {
+		return err
+	}
+	// connection is closed after in-flight requests are finished
+	defer conn.Close()
+
+	handler, err := NewHandler(ctx, conn)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:      ":" + httpPort,
+		Handler:   handler,
+		TLSConfig: serverTLS,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		logger.Log.Info("starting HTTP/REST gateway...")
+		if serverTLS != nil {
+			// certificate is given by serverTLS
+			errc <- srv.ListenAndServeTLS("", "")
+			return
+		}
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	// graceful shutdown
+	logger.Log.Warn("shutting down HTTP/REST gateway...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
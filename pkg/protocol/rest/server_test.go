@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+// stubServer records requests and fails the calls of unknown Products
+type stubServer struct {
+	v1.UnimplementedProductServiceServer
+	update *v1.UpdateRequest
+}
+
+func (s *stubServer) Read(ctx context.Context, req *v1.ReadRequest) (*v1.ReadResponse, error) {
+	if req.Id != 1 {
+		return nil, status.Errorf(codes.NotFound, "Product with ID='%d' is not found", req.Id)
+	}
+	return &v1.ReadResponse{Api: "v1", Product: &v1.ProductProto{Id: 1, Name: "Potato"}}, nil
+}
+
+func (s *stubServer) Update(ctx context.Context, req *v1.UpdateRequest) (*v1.UpdateResponse, error) {
+	s.update = req
+	return &v1.UpdateResponse{Api: "v1", Updated: 1}, nil
+}
+
+func (s *stubServer) Delete(ctx context.Context, req *v1.DeleteRequest) (*v1.DeleteResponse, error) {
+	st, _ := status.New(codes.Unavailable, "database is unavailable").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(1500 * time.Millisecond)})
+	return nil, st.Err()
+}
+
+func TestNewHandler(t *testing.T) {
+	ctx := context.Background()
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	stub := &stubServer{}
+	server := grpc.NewServer()
+	v1.RegisterProductServiceServer(server, stub)
+	go server.Serve(listen)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listen.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	handler, err := NewHandler(ctx, conn)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatus     int
+		wantBody       string
+		wantRetryAfter string
+	}{
+		{
+			name:       "Read",
+			method:     "GET",
+			path:       "/v1/products/1",
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"Potato"`,
+		},
+		{
+			name:       "Read unknown Product",
+			method:     "GET",
+			path:       "/v1/products/2",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `"message":"Product with ID='2' is not found"`,
+		},
+		{
+			name:       "Invalid ID",
+			method:     "GET",
+			path:       "/v1/products/potato",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Unknown path",
+			method:     "GET",
+			path:       "/v1/potatoes",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Retry",
+			method:         "DELETE",
+			path:           "/v1/products/1",
+			wantStatus:     http.StatusServiceUnavailable,
+			wantBody:       `"@type":"type.googleapis.com/google.rpc.RetryInfo"`,
+			wantRetryAfter: "2",
+		},
+		{
+			name:       "OpenAPI document",
+			method:     "GET",
+			path:       "/v1/swagger.json",
+			wantStatus: http.StatusOK,
+			wantBody:   `"/v1/products/{id}"`,
+		},
+		{
+			name:       "Docs",
+			method:     "GET",
+			path:       "/docs",
+			wantStatus: http.StatusOK,
+			wantBody:   `url: "/v1/swagger.json"`,
+		},
+		{
+			name:       "Docs script",
+			method:     "GET",
+			path:       "/docs/swagger-ui-bundle.js",
+			wantStatus: http.StatusOK,
+			wantBody:   "SwaggerUIBundle",
+		},
+		{
+			name:       "Unknown docs file",
+			method:     "GET",
+			path:       "/docs/potato.js",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Unimplemented",
+			method:     "GET",
+			path:       "/v1/products",
+			wantStatus: http.StatusNotImplemented,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want to contain %s", w.Body, tt.wantBody)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+
+	// fields of PATCH body are updated only
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("PATCH", "/v1/products/1?expected_revision=3",
+		strings.NewReader(`{"name": "Potato", "description": "Organic"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %d, want %d, body %s", w.Code, http.StatusOK, w.Body)
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || res["updated"] != "1" {
+		t.Errorf("PATCH body = %s, want updated 1", w.Body)
+	}
+	// mask is inferred from JSON object in random order
+	got := append([]string(nil), stub.update.GetUpdateMask().GetPaths()...)
+	sort.Strings(got)
+	if want := []string{"description", "name"}; !reflect.DeepEqual(got, want) ||
+		stub.update.Product.Id != 1 || stub.update.ExpectedRevision != 3 {
+		t.Errorf("Update() request = %v, want ID 1, revision 3 and mask %v", stub.update, want)
+	}
+}
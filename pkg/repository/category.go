@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Category is Category entity as it is kept in storage, Categories form a tree
+type Category struct {
+	ID   int64
+	Name string
+	// ParentID is ID of parent Category, 0 for root Category
+	ParentID int64
+}
+
+// CategoryNotFoundError is returned if Category with the ID doesn't exist
+type CategoryNotFoundError struct {
+	ID int64
+}
+
+func (e *CategoryNotFoundError) Error() string {
+	return fmt.Sprintf("Category with ID='%d' is not found", e.ID)
+}
+
+// CategoryInUseError is returned if Category can't be deleted as it has subcategories or Products
+type CategoryInUseError struct {
+	ID int64
+}
+
+func (e *CategoryInUseError) Error() string {
+	return fmt.Sprintf("Category with ID='%d' has subcategories or Products", e.ID)
+}
+
+// CategoryCycleError is returned if Category is moved under itself or any of its subcategories
+type CategoryCycleError struct {
+	ID       int64
+	ParentID int64
+}
+
+func (e *CategoryCycleError) Error() string {
+	return fmt.Sprintf("Category with ID='%d' can't be moved under its own subcategory with ID='%d'", e.ID, e.ParentID)
+}
+
+// CategoryRepository is storage of Categories.
+// Products reference Categories, so Categories are kept in the same storage as Products.
+type CategoryRepository interface {
+	// CreateCategory saves new Category and returns it with assigned ID, its parent must exist
+	CreateCategory(ctx context.Context, c *Category) (*Category, error)
+	// GetCategory returns Category by ID
+	GetCategory(ctx context.Context, id int64) (*Category, error)
+	// UpdateCategory writes name of Category and returns its new state
+	UpdateCategory(ctx context.Context, c *Category) (*Category, error)
+	// MoveCategory moves Category with its subcategories under the parent, 0 makes it root Category
+	MoveCategory(ctx context.Context, id int64, parentID int64) (*Category, error)
+	// DeleteCategory removes Category which has neither subcategories nor Products
+	DeleteCategory(ctx context.Context, id int64) error
+	// ListCategories returns all Categories ordered by ID
+	ListCategories(ctx context.Context) ([]*Category, error)
+}
+
+// Repository is storage of Products and their Categories
+type Repository interface {
+	ProductRepository
+	CategoryRepository
+}
+
+// Descendants returns all subcategories of Category with the ID in depth-first order,
+// parents go before their children and siblings are ordered as in categories.
+// Every Category is listed once, so that corrupted tree with a cycle can't make it recurse forever.
+func Descendants(categories []*Category, id int64) []*Category {
+	children := map[int64][]*Category{}
+	for _, c := range categories {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var list []*Category
+	visited := map[int64]bool{id: true}
+	var walk func(id int64)
+	walk = func(id int64) {
+		for _, c := range children[id] {
+			if visited[c.ID] {
+				continue
+			}
+			visited[c.ID] = true
+			list = append(list, c)
+			walk(c.ID)
+		}
+	}
+	walk(id)
+	return list
+}
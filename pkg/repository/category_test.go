@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescendants(t *testing.T) {
+	// food (1) -> vegetable (2) -> roots (4), food (1) -> fruit (3), drinks (5)
+	categories := []*Category{
+		{ID: 1, Name: "food"},
+		{ID: 2, Name: "vegetable", ParentID: 1},
+		{ID: 3, Name: "fruit", ParentID: 1},
+		{ID: 4, Name: "roots", ParentID: 2},
+		{ID: 5, Name: "drinks"},
+	}
+
+	tests := []struct {
+		name string
+		id   int64
+		want []int64
+	}{
+		{
+			name: "Whole tree",
+			id:   0,
+			want: []int64{1, 2, 4, 3, 5},
+		},
+		{
+			name: "Subtree",
+			id:   1,
+			want: []int64{2, 4, 3},
+		},
+		{
+			name: "Leaf",
+			id:   4,
+		},
+		{
+			name: "Unknown",
+			id:   8,
+		},
+		{
+			name: "Cycle",
+			id:   6,
+			want: []int64{7},
+		},
+	}
+	// corrupted tree: potatoes (6) -> tubers (7) -> potatoes (6)
+	categories = append(categories, &Category{ID: 6, Name: "potatoes", ParentID: 7}, &Category{ID: 7, Name: "tubers", ParentID: 6})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, c := range Descendants(categories, tt.id) {
+				got = append(got, c.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Descendants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
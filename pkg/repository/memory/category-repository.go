@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// categoryList returns all Categories ordered by ID
+func (d *data) categoryList() []repository.Category {
+	list := make([]repository.Category, 0, len(d.categories))
+	for _, c := range d.categories {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// categoryTree returns all Categories ordered by ID as input of repository.Descendants
+func (d *data) categoryTree() []*repository.Category {
+	list := d.categoryList()
+	tree := make([]*repository.Category, len(list))
+	for i := range list {
+		tree[i] = &list[i]
+	}
+	return tree
+}
+
+// filterCategories returns set of IDs of the filter Category and all its subcategories
+func (d *data) filterCategories(f repository.Filter) map[int64]bool {
+	if f.CategoryID == 0 {
+		return nil
+	}
+
+	ids := map[int64]bool{f.CategoryID: true}
+	for _, c := range repository.Descendants(d.categoryTree(), f.CategoryID) {
+		ids[c.ID] = true
+	}
+	return ids
+}
+
+// checkCategory checks that Category referenced by Product exists, 0 references no Category
+func (s *store) checkCategory(id int64) error {
+	if _, ok := s.d.categories[id]; id != 0 && !ok {
+		return &repository.CategoryNotFoundError{ID: id}
+	}
+	return nil
+}
+
+// CreateCategory saves new Category with next ID
+func (r *productRepository) CreateCategory(ctx context.Context, c *repository.Category) (*repository.Category, error) {
+	var created *repository.Category
+	err := r.write(func(s *store) error {
+		if err := s.checkCategory(c.ParentID); err != nil {
+			return err
+		}
+
+		cat := *c
+		cat.ID = s.d.newCategoryID()
+		s.d.putCategory(cat)
+		created = &cat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetCategory returns Category by ID
+func (r *productRepository) GetCategory(ctx context.Context, id int64) (*repository.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.d.categories[id]
+	if !ok {
+		return nil, &repository.CategoryNotFoundError{ID: id}
+	}
+	return &c, nil
+}
+
+// UpdateCategory writes name of Category
+func (r *productRepository) UpdateCategory(ctx context.Context, c *repository.Category) (*repository.Category, error) {
+	var updated *repository.Category
+	err := r.write(func(s *store) error {
+		cat, ok := s.d.categories[c.ID]
+		if !ok {
+			return &repository.CategoryNotFoundError{ID: c.ID}
+		}
+
+		cat.Name = c.Name
+		s.d.putCategory(cat)
+		updated = &cat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// MoveCategory writes parent of Category, it fails if the parent is the Category or any of its subcategories
+func (r *productRepository) MoveCategory(ctx context.Context, id int64, parentID int64) (*repository.Category, error) {
+	var moved *repository.Category
+	err := r.write(func(s *store) error {
+		cat, ok := s.d.categories[id]
+		if !ok {
+			return &repository.CategoryNotFoundError{ID: id}
+		}
+		if err := s.checkCategory(parentID); err != nil {
+			return err
+		}
+		if parentID == id {
+			return &repository.CategoryCycleError{ID: id, ParentID: parentID}
+		}
+		for _, c := range repository.Descendants(s.d.categoryTree(), id) {
+			if c.ID == parentID {
+				return &repository.CategoryCycleError{ID: id, ParentID: parentID}
+			}
+		}
+
+		cat.ParentID = parentID
+		s.d.putCategory(cat)
+		moved = &cat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// DeleteCategory removes Category which has neither subcategories nor Products
+func (r *productRepository) DeleteCategory(ctx context.Context, id int64) error {
+	return r.write(func(s *store) error {
+		if _, ok := s.d.categories[id]; !ok {
+			return &repository.CategoryNotFoundError{ID: id}
+		}
+		for _, c := range s.d.categories {
+			if c.ParentID == id {
+				return &repository.CategoryInUseError{ID: id}
+			}
+		}
+		for _, p := range s.d.products {
+			if p.CategoryID == id {
+				return &repository.CategoryInUseError{ID: id}
+			}
+		}
+
+		s.d.removeCategory(id)
+		return nil
+	})
+}
+
+// ListCategories returns all Categories ordered by ID
+func (r *productRepository) ListCategories(ctx context.Context) ([]*repository.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.d.categoryTree(), nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_productRepository_categories(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t)
+
+	// food (1) -> vegetable (2) -> roots (3)
+	for _, c := range []repository.Category{{Name: "food"}, {Name: "vegetable", ParentID: 1}, {Name: "roots", ParentID: 2}} {
+		if _, err := r.CreateCategory(ctx, &c); err != nil {
+			t.Fatalf("productRepository.CreateCategory() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{
+			name: "Create under unknown parent",
+			call: func() error {
+				_, err := r.CreateCategory(ctx, &repository.Category{Name: "fruit", ParentID: 7})
+				return err
+			},
+			wantErr: &repository.CategoryNotFoundError{ID: 7},
+		},
+		{
+			name: "Update unknown",
+			call: func() error {
+				_, err := r.UpdateCategory(ctx, &repository.Category{ID: 7, Name: "fruit"})
+				return err
+			},
+			wantErr: &repository.CategoryNotFoundError{ID: 7},
+		},
+		{
+			name: "Move under itself",
+			call: func() error {
+				_, err := r.MoveCategory(ctx, 2, 2)
+				return err
+			},
+			wantErr: &repository.CategoryCycleError{ID: 2, ParentID: 2},
+		},
+		{
+			name: "Move under subcategory",
+			call: func() error {
+				_, err := r.MoveCategory(ctx, 1, 3)
+				return err
+			},
+			wantErr: &repository.CategoryCycleError{ID: 1, ParentID: 3},
+		},
+		{
+			name: "Move under unknown parent",
+			call: func() error {
+				_, err := r.MoveCategory(ctx, 3, 7)
+				return err
+			},
+			wantErr: &repository.CategoryNotFoundError{ID: 7},
+		},
+		{
+			name:    "Delete with subcategories",
+			call:    func() error { return r.DeleteCategory(ctx, 2) },
+			wantErr: &repository.CategoryInUseError{ID: 2},
+		},
+		{
+			name: "Delete with Products",
+			call: func() error {
+				if _, err := r.Create(ctx, &repository.Product{Name: "potato", CategoryID: 3}); err != nil {
+					return err
+				}
+				return r.DeleteCategory(ctx, 3)
+			},
+			wantErr: &repository.CategoryInUseError{ID: 3},
+		},
+		{
+			name: "Product of unknown Category",
+			call: func() error {
+				_, err := r.Update(ctx, &repository.Product{ID: 1, CategoryID: 7}, []repository.Field{repository.FieldCategoryID}, 0)
+				return err
+			},
+			wantErr: &repository.CategoryNotFoundError{ID: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := r.UpdateCategory(ctx, &repository.Category{ID: 2, Name: "vegetables", ParentID: 3}); err != nil {
+		t.Fatalf("productRepository.UpdateCategory() error = %v", err)
+	}
+	if _, err := r.MoveCategory(ctx, 3, 0); err != nil {
+		t.Fatalf("productRepository.MoveCategory() error = %v", err)
+	}
+	if err := r.DeleteCategory(ctx, 2); err != nil {
+		t.Fatalf("productRepository.DeleteCategory() error = %v", err)
+	}
+	got, err := r.ListCategories(ctx)
+	want := []*repository.Category{{ID: 1, Name: "food"}, {ID: 3, Name: "roots"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("productRepository.ListCategories() = %v, %v, want %v", got, err, want)
+	}
+}
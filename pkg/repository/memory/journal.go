@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// minCompactSize is journal size in bytes below which it isn't compacted, so that small snapshot
+// isn't rewritten by every write
+const minCompactSize = 64 << 10
+
+// journalFile returns path of journal of the snapshot file
+func journalFile(snapshot string) string {
+	return snapshot + ".journal"
+}
+
+// journalRecord is writes of committed transaction as they are appended to journal, one JSON object per line.
+// It keeps written values rather than operations, so that it can be replayed on top of snapshot
+// which contains it already.
+type journalRecord struct {
+	NextID                 int64                       `json:"next_id"`
+	NextCategoryID         int64                       `json:"next_category_id"`
+	Products               []repository.Product        `json:"products,omitempty"`
+	RemovedProducts        []int64                     `json:"removed_products,omitempty"`
+	Categories             []repository.Category       `json:"categories,omitempty"`
+	RemovedCategories      []int64                     `json:"removed_categories,omitempty"`
+	IdempotencyKeys        []repository.IdempotencyKey `json:"idempotency_keys,omitempty"`
+	RemovedIdempotencyKeys []string                    `json:"removed_idempotency_keys,omitempty"`
+}
+
+// newJournalRecord returns record of writes of the running transaction of d
+func newJournalRecord(d *data) *journalRecord {
+	rec := &journalRecord{NextID: d.nextID, NextCategoryID: d.nextCategoryID}
+	for _, id := range sortedIDs(d.log.products) {
+		if p, ok := d.products[id]; ok {
+			rec.Products = append(rec.Products, p)
+		} else {
+			rec.RemovedProducts = append(rec.RemovedProducts, id)
+		}
+	}
+	for _, id := range sortedIDs(d.log.categories) {
+		if c, ok := d.categories[id]; ok {
+			rec.Categories = append(rec.Categories, c)
+		} else {
+			rec.RemovedCategories = append(rec.RemovedCategories, id)
+		}
+	}
+	requestIDs := make([]string, 0, len(d.log.idempotencyKeys))
+	for requestID := range d.log.idempotencyKeys {
+		requestIDs = append(requestIDs, requestID)
+	}
+	sort.Strings(requestIDs)
+	for _, requestID := range requestIDs {
+		if k, ok := d.idempotencyKeys[requestID]; ok {
+			rec.IdempotencyKeys = append(rec.IdempotencyKeys, k)
+		} else {
+			rec.RemovedIdempotencyKeys = append(rec.RemovedIdempotencyKeys, requestID)
+		}
+	}
+	return rec
+}
+
+// sortedIDs returns IDs of the set in ascending order
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// apply writes the record to d
+func (rec *journalRecord) apply(d *data) {
+	if rec.NextID > d.nextID {
+		d.nextID = rec.NextID
+	}
+	if rec.NextCategoryID > d.nextCategoryID {
+		d.nextCategoryID = rec.NextCategoryID
+	}
+	for _, p := range rec.Products {
+		d.putProduct(p)
+	}
+	for _, id := range rec.RemovedProducts {
+		d.removeProduct(id)
+	}
+	for _, c := range rec.Categories {
+		d.putCategory(c)
+	}
+	for _, id := range rec.RemovedCategories {
+		d.removeCategory(id)
+	}
+	for _, k := range rec.IdempotencyKeys {
+		d.putIdempotencyKey(k)
+	}
+	for _, requestID := range rec.RemovedIdempotencyKeys {
+		d.removeIdempotencyKey(requestID)
+	}
+}
+
+// journal is file of writes committed after the snapshot was written
+type journal struct {
+	f *os.File
+	// size is size of the complete records of the file in bytes, the next record is written at it
+	size int64
+}
+
+// openJournal replays journal file on top of d and opens it for appending, the file is created if it doesn't exist.
+// Record cut off by crash at the end of the file is dropped, because its transaction didn't commit.
+func openJournal(file string, d *data) (*journal, error) {
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.New("failed to open journal-> " + err.Error())
+	}
+	j := &journal{f: f}
+	if err := j.replay(d); err != nil {
+		f.Close()
+		return nil, err
+	}
+	// journal file must not disappear on crash once records are appended to it
+	if err := syncDir(filepath.Dir(file)); err != nil {
+		f.Close()
+		return nil, errors.New("failed to sync journal directory-> " + err.Error())
+	}
+	return j, nil
+}
+
+// replay applies complete records of the journal file to d, and cuts off incomplete one
+func (j *journal) replay(d *data) error {
+	b, err := ioutil.ReadAll(j.f)
+	if err != nil {
+		return errors.New("failed to read journal-> " + err.Error())
+	}
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+		var rec journalRecord
+		if err := json.Unmarshal(b[:i], &rec); err != nil {
+			return errors.New("failed to parse journal-> " + err.Error())
+		}
+		rec.apply(d)
+		j.size += int64(i + 1)
+		b = b[i+1:]
+	}
+	return j.reset(j.size)
+}
+
+// reset cuts the file off at size and moves to its end
+func (j *journal) reset(size int64) error {
+	if err := j.f.Truncate(size); err != nil {
+		return errors.New("failed to truncate journal-> " + err.Error())
+	}
+	if _, err := j.f.Seek(size, io.SeekStart); err != nil {
+		return errors.New("failed to seek journal-> " + err.Error())
+	}
+	j.size = size
+	return nil
+}
+
+// append writes record of the running transaction of d and syncs it to disk.
+// Transaction is committed once it returns nil, partially written record is cut off if it fails.
+func (j *journal) append(d *data) error {
+	if len(d.log.undo) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(newJournalRecord(d))
+	if err != nil {
+		return errors.New("failed to encode journal record-> " + err.Error())
+	}
+	b = append(b, '\n')
+	if _, err := j.f.Write(b); err != nil {
+		j.reset(j.size)
+		return errors.New("failed to write journal-> " + err.Error())
+	}
+	if err := j.f.Sync(); err != nil {
+		j.reset(j.size)
+		return errors.New("failed to sync journal-> " + err.Error())
+	}
+	j.size += int64(len(b))
+	return nil
+}
+
+// truncate removes all records, it is called once they are written to synced snapshot
+func (j *journal) truncate() error {
+	if err := j.reset(0); err != nil {
+		return err
+	}
+	if err := j.f.Sync(); err != nil {
+		return errors.New("failed to sync journal-> " + err.Error())
+	}
+	return nil
+}
+
+// writeFileSync replaces file with data atomically: data is written to temporary file and synced to disk,
+// then it is renamed to the file and the rename is synced too
+func writeFileSync(file string, data []byte) error {
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(file))
+}
+
+// syncDir syncs entries of directory to disk, e.g. after file is created or renamed in it
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
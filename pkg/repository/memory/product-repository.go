@@ -0,0 +1,703 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// errReadOnly is returned by writes in read only transaction
+var errReadOnly = errors.New("failed to write Product-> transaction is read only")
+
+// data is content of the repository
+type data struct {
+	products map[int64]repository.Product
+	// nextID is ID assigned to the next created Product
+	nextID     int64
+	categories map[int64]repository.Category
+	// nextCategoryID is ID assigned to the next created Category
+	nextCategoryID int64
+	// idempotencyKeys are idempotency keys by request ID
+	idempotencyKeys map[string]repository.IdempotencyKey
+	// log records writes of the running transaction, it is nil outside of write transactions
+	log *txLog
+}
+
+// txLog records writes of transaction, so that they can be undone and appended to journal
+type txLog struct {
+	// undo restore the data written by transaction one by one, they run in reverse order
+	undo []func()
+	// products, categories and idempotencyKeys are written IDs and request IDs
+	products        map[int64]bool
+	categories      map[int64]bool
+	idempotencyKeys map[string]bool
+}
+
+func newData() *data {
+	return &data{
+		products:        map[int64]repository.Product{},
+		nextID:          1,
+		categories:      map[int64]repository.Category{},
+		nextCategoryID:  1,
+		idempotencyKeys: map[string]repository.IdempotencyKey{},
+	}
+}
+
+// begin starts recording of writes
+func (d *data) begin() {
+	d.log = &txLog{products: map[int64]bool{}, categories: map[int64]bool{}, idempotencyKeys: map[string]bool{}}
+}
+
+// rollback undoes writes recorded after the first n ones
+func (d *data) rollback(n int) {
+	for i := len(d.log.undo) - 1; i >= n; i-- {
+		d.log.undo[i]()
+	}
+	d.log.undo = d.log.undo[:n]
+}
+
+// onUndo records how to undo write unless writes aren't recorded
+func (d *data) onUndo(undo func()) {
+	if d.log != nil {
+		d.log.undo = append(d.log.undo, undo)
+	}
+}
+
+// newProductID returns ID of the next created Product
+func (d *data) newProductID() int64 {
+	id := d.nextID
+	d.nextID++
+	d.onUndo(func() { d.nextID = id })
+	return id
+}
+
+// newCategoryID returns ID of the next created Category
+func (d *data) newCategoryID() int64 {
+	id := d.nextCategoryID
+	d.nextCategoryID++
+	d.onUndo(func() { d.nextCategoryID = id })
+	return id
+}
+
+// touchProduct records write of Product
+func (d *data) touchProduct(id int64) {
+	if d.log == nil {
+		return
+	}
+	old, ok := d.products[id]
+	d.onUndo(func() {
+		if ok {
+			d.products[id] = old
+		} else {
+			delete(d.products, id)
+		}
+	})
+	d.log.products[id] = true
+}
+
+// putProduct inserts or replaces Product
+func (d *data) putProduct(p repository.Product) {
+	d.touchProduct(p.ID)
+	d.products[p.ID] = p
+}
+
+// removeProduct removes Product
+func (d *data) removeProduct(id int64) {
+	d.touchProduct(id)
+	delete(d.products, id)
+}
+
+// touchCategory records write of Category
+func (d *data) touchCategory(id int64) {
+	if d.log == nil {
+		return
+	}
+	old, ok := d.categories[id]
+	d.onUndo(func() {
+		if ok {
+			d.categories[id] = old
+		} else {
+			delete(d.categories, id)
+		}
+	})
+	d.log.categories[id] = true
+}
+
+// putCategory inserts or replaces Category
+func (d *data) putCategory(c repository.Category) {
+	d.touchCategory(c.ID)
+	d.categories[c.ID] = c
+}
+
+// removeCategory removes Category
+func (d *data) removeCategory(id int64) {
+	d.touchCategory(id)
+	delete(d.categories, id)
+}
+
+// touchIdempotencyKey records write of idempotency key
+func (d *data) touchIdempotencyKey(requestID string) {
+	if d.log == nil {
+		return
+	}
+	old, ok := d.idempotencyKeys[requestID]
+	d.onUndo(func() {
+		if ok {
+			d.idempotencyKeys[requestID] = old
+		} else {
+			delete(d.idempotencyKeys, requestID)
+		}
+	})
+	d.log.idempotencyKeys[requestID] = true
+}
+
+// putIdempotencyKey inserts or replaces idempotency key
+func (d *data) putIdempotencyKey(k repository.IdempotencyKey) {
+	d.touchIdempotencyKey(k.RequestID)
+	d.idempotencyKeys[k.RequestID] = k
+}
+
+// removeIdempotencyKey removes idempotency key
+func (d *data) removeIdempotencyKey(requestID string) {
+	d.touchIdempotencyKey(requestID)
+	delete(d.idempotencyKeys, requestID)
+}
+
+// snapshot is content of the repository as it is saved to file
+type snapshot struct {
+	NextID          int64                       `json:"next_id"`
+	Products        []snapshotProduct           `json:"products"`
+	NextCategoryID  int64                       `json:"next_category_id"`
+	Categories      []repository.Category       `json:"categories"`
+	IdempotencyKeys []repository.IdempotencyKey `json:"idempotency_keys,omitempty"`
+}
+
+// snapshotProduct is Product as it is saved to file,
+// snapshots written before Categories were introduced keep category name in Category field
+type snapshotProduct struct {
+	repository.Product
+	Category string `json:"Category,omitempty"`
+}
+
+// store implements repository.ProductStore on top of data,
+// caller is responsible for locking
+type store struct {
+	d        *data
+	readOnly bool
+}
+
+// productRepository is in-memory implementation of repository.Repository.
+// Writes are serialized, every write runs in transaction writing data of the repository in place,
+// its writes are undone if it fails. Committed writes are appended to journal, which is compacted
+// into snapshot file when it grows bigger than the snapshot.
+type productRepository struct {
+	mu sync.RWMutex
+	d  *data
+	// file is path to snapshot file, empty if snapshotting is off
+	file string
+	// journal is open journal file of the snapshot, nil if snapshotting is off
+	journal *journal
+	// snapshotSize is size of the snapshot file in bytes
+	snapshotSize int64
+}
+
+// productTx is in-memory implementation of repository.ProductTx
+type productTx struct {
+	store
+}
+
+// NewRepository creates repository of Products and Categories kept in memory.
+// If file is set, data is loaded from it and its journal, and every committed write is saved to the journal.
+func NewRepository(file string) (repository.Repository, error) {
+	r := &productRepository{d: newData(), file: file}
+	if len(file) == 0 {
+		return r, nil
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	j, err := openJournal(journalFile(file), r.d)
+	if err != nil {
+		return nil, err
+	}
+	r.journal = j
+	return r, nil
+}
+
+// load reads data from snapshot file, it does nothing if the file doesn't exist
+func (r *productRepository) load() error {
+	b, err := ioutil.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.New("failed to read snapshot-> " + err.Error())
+	}
+	r.snapshotSize = int64(len(b))
+
+	var s snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.New("failed to parse snapshot-> " + err.Error())
+	}
+	for _, c := range s.Categories {
+		r.d.categories[c.ID] = c
+		if c.ID >= r.d.nextCategoryID {
+			r.d.nextCategoryID = c.ID + 1
+		}
+	}
+	if s.NextCategoryID > r.d.nextCategoryID {
+		r.d.nextCategoryID = s.NextCategoryID
+	}
+	legacy := map[string]int64{}
+	for _, sp := range s.Products {
+		p := sp.Product
+		if len(sp.Category) > 0 && p.CategoryID == 0 {
+			p.CategoryID = r.d.legacyCategory(legacy, sp.Category)
+		}
+		r.d.products[p.ID] = p
+		if p.ID >= r.d.nextID {
+			r.d.nextID = p.ID + 1
+		}
+	}
+	if s.NextID > r.d.nextID {
+		r.d.nextID = s.NextID
+	}
+	for _, k := range s.IdempotencyKeys {
+		r.d.idempotencyKeys[k.RequestID] = k
+	}
+	return nil
+}
+
+// legacyCategory returns ID of root Category created for the category name of legacy snapshot,
+// it is created once per name
+func (d *data) legacyCategory(created map[string]int64, name string) int64 {
+	if id, ok := created[name]; ok {
+		return id
+	}
+	c := repository.Category{ID: d.newCategoryID(), Name: name}
+	d.categories[c.ID] = c
+	created[name] = c.ID
+	return c.ID
+}
+
+// compact writes data to snapshot file and empties the journal. Snapshot file is replaced atomically
+// so that it is never left half-written, journal is emptied after the snapshot is synced to disk.
+func (r *productRepository) compact() error {
+	d := r.d
+	s := snapshot{
+		NextID:         d.nextID,
+		Products:       make([]snapshotProduct, 0, len(d.products)),
+		NextCategoryID: d.nextCategoryID,
+		Categories:     d.categoryList(),
+	}
+	for _, p := range d.products {
+		s.Products = append(s.Products, snapshotProduct{Product: p})
+	}
+	sort.Slice(s.Products, func(i, j int) bool { return s.Products[i].ID < s.Products[j].ID })
+	for _, k := range d.idempotencyKeys {
+		s.IdempotencyKeys = append(s.IdempotencyKeys, k)
+	}
+	sort.Slice(s.IdempotencyKeys, func(i, j int) bool { return s.IdempotencyKeys[i].RequestID < s.IdempotencyKeys[j].RequestID })
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return errors.New("failed to encode snapshot-> " + err.Error())
+	}
+	if err := writeFileSync(r.file, b); err != nil {
+		return errors.New("failed to write snapshot-> " + err.Error())
+	}
+	r.snapshotSize = int64(len(b))
+	return r.journal.truncate()
+}
+
+// commit appends writes of the transaction to the journal, journal is compacted if it is bigger than the snapshot
+func (r *productRepository) commit() error {
+	if r.journal == nil {
+		return nil
+	}
+	if err := r.journal.append(r.d); err != nil {
+		return err
+	}
+
+	if r.journal.size < minCompactSize || r.journal.size < r.snapshotSize {
+		return nil
+	}
+	// writes are durable in the journal already, compaction is retried by the next write if it fails
+	r.compact()
+	return nil
+}
+
+// Create saves new Product with next ID
+func (r *productRepository) Create(ctx context.Context, p *repository.Product) (*repository.Product, error) {
+	var created *repository.Product
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		created, err = tx.Create(ctx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Get returns Product by ID
+func (r *productRepository) Get(ctx context.Context, id int64) (*repository.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store().Get(ctx, id)
+}
+
+// Update writes the fields of Product
+func (r *productRepository) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	var updated *repository.Product
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		updated, err = tx.Update(ctx, p, fields, expectedRevision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete marks Product as deleted
+func (r *productRepository) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	var revision int64
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		revision, err = tx.Delete(ctx, id, expectedRevision)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return revision, nil
+}
+
+// Undelete clears deletion mark of Product
+func (r *productRepository) Undelete(ctx context.Context, id int64) (*repository.Product, error) {
+	var undeleted *repository.Product
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		undeleted, err = tx.Undelete(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return undeleted, nil
+}
+
+// Purge removes Products marked as deleted before the time
+func (r *productRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.write(func(s *store) error {
+		for id, p := range s.d.products {
+			if !p.DeletedAt.IsZero() && p.DeletedAt.Before(deletedBefore) {
+				s.d.removeProduct(id)
+				purged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// PurgeIdempotencyKeys removes idempotency keys expired before the time
+func (r *productRepository) PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.write(func(s *store) error {
+		for requestID, k := range s.d.idempotencyKeys {
+			if k.ExpiresAt.Before(expiredBefore) {
+				s.d.removeIdempotencyKey(requestID)
+				purged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// GetIdempotencyKey returns idempotency key by request ID unless it is expired
+func (r *productRepository) GetIdempotencyKey(ctx context.Context, requestID string) (*repository.IdempotencyKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store().GetIdempotencyKey(ctx, requestID)
+}
+
+// SaveIdempotencyKey saves idempotency key
+func (r *productRepository) SaveIdempotencyKey(ctx context.Context, k *repository.IdempotencyKey) error {
+	return r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		return tx.SaveIdempotencyKey(ctx, k)
+	})
+}
+
+// List selects Products
+func (r *productRepository) List(ctx context.Context, q repository.ListQuery) ([]*repository.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store().List(ctx, q)
+}
+
+// Stream selects Products and passes them to fn, the lock is released before fn is called
+// so that slow consumer doesn't block writes
+func (r *productRepository) Stream(ctx context.Context, q repository.ListQuery, fn func(p *repository.Product) error) error {
+	r.mu.RLock()
+	selected, err := list(r.d, q)
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range selected {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count counts Products matching the filter
+func (r *productRepository) Count(ctx context.Context, f repository.Filter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store().Count(ctx, f)
+}
+
+// InTx runs fn in transaction. Read only transactions run concurrently,
+// other transactions are serialized, their writes are undone unless fn succeeds and they are saved to the journal.
+// fn must access Products through tx only, calling the repository would deadlock.
+func (r *productRepository) InTx(ctx context.Context, readOnly bool, fn func(tx repository.ProductTx) error) error {
+	if readOnly {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		return fn(&productTx{store: *r.store()})
+	}
+
+	return r.write(func(s *store) error {
+		return fn(&productTx{store: *s})
+	})
+}
+
+// write runs fn with store writing data of the repository, writes are undone unless fn succeeds
+// and they are saved to the journal
+func (r *productRepository) write(fn func(s *store) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.d.begin()
+	committed := false
+	defer func() {
+		// writes are undone if fn panics too
+		if !committed {
+			r.d.rollback(0)
+		}
+		r.d.log = nil
+	}()
+
+	if err := fn(&store{d: r.d}); err != nil {
+		return err
+	}
+	if err := r.commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// store returns read only store of the repository data
+func (r *productRepository) store() *store {
+	return &store{d: r.d, readOnly: true}
+}
+
+// Savepoint runs fn and restores data saved before fn if it fails
+func (t *productTx) Savepoint(ctx context.Context, fn func() error) error {
+	if t.readOnly {
+		return fn()
+	}
+
+	n := len(t.d.log.undo)
+	if err := fn(); err != nil {
+		t.d.rollback(n)
+		return err
+	}
+	return nil
+}
+
+// Create inserts Product with next ID
+func (s *store) Create(ctx context.Context, p *repository.Product) (*repository.Product, error) {
+	if s.readOnly {
+		return nil, errReadOnly
+	}
+
+	if err := s.checkCategory(p.CategoryID); err != nil {
+		return nil, err
+	}
+
+	created := *p
+	created.ID = s.d.newProductID()
+	created.Revision = 1
+	s.d.putProduct(created)
+	return &created, nil
+}
+
+// Get returns copy of Product by ID unless it is deleted
+func (s *store) Get(ctx context.Context, id int64) (*repository.Product, error) {
+	p, ok := s.d.products[id]
+	if !ok || !p.DeletedAt.IsZero() {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	return &p, nil
+}
+
+// written returns Product for conditional write: it must exist, it must not be deleted
+// and it must have the expected revision unless it is 0
+func (s *store) written(id int64, expectedRevision int64) (repository.Product, error) {
+	p, ok := s.d.products[id]
+	if !ok || !p.DeletedAt.IsZero() {
+		return repository.Product{}, &repository.NotFoundError{ID: id}
+	}
+	if expectedRevision != 0 && p.Revision != expectedRevision {
+		return repository.Product{}, &repository.RevisionMismatchError{ID: id, Revision: p.Revision, Expected: expectedRevision}
+	}
+	return p, nil
+}
+
+// Update writes the fields of Product and bumps its revision
+func (s *store) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	if s.readOnly {
+		return nil, errReadOnly
+	}
+
+	updated, err := s.written(p.ID, expectedRevision)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyUpdate(&updated, p, fields); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(updated.CategoryID); err != nil {
+		return nil, err
+	}
+	updated.Revision++
+	s.d.putProduct(updated)
+	return &updated, nil
+}
+
+// Delete marks Product as deleted and bumps its revision
+func (s *store) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	if s.readOnly {
+		return 0, errReadOnly
+	}
+
+	deleted, err := s.written(id, expectedRevision)
+	if err != nil {
+		return 0, err
+	}
+	deleted.DeletedAt = time.Now().UTC()
+	deleted.Revision++
+	s.d.putProduct(deleted)
+	return deleted.Revision, nil
+}
+
+// Undelete clears deletion mark of Product and bumps its revision
+func (s *store) Undelete(ctx context.Context, id int64) (*repository.Product, error) {
+	if s.readOnly {
+		return nil, errReadOnly
+	}
+
+	undeleted, ok := s.d.products[id]
+	if !ok || undeleted.DeletedAt.IsZero() {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	undeleted.DeletedAt = time.Time{}
+	undeleted.Revision++
+	s.d.putProduct(undeleted)
+	return &undeleted, nil
+}
+
+// List selects Products
+func (s *store) List(ctx context.Context, q repository.ListQuery) ([]*repository.Product, error) {
+	selected, err := list(s.d, q)
+	if err != nil {
+		return nil, err
+	}
+	if selected == nil {
+		selected = []*repository.Product{}
+	}
+	return selected, nil
+}
+
+// Stream selects Products and passes them to fn one by one
+func (s *store) Stream(ctx context.Context, q repository.ListQuery, fn func(p *repository.Product) error) error {
+	selected, err := list(s.d, q)
+	if err != nil {
+		return err
+	}
+	for _, p := range selected {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count counts Products matching the filter
+func (s *store) Count(ctx context.Context, f repository.Filter) (int64, error) {
+	var total int64
+	categories := s.d.filterCategories(f)
+	for id := range s.d.products {
+		p := s.d.products[id]
+		if match(f, categories, &p) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// GetIdempotencyKey returns copy of idempotency key by request ID unless it is expired
+func (s *store) GetIdempotencyKey(ctx context.Context, requestID string) (*repository.IdempotencyKey, error) {
+	k, ok := s.d.idempotencyKeys[requestID]
+	if !ok || !k.ExpiresAt.After(time.Now()) {
+		return nil, &repository.IdempotencyKeyNotFoundError{RequestID: requestID}
+	}
+	return &k, nil
+}
+
+// SaveIdempotencyKey saves idempotency key unless the request ID is saved already and it isn't expired
+func (s *store) SaveIdempotencyKey(ctx context.Context, k *repository.IdempotencyKey) error {
+	if s.readOnly {
+		return errReadOnly
+	}
+
+	if saved, ok := s.d.idempotencyKeys[k.RequestID]; ok && saved.ExpiresAt.After(time.Now()) {
+		return &repository.StorageError{
+			Kind: repository.KindDuplicate,
+			Op:   "failed to save idempotency key",
+			Err:  fmt.Errorf("request ID='%s' is saved already", k.RequestID),
+		}
+	}
+	saved := *k
+	saved.ExpiresAt = saved.ExpiresAt.UTC()
+	s.d.putIdempotencyKey(saved)
+	return nil
+}
@@ -0,0 +1,695 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// newRepository creates repository without snapshot file containing the Products
+func newRepository(t *testing.T, products ...repository.Product) repository.Repository {
+	r, err := NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	ctx := context.Background()
+	for _, p := range products {
+		if _, err := r.Create(ctx, &p); err != nil {
+			t.Fatalf("failed to create Product: %v", err)
+		}
+	}
+	return r
+}
+
+func Test_productRepository_Create(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t)
+	tm := time.Now().In(time.UTC)
+
+	for i := int64(1); i <= 2; i++ {
+		got, err := r.Create(ctx, &repository.Product{ID: 10, Name: "name", Date: tm, Revision: 5})
+		if err != nil {
+			t.Fatalf("productRepository.Create() error = %v", err)
+		}
+		want := &repository.Product{ID: i, Name: "name", Date: tm, Revision: 1}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("productRepository.Create() = %v, want %v", got, want)
+		}
+	}
+}
+
+func Test_productRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t, repository.Product{Name: "name"})
+
+	tests := []struct {
+		name    string
+		id      int64
+		want    *repository.Product
+		wantErr error
+	}{
+		{
+			name: "OK",
+			id:   1,
+			want: &repository.Product{ID: 1, Name: "name", Revision: 1},
+		},
+		{
+			name:    "Not found",
+			id:      2,
+			wantErr: &repository.NotFoundError{ID: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Get(ctx, tt.id)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name             string
+		p                *repository.Product
+		fields           []repository.Field
+		expectedRevision int64
+		want             *repository.Product
+		wantErr          error
+	}{
+		{
+			name: "OK",
+			p:    &repository.Product{ID: 1, Name: "new name", Price: money.Amount{Currency: "EUR", Minor: 200}, Date: tm},
+			want: &repository.Product{ID: 1, Name: "new name", Price: money.Amount{Currency: "EUR", Minor: 200}, Date: tm, Revision: 2},
+		},
+		{
+			name:   "Partial",
+			p:      &repository.Product{ID: 1, Name: "new name", Price: money.Amount{Currency: "EUR", Minor: 200}},
+			fields: []repository.Field{repository.FieldPrice},
+			want:   &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 200}, Unit: "kg", Revision: 2},
+		},
+		{
+			name:             "Expected revision",
+			p:                &repository.Product{ID: 1, Name: "new name"},
+			fields:           []repository.Field{repository.FieldName},
+			expectedRevision: 1,
+			want:             &repository.Product{ID: 1, Name: "new name", Price: money.Amount{Currency: "EUR", Minor: 100}, Unit: "kg", Revision: 2},
+		},
+		{
+			name:             "Revision mismatch",
+			p:                &repository.Product{ID: 1, Name: "new name"},
+			expectedRevision: 3,
+			wantErr:          &repository.RevisionMismatchError{ID: 1, Revision: 1, Expected: 3},
+		},
+		{
+			name:    "Not found",
+			p:       &repository.Product{ID: 2, Name: "new name"},
+			wantErr: &repository.NotFoundError{ID: 2},
+		},
+		{
+			name:    "Unsupported field",
+			p:       &repository.Product{ID: 1},
+			fields:  []repository.Field{repository.FieldID},
+			wantErr: errors.New("updating field 'id' is not supported"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRepository(t, repository.Product{Name: "name", Price: money.Amount{Currency: "EUR", Minor: 100}, Unit: "kg"})
+			got, err := r.Update(ctx, tt.p, tt.fields, tt.expectedRevision)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Update() = %v, want %v", got, tt.want)
+			}
+			if stored, _ := r.Get(ctx, 1); tt.want != nil && !reflect.DeepEqual(stored, tt.want) {
+				t.Errorf("productRepository.Update() stored %v, want %v", stored, tt.want)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		id               int64
+		expectedRevision int64
+		wantErr          error
+	}{
+		{
+			name: "OK",
+			id:   1,
+		},
+		{
+			name:             "Expected revision",
+			id:               1,
+			expectedRevision: 1,
+		},
+		{
+			name:             "Revision mismatch",
+			id:               1,
+			expectedRevision: 2,
+			wantErr:          &repository.RevisionMismatchError{ID: 1, Revision: 1, Expected: 2},
+		},
+		{
+			name:    "Not found",
+			id:      2,
+			wantErr: &repository.NotFoundError{ID: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRepository(t, repository.Product{Name: "name"})
+			revision, err := r.Delete(ctx, tt.id, tt.expectedRevision)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && revision != 2 {
+				t.Errorf("productRepository.Delete() = %d, want 2", revision)
+			}
+			if _, err := r.Get(ctx, 1); (err == nil) != (tt.wantErr != nil) {
+				t.Errorf("productRepository.Delete() left Product, error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Undelete(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t, repository.Product{Name: "name 1"}, repository.Product{Name: "name 2"})
+	if _, err := r.Delete(ctx, 1, 0); err != nil {
+		t.Fatalf("productRepository.Delete() error = %v", err)
+	}
+
+	if _, err := r.Delete(ctx, 1, 0); !reflect.DeepEqual(err, &repository.NotFoundError{ID: 1}) {
+		t.Errorf("productRepository.Delete() error = %v, want NotFoundError", err)
+	}
+	if total, err := r.Count(ctx, repository.Filter{}); err != nil || total != 1 {
+		t.Errorf("productRepository.Count() = %d, %v, want 1", total, err)
+	}
+	list, err := r.List(ctx, repository.ListQuery{Filter: repository.Filter{ShowDeleted: true}})
+	if err != nil || len(list) != 2 || list[0].DeletedAt.IsZero() || !list[1].DeletedAt.IsZero() {
+		t.Errorf("productRepository.List() = %v, %v, want deleted Product 1 and Product 2", list, err)
+	}
+
+	got, err := r.Undelete(ctx, 1)
+	want := &repository.Product{ID: 1, Name: "name 1", Revision: 3}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("productRepository.Undelete() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := r.Undelete(ctx, 2); !reflect.DeepEqual(err, &repository.NotFoundError{ID: 2}) {
+		t.Errorf("productRepository.Undelete() error = %v, want NotFoundError", err)
+	}
+}
+
+func Test_productRepository_Purge(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t, repository.Product{Name: "name 1"}, repository.Product{Name: "name 2"})
+	if _, err := r.Delete(ctx, 1, 0); err != nil {
+		t.Fatalf("productRepository.Delete() error = %v", err)
+	}
+
+	if purged, err := r.Purge(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("productRepository.Purge() = %d, %v, want 0", purged, err)
+	}
+	if purged, err := r.Purge(ctx, time.Now().Add(time.Minute)); err != nil || purged != 1 {
+		t.Errorf("productRepository.Purge() = %d, %v, want 1", purged, err)
+	}
+	if _, err := r.Undelete(ctx, 1); !reflect.DeepEqual(err, &repository.NotFoundError{ID: 1}) {
+		t.Errorf("productRepository.Undelete() error = %v, want NotFoundError", err)
+	}
+	if total, err := r.Count(ctx, repository.Filter{ShowDeleted: true}); err != nil || total != 1 {
+		t.Errorf("productRepository.Count() = %d, %v, want 1", total, err)
+	}
+}
+
+func Test_productRepository_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t)
+	k := &repository.IdempotencyKey{RequestID: "r1", Fingerprint: "f1", Response: []byte("response"), ExpiresAt: time.Now().Add(time.Hour)}
+
+	if err := r.SaveIdempotencyKey(ctx, k); err != nil {
+		t.Fatalf("productRepository.SaveIdempotencyKey() error = %v", err)
+	}
+	if got, err := r.GetIdempotencyKey(ctx, "r1"); err != nil || got.Fingerprint != "f1" {
+		t.Errorf("productRepository.GetIdempotencyKey() = %v, %v, want fingerprint f1", got, err)
+	}
+	var storageErr *repository.StorageError
+	if err := r.SaveIdempotencyKey(ctx, k); !errors.As(err, &storageErr) || storageErr.Kind != repository.KindDuplicate {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v, want KindDuplicate", err)
+	}
+
+	// key is saved only if transaction is committed
+	failed := errors.New("failed")
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		if err := tx.SaveIdempotencyKey(ctx, &repository.IdempotencyKey{RequestID: "r2", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("productRepository.InTx() error = %v, want %v", err, failed)
+	}
+	if _, err := r.GetIdempotencyKey(ctx, "r2"); !reflect.DeepEqual(err, &repository.IdempotencyKeyNotFoundError{RequestID: "r2"}) {
+		t.Errorf("productRepository.GetIdempotencyKey() error = %v, want IdempotencyKeyNotFoundError", err)
+	}
+
+	// expired key is not found and it is replaced
+	expired := &repository.IdempotencyKey{RequestID: "r3", Fingerprint: "f3", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := r.SaveIdempotencyKey(ctx, expired); err != nil {
+		t.Fatalf("productRepository.SaveIdempotencyKey() error = %v", err)
+	}
+	if _, err := r.GetIdempotencyKey(ctx, "r3"); !reflect.DeepEqual(err, &repository.IdempotencyKeyNotFoundError{RequestID: "r3"}) {
+		t.Errorf("productRepository.GetIdempotencyKey() expired error = %v, want IdempotencyKeyNotFoundError", err)
+	}
+	if purged, err := r.PurgeIdempotencyKeys(ctx, time.Now()); err != nil || purged != 1 {
+		t.Errorf("productRepository.PurgeIdempotencyKeys() = %d, %v, want 1", purged, err)
+	}
+	if _, err := r.GetIdempotencyKey(ctx, "r1"); err != nil {
+		t.Errorf("productRepository.GetIdempotencyKey() after purge error = %v", err)
+	}
+}
+
+func Test_productRepository_List(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+	r := newRepository(t)
+	// food (1) -> fruit (2), vegetable (3)
+	for _, c := range []repository.Category{{Name: "food"}, {Name: "fruit", ParentID: 1}, {Name: "vegetable"}} {
+		if _, err := r.CreateCategory(ctx, &c); err != nil {
+			t.Fatalf("productRepository.CreateCategory() error = %v", err)
+		}
+	}
+	for _, p := range []repository.Product{
+		{Name: "banana", Price: money.Amount{Currency: "EUR", Minor: 200}, CategoryID: 2, Date: tm},
+		{Name: "apple", Price: money.Amount{Currency: "EUR", Minor: 100}, CategoryID: 2, Date: tm.Add(time.Hour)},
+		{Name: "carrot", Price: money.Amount{Currency: "EUR", Minor: 100}, CategoryID: 3, Date: tm.Add(2 * time.Hour)},
+		{Name: "cucumber", Price: money.Amount{Currency: "USD", Minor: 150}, CategoryID: 3, Date: tm.Add(3 * time.Hour)},
+	} {
+		if _, err := r.Create(ctx, &p); err != nil {
+			t.Fatalf("productRepository.Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		q       repository.ListQuery
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "All",
+			want: []int64{1, 2, 3, 4},
+		},
+		{
+			name: "Filter",
+			q:    repository.ListQuery{Filter: repository.Filter{CategoryID: 2, NamePrefix: "ap"}},
+			want: []int64{2},
+		},
+		{
+			name: "Subcategories",
+			q:    repository.ListQuery{Filter: repository.Filter{CategoryID: 1}},
+			want: []int64{1, 2},
+		},
+		{
+			name: "Date range",
+			q:    repository.ListQuery{Filter: repository.Filter{DateFrom: tm.Add(time.Hour), DateTo: tm.Add(2 * time.Hour)}},
+			want: []int64{2},
+		},
+		{
+			name: "Price range",
+			q: repository.ListQuery{Filter: repository.Filter{
+				PriceFrom: money.Amount{Currency: "EUR", Minor: 150},
+				PriceTo:   money.Amount{Currency: "EUR", Minor: 250},
+			}},
+			want: []int64{1},
+		},
+		{
+			name: "Price in another currency",
+			q:    repository.ListQuery{Filter: repository.Filter{PriceFrom: money.Amount{Currency: "GBP", Minor: 1}}},
+			want: []int64{},
+		},
+		{
+			name: "Order by name",
+			q:    repository.ListQuery{Order: repository.Order{Field: repository.FieldName}},
+			want: []int64{2, 1, 3, 4},
+		},
+		{
+			name: "Order by price desc",
+			q:    repository.ListQuery{Order: repository.Order{Field: repository.FieldPrice, Desc: true}},
+			want: []int64{4, 1, 3, 2},
+		},
+		{
+			name: "Next page",
+			q: repository.ListQuery{
+				Order: repository.Order{Field: repository.FieldPrice},
+				After: &repository.Product{ID: 2, Price: money.Amount{Currency: "EUR", Minor: 100}},
+				Limit: 1,
+			},
+			want: []int64{3},
+		},
+		{
+			name: "Next page in another currency",
+			q: repository.ListQuery{
+				Order: repository.Order{Field: repository.FieldPrice},
+				After: &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 200}},
+			},
+			want: []int64{4},
+		},
+		{
+			name:    "Unsupported order",
+			q:       repository.ListQuery{Order: repository.Order{Field: repository.FieldUnit}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := r.List(ctx, tt.q)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("productRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			got := []int64{}
+			for _, p := range list {
+				got = append(got, p.ID)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	total, err := r.Count(ctx, repository.Filter{CategoryID: 1})
+	if err != nil || total != 2 {
+		t.Errorf("productRepository.Count() = %d, %v, want 2, nil", total, err)
+	}
+}
+
+func Test_productRepository_InTx(t *testing.T) {
+	ctx := context.Background()
+	failed := errors.New("item failed")
+
+	tests := []struct {
+		name     string
+		readOnly bool
+		fn       func(tx repository.ProductTx) error
+		wantErr  error
+		want     []int64
+	}{
+		{
+			name: "Commit",
+			fn: func(tx repository.ProductTx) error {
+				_, err := tx.Create(ctx, &repository.Product{Name: "name 2"})
+				return err
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name: "Rollback",
+			fn: func(tx repository.ProductTx) error {
+				if _, err := tx.Delete(ctx, 1, 0); err != nil {
+					return err
+				}
+				return failed
+			},
+			wantErr: failed,
+			want:    []int64{1},
+		},
+		{
+			name: "Savepoint",
+			fn: func(tx repository.ProductTx) error {
+				err := tx.Savepoint(ctx, func() error {
+					if _, err := tx.Create(ctx, &repository.Product{Name: "name 2"}); err != nil {
+						return err
+					}
+					_, err := tx.Delete(ctx, 5, 0)
+					return err
+				})
+				if _, ok := err.(*repository.NotFoundError); !ok {
+					return errors.New("NotFoundError is expected")
+				}
+				return tx.Savepoint(ctx, func() error {
+					_, err := tx.Create(ctx, &repository.Product{Name: "name 3"})
+					return err
+				})
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name:     "Read only",
+			readOnly: true,
+			fn: func(tx repository.ProductTx) error {
+				_, err := tx.Create(ctx, &repository.Product{Name: "name 2"})
+				return err
+			},
+			wantErr: errReadOnly,
+			want:    []int64{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRepository(t, repository.Product{Name: "name"})
+			err := r.InTx(ctx, tt.readOnly, tt.fn)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.InTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			list, _ := r.List(ctx, repository.ListQuery{})
+			got := []int64{}
+			for _, p := range list {
+				got = append(got, p.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.InTx() left %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_productRepository_concurrency(t *testing.T) {
+	ctx := context.Background()
+	r := newRepository(t)
+
+	const n = 50
+	var wg sync.WaitGroup
+	ids := make([]int64, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := r.Create(ctx, &repository.Product{Name: "name"})
+			if err != nil {
+				t.Errorf("productRepository.Create() error = %v", err)
+				return
+			}
+			ids[i] = p.ID
+			if _, err := r.List(ctx, repository.ListQuery{}); err != nil {
+				t.Errorf("productRepository.List() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[int64]bool{}
+	for _, id := range ids {
+		if id < 1 || id > n || seen[id] {
+			t.Fatalf("productRepository.Create() assigned IDs %v, want unique IDs from 1 to %d", ids, n)
+		}
+		seen[id] = true
+	}
+}
+
+func Test_productRepository_snapshot(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "products.json")
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	r, err := NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	for _, name := range []string{"name 1", "name 2"} {
+		if _, err := r.Create(ctx, &repository.Product{Name: name, Date: tm}); err != nil {
+			t.Fatalf("productRepository.Create() error = %v", err)
+		}
+	}
+	if _, err := r.Delete(ctx, 2, 0); err != nil {
+		t.Fatalf("productRepository.Delete() error = %v", err)
+	}
+
+	if _, err := r.Purge(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("productRepository.Purge() error = %v", err)
+	}
+
+	// deleted ID must not be reused after restart
+	r, err = NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	got, err := r.Get(ctx, 1)
+	want := &repository.Product{ID: 1, Name: "name 1", Date: tm, Revision: 1}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("productRepository.Get() = %v, %v, want %v", got, err, want)
+	}
+	created, err := r.Create(ctx, &repository.Product{Name: "name 3"})
+	if err != nil || created.ID != 3 {
+		t.Errorf("productRepository.Create() = %v, %v, want ID 3", created, err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("{"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := NewRepository(file); err == nil {
+		t.Errorf("NewRepository() error = nil, want error for malformed snapshot")
+	}
+
+	// category names of legacy snapshot become root Categories, legacy server didn't write journal
+	if err := os.Remove(journalFile(file)); err != nil {
+		t.Fatalf("failed to remove journal: %v", err)
+	}
+	legacy := `{"next_id":4,"products":[{"ID":1,"Name":"potato","Category":"vegetable"},` +
+		`{"ID":2,"Name":"apple","Category":"fruit"},{"ID":3,"Name":"tomato","Category":"vegetable"}]}`
+	if err := ioutil.WriteFile(file, []byte(legacy), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	r, err = NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	categories, err := r.ListCategories(ctx)
+	wantCategories := []*repository.Category{{ID: 1, Name: "vegetable"}, {ID: 2, Name: "fruit"}}
+	if err != nil || !reflect.DeepEqual(categories, wantCategories) {
+		t.Errorf("productRepository.ListCategories() = %v, %v, want %v", categories, err, wantCategories)
+	}
+	if total, err := r.Count(ctx, repository.Filter{CategoryID: 1}); err != nil || total != 2 {
+		t.Errorf("productRepository.Count() = %d, %v, want 2, nil", total, err)
+	}
+}
+
+func Test_productRepository_journal(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "products.json")
+
+	r, err := NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	for _, name := range []string{"name 1", "name 2"} {
+		if _, err := r.Create(ctx, &repository.Product{Name: name}); err != nil {
+			t.Fatalf("productRepository.Create() error = %v", err)
+		}
+	}
+	// failed transaction isn't journaled
+	failed := errors.New("failed")
+	if err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		if _, err := tx.Delete(ctx, 1, 0); err != nil {
+			return err
+		}
+		return failed
+	}); err != failed {
+		t.Fatalf("productRepository.InTx() error = %v, want %v", err, failed)
+	}
+	if _, err := r.Delete(ctx, 2, 0); err != nil {
+		t.Fatalf("productRepository.Delete() error = %v", err)
+	}
+
+	// record cut off by crash is dropped
+	f, err := os.OpenFile(journalFile(file), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("failed to open journal: %v", err)
+	}
+	f.WriteString(`{"next_id":4,"products":[{"ID":3`)
+	f.Close()
+
+	check := func(r repository.Repository) {
+		t.Helper()
+		if p, err := r.Get(ctx, 1); err != nil || p.Name != "name 1" {
+			t.Errorf("productRepository.Get(1) = %v, %v, want name 1", p, err)
+		}
+		if _, err := r.Get(ctx, 2); err == nil {
+			t.Errorf("productRepository.Get(2) error = nil, want deleted Product")
+		}
+		if _, err := r.Get(ctx, 3); err == nil {
+			t.Errorf("productRepository.Get(3) error = nil, want Product of cut off record to be dropped")
+		}
+	}
+	r, err = NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	check(r)
+
+	// compaction moves the journal to snapshot
+	if err := r.(*productRepository).compact(); err != nil {
+		t.Fatalf("productRepository.compact() error = %v", err)
+	}
+	if fi, err := os.Stat(journalFile(file)); err != nil || fi.Size() != 0 {
+		t.Errorf("journal after compaction = %v, %v, want empty file", fi, err)
+	}
+	r, err = NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	check(r)
+	created, err := r.Create(ctx, &repository.Product{Name: "name 3"})
+	if err != nil || created.ID != 3 {
+		t.Errorf("productRepository.Create() = %v, %v, want ID 3", created, err)
+	}
+
+	// journal is replayed on top of snapshot which contains its records already, e.g. after crash in compaction
+	b, err := ioutil.ReadFile(journalFile(file))
+	if err != nil {
+		t.Fatalf("failed to read journal: %v", err)
+	}
+	if err := r.(*productRepository).compact(); err != nil {
+		t.Fatalf("productRepository.compact() error = %v", err)
+	}
+	if err := ioutil.WriteFile(journalFile(file), b, 0600); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+	r, err = NewRepository(file)
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	if p, err := r.Get(ctx, 3); err != nil || p.Name != "name 3" {
+		t.Errorf("productRepository.Get(3) = %v, %v, want name 3", p, err)
+	}
+
+	if err := ioutil.WriteFile(journalFile(file), []byte("{\n"), 0600); err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+	if _, err := NewRepository(file); err == nil {
+		t.Errorf("NewRepository() error = nil, want error for malformed journal")
+	}
+}
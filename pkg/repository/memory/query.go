@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// match checks if Product is selected by the filter,
+// categories are IDs of the filter Category and its subcategories
+func match(f repository.Filter, categories map[int64]bool, p *repository.Product) bool {
+	switch {
+	case !f.ShowDeleted && !p.DeletedAt.IsZero():
+		return false
+	case f.CategoryID != 0 && !categories[p.CategoryID]:
+		return false
+	case len(f.Creator) > 0 && p.Creator != f.Creator:
+		return false
+	case len(f.Unit) > 0 && p.Unit != f.Unit:
+		return false
+	case !f.DateFrom.IsZero() && p.Date.Before(f.DateFrom):
+		return false
+	case !f.DateTo.IsZero() && !p.Date.Before(f.DateTo):
+		return false
+	case len(f.NamePrefix) > 0 && !strings.HasPrefix(p.Name, f.NamePrefix):
+		return false
+	case !f.PriceFrom.IsZero() && (p.Price.Currency != f.PriceFrom.Currency || p.Price.Minor < f.PriceFrom.Minor):
+		return false
+	case !f.PriceTo.IsZero() && (p.Price.Currency != f.PriceTo.Currency || p.Price.Minor >= f.PriceTo.Minor):
+		return false
+	}
+	return true
+}
+
+// compareTimes compares times like strings.Compare does
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// compareInts compares integers like strings.Compare does
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compare compares Products by the sort field, ID breaks ties
+func compare(field repository.Field, a, b *repository.Product) int {
+	var c int
+	switch field {
+	case repository.FieldName:
+		c = strings.Compare(a.Name, b.Name)
+	case repository.FieldPrice:
+		// amounts of different currencies aren't comparable
+		if c = strings.Compare(a.Price.Currency, b.Price.Currency); c == 0 {
+			c = compareInts(a.Price.Minor, b.Price.Minor)
+		}
+	case repository.FieldDate:
+		c = compareTimes(a.Date, b.Date)
+	}
+	if c != 0 {
+		return c
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+// less checks if Product a goes before Product b in the sort order
+func less(o repository.Order, a, b *repository.Product) bool {
+	if o.Desc {
+		return compare(o.Field, a, b) > 0
+	}
+	return compare(o.Field, a, b) < 0
+}
+
+// checkOrder validates that Products can be sorted by the field
+func checkOrder(o repository.Order) error {
+	switch o.Field {
+	case "", repository.FieldID, repository.FieldName, repository.FieldPrice, repository.FieldDate:
+		return nil
+	}
+	return fmt.Errorf("sorting by field '%s' is not supported", o.Field)
+}
+
+// list selects Products by the query from the data
+func list(d *data, q repository.ListQuery) ([]*repository.Product, error) {
+	if err := checkOrder(q.Order); err != nil {
+		return nil, err
+	}
+
+	categories := d.filterCategories(q.Filter)
+	var selected []*repository.Product
+	for id := range d.products {
+		p := d.products[id]
+		if !match(q.Filter, categories, &p) {
+			continue
+		}
+		if q.After != nil && !less(q.Order, q.After, &p) {
+			continue
+		}
+		selected = append(selected, &p)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return less(q.Order, selected[i], selected[j])
+	})
+	if q.Limit > 0 && len(selected) > q.Limit {
+		selected = selected[:q.Limit]
+	}
+	return selected, nil
+}
+
+// fullUpdateFields are fields written by Update if no fields are listed
+var fullUpdateFields = []repository.Field{
+	repository.FieldName,
+	repository.FieldPrice,
+	repository.FieldUnit,
+	repository.FieldCategoryID,
+	repository.FieldCreator,
+	repository.FieldDescription,
+	repository.FieldDate,
+}
+
+// applyUpdate copies the fields from Product src to Product dst
+func applyUpdate(dst, src *repository.Product, fields []repository.Field) error {
+	if len(fields) == 0 {
+		fields = fullUpdateFields
+	}
+
+	for _, field := range fields {
+		switch field {
+		case repository.FieldName:
+			dst.Name = src.Name
+		case repository.FieldPrice:
+			dst.Price = src.Price
+		case repository.FieldUnit:
+			dst.Unit = src.Unit
+		case repository.FieldCategoryID:
+			dst.CategoryID = src.CategoryID
+		case repository.FieldCreator:
+			dst.Creator = src.Creator
+		case repository.FieldDescription:
+			dst.Description = src.Description
+		case repository.FieldDate:
+			dst.Date = src.Date
+		default:
+			return fmt.Errorf("updating field '%s' is not supported", field)
+		}
+	}
+	return nil
+}
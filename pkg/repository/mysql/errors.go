@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// MySQL server error numbers
+const (
+	errDupEntry            = 1062
+	errDupEntryWithKeyName = 1586
+	errLockWaitTimeout     = 1205
+	errLockDeadlock        = 1213
+	errTooManyConnections  = 1040
+	errServerShutdown      = 1053
+	errQueryInterrupted    = 1317
+	errQueryTimeout        = 3024
+)
+
+// errorKind classifies errors of MySQL driver by error number
+func errorKind(err error) repository.ErrorKind {
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return repository.KindUnavailable
+	}
+
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return repository.KindUnknown
+	}
+	switch myErr.Number {
+	case errDupEntry, errDupEntryWithKeyName:
+		return repository.KindDuplicate
+	case errLockWaitTimeout, errLockDeadlock:
+		return repository.KindConflict
+	case errTooManyConnections, errServerShutdown:
+		return repository.KindUnavailable
+	case errQueryInterrupted:
+		return repository.KindCanceled
+	case errQueryTimeout:
+		return repository.KindDeadlineExceeded
+	}
+	return repository.KindUnknown
+}
@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_errorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want repository.ErrorKind
+	}{
+		{
+			name: "Duplicate key",
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"},
+			want: repository.KindDuplicate,
+		},
+		{
+			name: "Deadlock",
+			err:  &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			want: repository.KindConflict,
+		},
+		{
+			name: "Lock wait timeout",
+			err:  &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			want: repository.KindConflict,
+		},
+		{
+			name: "Too many connections",
+			err:  &mysql.MySQLError{Number: 1040, Message: "Too many connections"},
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Invalid connection",
+			err:  mysql.ErrInvalidConn,
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Query interrupted",
+			err:  &mysql.MySQLError{Number: 1317, Message: "Query execution was interrupted"},
+			want: repository.KindCanceled,
+		},
+		{
+			name: "Syntax error",
+			err:  &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"},
+			want: repository.KindUnknown,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("failed"),
+			want: repository.KindUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorKind(tt.err); got != tt.want {
+				t.Errorf("errorKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
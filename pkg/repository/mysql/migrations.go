@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// migrations of MySQL database schema, released migrations must never be changed
+var migrations = []sqldb.Migration{
+	{
+		Version: 1,
+		Name:    "create_product",
+		// table could be created by the server before migrations were introduced, so it must have the same schema
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS `Product` (`ID` bigint(20) NOT NULL AUTO_INCREMENT," +
+				"`Name` varchar(200) DEFAULT NULL," +
+				"`Price` varchar(200) DEFAULT NULL," +
+				"`Creator` varchar(200) DEFAULT NULL," +
+				"`Unit` varchar(200) DEFAULT NULL," +
+				"`Category` varchar(200) DEFAULT NULL," +
+				"`Description` varchar(1024) DEFAULT NULL," +
+				"`Date` timestamp NULL DEFAULT NULL," +
+				"PRIMARY KEY (`ID`)," +
+				"UNIQUE KEY `ID_UNIQUE` (`ID`))",
+		},
+		Down: []string{
+			"DROP TABLE `Product`",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "product_revision",
+		// Revision of existing Products starts at 1
+		Up: []string{
+			"ALTER TABLE `Product` ADD COLUMN `Revision` bigint(20) NOT NULL DEFAULT 1 AFTER `Date`",
+		},
+		Down: []string{
+			"ALTER TABLE `Product` DROP COLUMN `Revision`",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "structured_price",
+		// legacy Price column is kept, but it is not written anymore
+		Up: []string{
+			"ALTER TABLE `Product` ADD COLUMN `PriceMinor` bigint(20) NOT NULL DEFAULT 0 AFTER `Price`," +
+				"ADD COLUMN `PriceCurrency` varchar(3) NOT NULL DEFAULT '' AFTER `PriceMinor`",
+		},
+		UpData: sqldb.ParseLegacyPrices,
+		Down: []string{
+			"ALTER TABLE `Product` DROP COLUMN `PriceMinor`, DROP COLUMN `PriceCurrency`",
+		},
+		DownData: sqldb.FormatLegacyPrices,
+	},
+	{
+		Version: 4,
+		Name:    "normalize_unit",
+		// normalized units are valid free text as well, so there is nothing to revert
+		UpData: sqldb.NormalizeUnits,
+	},
+	{
+		Version: 5,
+		Name:    "category_tree",
+		// legacy Category column is kept, but it is not written anymore
+		Up: []string{
+			"CREATE TABLE `Category` (`ID` bigint(20) NOT NULL AUTO_INCREMENT," +
+				"`Name` varchar(200) NOT NULL," +
+				"`ParentID` bigint(20) DEFAULT NULL," +
+				"PRIMARY KEY (`ID`)," +
+				"CONSTRAINT `fk_category_parent` FOREIGN KEY (`ParentID`) REFERENCES `Category` (`ID`))",
+			"ALTER TABLE `Product` ADD COLUMN `CategoryID` bigint(20) DEFAULT NULL AFTER `Category`," +
+				"ADD CONSTRAINT `fk_product_category` FOREIGN KEY (`CategoryID`) REFERENCES `Category` (`ID`)",
+		},
+		UpData: sqldb.MigrateLegacyCategories,
+		Down: []string{
+			"ALTER TABLE `Product` DROP FOREIGN KEY `fk_product_category`, DROP COLUMN `CategoryID`",
+			"DROP TABLE `Category`",
+		},
+		DownData: sqldb.RestoreLegacyCategories,
+	},
+	{
+		Version: 6,
+		Name:    "soft_delete",
+		// Products deleted before the migration is rolled back are removed permanently
+		Up: []string{
+			"ALTER TABLE `Product` ADD COLUMN `DeletedAt` timestamp NULL DEFAULT NULL," +
+				"ADD INDEX `idx_product_deleted_at` (`DeletedAt`)",
+		},
+		Down: []string{
+			"DELETE FROM `Product` WHERE `DeletedAt` IS NOT NULL",
+			"ALTER TABLE `Product` DROP INDEX `idx_product_deleted_at`, DROP COLUMN `DeletedAt`",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "idempotency_key",
+		Up: []string{
+			"CREATE TABLE `IdempotencyKey` (`RequestID` varchar(128) NOT NULL," +
+				"`Fingerprint` char(64) NOT NULL," +
+				"`Response` blob NOT NULL," +
+				"`ExpiresAt` datetime NOT NULL," +
+				"PRIMARY KEY (`RequestID`)," +
+				"KEY `idx_idempotency_key_expires_at` (`ExpiresAt`))",
+		},
+		Down: []string{
+			"DROP TABLE `IdempotencyKey`",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "product_fulltext",
+		// InnoDB builds one FULLTEXT index per statement without copying the table
+		Up: []string{
+			"ALTER TABLE `Product` ADD FULLTEXT INDEX `ft_product_name` (`Name`)",
+			"ALTER TABLE `Product` ADD FULLTEXT INDEX `ft_product_description` (`Description`)",
+			"ALTER TABLE `Category` ADD FULLTEXT INDEX `ft_category_name` (`Name`)",
+		},
+		Down: []string{
+			"ALTER TABLE `Category` DROP INDEX `ft_category_name`",
+			"ALTER TABLE `Product` DROP INDEX `ft_product_description`",
+			"ALTER TABLE `Product` DROP INDEX `ft_product_name`",
+		},
+	},
+}
+
+// NewMigrator creates migrator of MySQL database schema
+func NewMigrator(db *sql.DB) *sqldb.Migrator {
+	return sqldb.NewMigrator(db, dialect)
+}
@@ -0,0 +1,171 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+// appliedRows returns rows of schema_migrations table with all migrations applied except pending ones
+func appliedRows(pending ...int64) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, mg := range migrations {
+		applied := true
+		for _, v := range pending {
+			if mg.Version == v {
+				applied = false
+			}
+		}
+		if applied {
+			rows.AddRow(mg.Version, time.Now())
+		}
+	}
+	return rows
+}
+
+func TestMigrator_Up(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	m := NewMigrator(db)
+
+	tests := []struct {
+		name     string
+		mock     func()
+		wantDone int
+		wantErr  bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `version`, `applied_at` FROM schema_migrations")).
+					WillReturnRows(appliedRows(1))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS `Product`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations(`version`, `name`, `applied_at`) VALUES(?, ?, ?)")).
+					WithArgs(1, "create_product", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantDone: 1,
+		},
+		{
+			name: "Legacy table",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+				mock.ExpectBegin()
+				// table created by the server before migrations were introduced is kept
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS `Product`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(1, "create_product", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE `Product` ADD COLUMN `Revision`")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(2, "product_revision", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectExec("ALTER TABLE `Product` ADD COLUMN `PriceMinor`").WillReturnError(errors.New("ALTER failed"))
+				mock.ExpectRollback()
+			},
+			wantDone: 2,
+			wantErr:  true,
+		},
+		{
+			name: "Legacy prices",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(appliedRows(3))
+				mock.ExpectBegin()
+				mock.ExpectExec("ALTER TABLE `Product` ADD COLUMN `PriceMinor`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `ID`, `Price` FROM Product WHERE `Price`<>''")).
+					WillReturnRows(sqlmock.NewRows([]string{"ID", "Price"}).AddRow(1, "5,50€").AddRow(2, "cheap"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `PriceMinor`=?, `PriceCurrency`=? WHERE `ID`=?")).
+					WithArgs(550, "EUR", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(3, "structured_price", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantDone: 1,
+		},
+		{
+			name: "Legacy units",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(appliedRows(4))
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `ID`, `Unit` FROM Product WHERE `Unit`<>''")).
+					WillReturnRows(sqlmock.NewRows([]string{"ID", "Unit"}).AddRow(1, "Kg").AddRow(2, "kg").AddRow(3, "bag"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `Unit`=? WHERE `ID`=?")).
+					WithArgs("kg", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(4, "normalize_unit", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantDone: 1,
+		},
+		{
+			name: "Legacy categories",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(appliedRows(5))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE `Category`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ALTER TABLE `Product` ADD COLUMN `CategoryID`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `Category` FROM Product WHERE `Category`<>''")).
+					WillReturnRows(sqlmock.NewRows([]string{"Category"}).AddRow("vegetable"))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO Category(`Name`, `ParentID`) VALUES(?, ?)")).
+					WithArgs("vegetable", nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `CategoryID`=? WHERE `Category`=? AND `CategoryID` IS NULL")).
+					WithArgs(1, "vegetable").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO schema_migrations").
+					WithArgs(5, "category_tree", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantDone: 1,
+		},
+		{
+			name: "Applied",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").WillReturnRows(appliedRows())
+			},
+		},
+		{
+			name: "Migration failed",
+			mock: func() {
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM schema_migrations").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+				mock.ExpectBegin()
+				mock.ExpectExec("CREATE TABLE IF NOT EXISTS `Product`").WillReturnError(errors.New("CREATE failed"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			done, err := m.Up(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Migrator.Up() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(done) != tt.wantDone {
+				t.Errorf("Migrator.Up() applied %v, want %d migrations", done, tt.wantDone)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// dialect is MySQL flavor of SQL.
+// Database must be opened with clientFoundRows=true, so that UPDATE reports matched rows.
+var dialect = &sqldb.Dialect{
+	Migrations: migrations,
+	QuoteChar:  '`',
+	ForUpdate:  " FOR UPDATE",
+	ErrorKind:  errorKind,
+}
+
+// NewProductRepository creates Product repository stored in MySQL database
+func NewProductRepository(db *sql.DB) repository.ProductRepository {
+	return sqldb.NewProductRepository(db, dialect)
+}
+
+// NewCategoryRepository creates Category repository stored in MySQL database
+func NewCategoryRepository(db *sql.DB) repository.CategoryRepository {
+	return sqldb.NewCategoryRepository(db, dialect)
+}
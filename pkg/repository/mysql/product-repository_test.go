@@ -0,0 +1,1067 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// selectColumns are columns of Product table selected by queries
+const selectColumns = "`ID`, `Name`, `PriceMinor`, `PriceCurrency`, `Creator`, `Unit`, `CategoryID`, `Description`, `Date`, `Revision`, `DeletedAt`"
+
+// productColumns are columns selected from Product table
+var productColumns = []string{"ID", "Name", "PriceMinor", "PriceCurrency", "Creator", "Unit", "CategoryID", "Description", "Date", "Revision", "DeletedAt"}
+
+// expectCategories expects Categories to be selected for filter by Category 5, which has subcategory 6
+func expectCategories(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `ID`, `Name`, `ParentID` FROM Category ORDER BY `ID`")).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "ParentID"}).
+			AddRow(5, "vegetable", nil).AddRow(6, "roots", 5).AddRow(7, "fruit", nil))
+}
+
+func Test_productRepository_Create(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name    string
+		p       *repository.Product
+		mock    func()
+		want    *repository.Product
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			p:    &repository.Product{Name: "Name", Description: "Description", Date: tm},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO Product").WithArgs("Name", 0, "", "", "", nil, "Description", tm).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			want: &repository.Product{ID: 1, Name: "Name", Description: "Description", Date: tm, Revision: 1},
+		},
+		{
+			name: "INSERT failed",
+			p:    &repository.Product{Name: "name", Description: "description", Date: tm},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO Product").WithArgs("name", 0, "", "", "", nil, "description", tm).
+					WillReturnError(errors.New("INSERT failed"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "LastInsertId failed",
+			p:    &repository.Product{Name: "name", Description: "description", Date: tm},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO Product").WithArgs("name", 0, "", "", "", nil, "description", tm).
+					WillReturnResult(sqlmock.NewErrorResult(errors.New("LastInsertId failed")))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Create(ctx, tt.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("productRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Create() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Get(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name    string
+		id      int64
+		mock    func()
+		want    *repository.Product
+		wantErr error
+	}{
+		{
+			name: "OK",
+			id:   1,
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(1, "name", 500, "EUR", "Marty", "kg", 5, "description", tm, 2, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM Product WHERE `ID`=? AND `DeletedAt` IS NULL")).
+					WithArgs(1).WillReturnRows(rows)
+			},
+			want: &repository.Product{
+				ID:          1,
+				Name:        "name",
+				Price:       money.Amount{Currency: "EUR", Minor: 500},
+				Creator:     "Marty",
+				Unit:        "kg",
+				CategoryID:  5,
+				Description: "description",
+				Date:        tm,
+				Revision:    2,
+			},
+		},
+		{
+			name: "SELECT failed",
+			id:   1,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnError(errors.New("SELECT failed"))
+			},
+			wantErr: &repository.StorageError{Op: "failed to select from Product", Err: errors.New("SELECT failed")},
+		},
+		{
+			name: "Not found",
+			id:   1,
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns)
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr: &repository.NotFoundError{ID: 1},
+		},
+		{
+			name: "Multiple rows",
+			id:   1,
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(1, "name", 0, "", "", "", nil, "description", tm, 1, nil).
+					AddRow(1, "name", 0, "", "", "", nil, "description", tm, 1, nil)
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).WillReturnRows(rows)
+			},
+			wantErr: errors.New("found multiple Product rows with ID='1'"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Get(ctx, tt.id)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Get() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	type args struct {
+		p                *repository.Product
+		fields           []repository.Field
+		expectedRevision int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func()
+		want    *repository.Product
+		wantErr error
+	}{
+		{
+			name: "OK",
+			args: args{
+				p: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `Name`=?, `PriceMinor`=?, `PriceCurrency`=?, `Unit`=?, `CategoryID`=?, `Creator`=?, `Description`=?, `Date`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL")).
+					WithArgs("new name", 0, "", "", nil, "", "new description", tm, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "new name", 0, "", "", "", nil, "new description", tm, 2, nil))
+				mock.ExpectCommit()
+			},
+			want: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm, Revision: 2},
+		},
+		{
+			name: "Partial update",
+			args: args{
+				p:      &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 600}, Name: "ignored"},
+				fields: []repository.Field{repository.FieldPrice, repository.FieldPrice},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `PriceMinor`=?, `PriceCurrency`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL")).
+					WithArgs(600, "EUR", 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "name", 600, "EUR", "", "", nil, "description", tm, 2, nil))
+				mock.ExpectCommit()
+			},
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 600}, Description: "description", Date: tm, Revision: 2},
+		},
+		{
+			name: "Expected revision",
+			args: args{
+				p:                &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 600}},
+				fields:           []repository.Field{repository.FieldPrice},
+				expectedRevision: 3,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `PriceMinor`=?, `PriceCurrency`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL AND `Revision`=?")).
+					WithArgs(600, "EUR", 1, 3).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "name", 600, "EUR", "", "", nil, "description", tm, 4, nil))
+				mock.ExpectCommit()
+			},
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 600}, Description: "description", Date: tm, Revision: 4},
+		},
+		{
+			name: "Revision mismatch",
+			args: args{
+				p:                &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 600}},
+				fields:           []repository.Field{repository.FieldPrice},
+				expectedRevision: 3,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs(600, "EUR", 1, 3).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(4))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.RevisionMismatchError{ID: 1, Revision: 4, Expected: 3},
+		},
+		{
+			name: "Expected revision of missing product",
+			args: args{
+				p:                &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 600}},
+				fields:           []repository.Field{repository.FieldPrice},
+				expectedRevision: 3,
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs(600, "EUR", 1, 3).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.NotFoundError{ID: 1},
+		},
+		{
+			name: "Unsupported field",
+			args: args{
+				p:      &repository.Product{ID: 1},
+				fields: []repository.Field{repository.FieldID},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("updating field 'id' is not supported"),
+		},
+		{
+			name: "UPDATE failed",
+			args: args{
+				p: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs("new name", 0, "", "", nil, "", "new description", tm, 1).
+					WillReturnError(errors.New("UPDATE failed"))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to update Product", Err: errors.New("UPDATE failed")},
+		},
+		{
+			name: "RowsAffected failed",
+			args: args{
+				p: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs("new name", 0, "", "", nil, "", "new description", tm, 1).
+					WillReturnResult(sqlmock.NewErrorResult(errors.New("RowsAffected failed")))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to retrieve rows affected value", Err: errors.New("RowsAffected failed")},
+		},
+		{
+			name: "Not Found",
+			args: args{
+				p: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs("new name", 0, "", "", nil, "", "new description", tm, 1).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.NotFoundError{ID: 1},
+		},
+		{
+			name: "COMMIT failed",
+			args: args{
+				p: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm},
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs("new name", 0, "", "", nil, "", "new description", tm, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "new name", 0, "", "", "", nil, "new description", tm, 2, nil))
+				mock.ExpectCommit().WillReturnError(errors.New("COMMIT failed"))
+			},
+			wantErr: &repository.StorageError{Op: "failed to commit transaction", Err: errors.New("COMMIT failed")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Update(ctx, tt.args.p, tt.args.fields, tt.args.expectedRevision)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Update() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+
+	type args struct {
+		id               int64
+		expectedRevision int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			args: args{id: 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `DeletedAt`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL")).WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `Revision` FROM Product WHERE `ID`=?")).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(2))
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "Expected revision",
+			args: args{id: 1, expectedRevision: 2},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `DeletedAt`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL AND `Revision`=?")).WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(3))
+				mock.ExpectCommit()
+			},
+			want: 3,
+		},
+		{
+			name: "Revision mismatch",
+			args: args{id: 1, expectedRevision: 2},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(3))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.RevisionMismatchError{ID: 1, Revision: 3, Expected: 2},
+		},
+		{
+			name: "DELETE failed",
+			args: args{id: 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnError(errors.New("DELETE failed"))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to delete Product", Err: errors.New("DELETE failed")},
+		},
+		{
+			name: "RowsAffected failed",
+			args: args{id: 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewErrorResult(errors.New("RowsAffected failed")))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to retrieve rows affected value", Err: errors.New("RowsAffected failed")},
+		},
+		{
+			name: "Not Found",
+			args: args{id: 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.NotFoundError{ID: 1},
+		},
+		{
+			name: "SELECT failed",
+			args: args{id: 1},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(1).
+					WillReturnError(errors.New("SELECT failed"))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to select Product revision", Err: errors.New("SELECT failed")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Delete(ctx, tt.args.id, tt.args.expectedRevision)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("productRepository.Delete() = %d, want %d", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Undelete(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *repository.Product
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Product SET `DeletedAt`=NULL, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NOT NULL")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "name", 0, "", "", "", nil, "", tm, 3, nil))
+				mock.ExpectCommit()
+			},
+			want: &repository.Product{ID: 1, Name: "name", Date: tm, Revision: 3},
+		},
+		{
+			name: "Not deleted",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`=NULL").WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.NotFoundError{ID: 1},
+		},
+		{
+			name: "UPDATE failed",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`=NULL").WithArgs(1).
+					WillReturnError(errors.New("UPDATE failed"))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.StorageError{Op: "failed to undelete Product", Err: errors.New("UPDATE failed")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Undelete(ctx, 1)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Undelete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Undelete() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Purge(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM Product WHERE `DeletedAt` IS NOT NULL AND `DeletedAt`<?")).WithArgs(tm).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	got, err := r.Purge(ctx, tm)
+	if err != nil || got != 2 {
+		t.Errorf("productRepository.Purge() = %d, %v, want 2, nil", got, err)
+	}
+
+	mock.ExpectExec("DELETE FROM Product").WithArgs(tm).WillReturnError(errors.New("DELETE failed"))
+	wantErr := &repository.StorageError{Op: "failed to purge Product", Err: errors.New("DELETE failed")}
+	if _, err := r.Purge(ctx, tm); !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("productRepository.Purge() error = %v, wantErr %v", err, wantErr)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_productRepository_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC).Add(time.Hour)
+	k := &repository.IdempotencyKey{RequestID: "r1", Fingerprint: "f1", Response: []byte("response"), ExpiresAt: tm}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM IdempotencyKey WHERE `RequestID`=? AND `ExpiresAt`<=?")).
+		WithArgs("r1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO IdempotencyKey(`RequestID`, `Fingerprint`, `Response`, `ExpiresAt`) VALUES(?, ?, ?, ?)")).
+		WithArgs("r1", "f1", []byte("response"), tm).WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := r.SaveIdempotencyKey(ctx, k); err != nil {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `Fingerprint`, `Response`, `ExpiresAt` FROM IdempotencyKey WHERE `RequestID`=? AND `ExpiresAt`>?")).
+		WithArgs("r1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Fingerprint", "Response", "ExpiresAt"}).AddRow("f1", []byte("response"), tm))
+	got, err := r.GetIdempotencyKey(ctx, "r1")
+	if err != nil || !reflect.DeepEqual(got, k) {
+		t.Errorf("productRepository.GetIdempotencyKey() = %v, %v, want %v, nil", got, err, k)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM IdempotencyKey").WithArgs("r2", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"Fingerprint", "Response", "ExpiresAt"}))
+	wantErr := &repository.IdempotencyKeyNotFoundError{RequestID: "r2"}
+	if _, err := r.GetIdempotencyKey(ctx, "r2"); !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("productRepository.GetIdempotencyKey() error = %v, wantErr %v", err, wantErr)
+	}
+
+	mock.ExpectExec("DELETE FROM IdempotencyKey").WithArgs("r1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO IdempotencyKey").WithArgs("r1", "f1", []byte("response"), tm).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'r1' for key 'PRIMARY'"})
+	var storageErr *repository.StorageError
+	if err := r.SaveIdempotencyKey(ctx, k); !errors.As(err, &storageErr) || storageErr.Kind != repository.KindDuplicate {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v, want KindDuplicate", err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM IdempotencyKey WHERE `ExpiresAt`<?")).WithArgs(tm).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	if n, err := r.PurgeIdempotencyKeys(ctx, tm); err != nil || n != 3 {
+		t.Errorf("productRepository.PurgeIdempotencyKeys() = %d, %v, want 3, nil", n, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_productRepository_List(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm1 := time.Now().In(time.UTC)
+	tm2 := time.Now().In(time.UTC)
+	filter := repository.Filter{
+		CategoryID: 5,
+		Creator:    "Marty",
+		DateFrom:   tm1,
+		NamePrefix: "50%_",
+	}
+	where := " WHERE `DeletedAt` IS NULL AND `CategoryID` IN (?, ?) AND `Creator`=? AND `Date`>=? AND `Name` LIKE ?"
+
+	tests := []struct {
+		name    string
+		q       repository.ListQuery
+		mock    func()
+		want    []*repository.Product
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			q:    repository.ListQuery{Limit: 3},
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(1, "name 1", 0, "", "", "", nil, "description 1", tm1, 1, nil).
+					AddRow(2, "name 2", 0, "", "", "", nil, "description 2", tm2, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM Product WHERE `DeletedAt` IS NULL ORDER BY `ID` ASC LIMIT ?")).
+					WithArgs(3).WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 1, Name: "name 1", Description: "description 1", Date: tm1, Revision: 1},
+				{ID: 2, Name: "name 2", Description: "description 2", Date: tm2, Revision: 1},
+			},
+		},
+		{
+			name: "Empty",
+			q:    repository.ListQuery{},
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM Product WHERE `DeletedAt` IS NULL ORDER BY `ID` ASC")).
+					WillReturnRows(sqlmock.NewRows(productColumns))
+			},
+			want: []*repository.Product{},
+		},
+		{
+			name: "Next page by ID",
+			q: repository.ListQuery{
+				After: &repository.Product{ID: 1},
+				Limit: 2,
+			},
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(2, "name 2", 0, "", "", "", nil, "description 2", tm2, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM Product WHERE `DeletedAt` IS NULL AND `ID`>? ORDER BY `ID` ASC LIMIT ?")).
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 2, Name: "name 2", Description: "description 2", Date: tm2, Revision: 1},
+			},
+		},
+		{
+			name: "Filter and order",
+			q: repository.ListQuery{
+				Filter: filter,
+				Order:  repository.Order{Field: repository.FieldDate, Desc: true},
+				Limit:  2,
+			},
+			mock: func() {
+				expectCategories(mock)
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(2, "50%_ name 2", 0, "", "Marty", "", 5, "description 2", tm2, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM Product"+
+					where+" ORDER BY `Date` DESC, `ID` DESC LIMIT ?")).
+					WithArgs(5, 6, "Marty", tm1, `50\%\_%`, 2).WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 2, Name: "50%_ name 2", Creator: "Marty", CategoryID: 5, Description: "description 2", Date: tm2, Revision: 1},
+			},
+		},
+		{
+			name: "Filter and order next page",
+			q: repository.ListQuery{
+				Filter: filter,
+				Order:  repository.Order{Field: repository.FieldDate, Desc: true},
+				After:  &repository.Product{ID: 2, Date: tm2},
+				Limit:  2,
+			},
+			mock: func() {
+				expectCategories(mock)
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(1, "50%_ name 1", 0, "", "Marty", "", 5, "description 1", tm1, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM Product"+
+					where+" AND (`Date`<? OR (`Date`=? AND `ID`<?)) ORDER BY `Date` DESC, `ID` DESC LIMIT ?")).
+					WithArgs(5, 6, "Marty", tm1, `50\%\_%`, tm2, tm2, 2, 2).WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 1, Name: "50%_ name 1", Creator: "Marty", CategoryID: 5, Description: "description 1", Date: tm1, Revision: 1},
+			},
+		},
+		{
+			name: "Price range ordered by price",
+			q: repository.ListQuery{
+				Filter: repository.Filter{
+					PriceFrom: money.Amount{Currency: "EUR", Minor: 100},
+					PriceTo:   money.Amount{Currency: "EUR", Minor: 1000},
+				},
+				Order: repository.Order{Field: repository.FieldPrice},
+				After: &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 200}},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(2, "name 2", 500, "EUR", "", "", nil, "", tm2, 1, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT "+selectColumns+" FROM Product"+
+					" WHERE `DeletedAt` IS NULL AND `PriceCurrency`=? AND `PriceMinor`>=? AND `PriceCurrency`=? AND `PriceMinor`<?"+
+					" AND (`PriceCurrency`>? OR (`PriceCurrency`=? AND (`PriceMinor`>? OR (`PriceMinor`=? AND `ID`>?))))"+
+					" ORDER BY `PriceCurrency` ASC, `PriceMinor` ASC, `ID` ASC")).
+					WithArgs("EUR", 100, "EUR", 1000, "EUR", "EUR", 200, 200, 1).WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 2, Name: "name 2", Price: money.Amount{Currency: "EUR", Minor: 500}, Date: tm2, Revision: 1},
+			},
+		},
+		{
+			name: "Show deleted",
+			q:    repository.ListQuery{Filter: repository.Filter{ShowDeleted: true}},
+			mock: func() {
+				rows := sqlmock.NewRows(productColumns).
+					AddRow(1, "name 1", 0, "", "", "", nil, "description 1", tm1, 2, tm2)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT " + selectColumns + " FROM Product ORDER BY `ID` ASC")).
+					WillReturnRows(rows)
+			},
+			want: []*repository.Product{
+				{ID: 1, Name: "name 1", Description: "description 1", Date: tm1, Revision: 2, DeletedAt: tm2},
+			},
+		},
+		{
+			name: "Unsupported order field",
+			q: repository.ListQuery{
+				Order: repository.Order{Field: repository.FieldDescription},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+		{
+			name: "SELECT failed",
+			q:    repository.ListQuery{},
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM Product").WillReturnError(errors.New("SELECT failed"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Scan failed",
+			q:    repository.ListQuery{},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"ID"}).AddRow(1)
+				mock.ExpectQuery("SELECT (.+) FROM Product").WillReturnRows(rows)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.List(ctx, tt.q)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("productRepository.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.List() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Stream(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	expectCategories(mock)
+	rows := sqlmock.NewRows(productColumns).
+		AddRow(1, "name 1", 0, "", "", "", 5, "description 1", tm, 1, nil).
+		AddRow(2, "name 2", 0, "", "", "", 6, "description 2", tm, 1, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM Product WHERE `DeletedAt` IS NULL AND `CategoryID` IN (?, ?) ORDER BY `Name` ASC, `ID` ASC")).
+		WithArgs(5, 6).WillReturnRows(rows)
+
+	// stream stops as soon as fn fails
+	var got []int64
+	err = r.Stream(ctx, repository.ListQuery{
+		Filter: repository.Filter{CategoryID: 5},
+		Order:  repository.Order{Field: repository.FieldName},
+	}, func(p *repository.Product) error {
+		got = append(got, p.ID)
+		return errors.New("Send failed")
+	})
+	if err == nil || err.Error() != "Send failed" {
+		t.Errorf("productRepository.Stream() error = %v, want Send failed", err)
+	}
+	if !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("productRepository.Stream() passed %v, want [1]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_productRepository_Count(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Product WHERE `DeletedAt` IS NULL AND `Unit`=?")).WithArgs("kg").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	got, err := r.Count(ctx, repository.Filter{Unit: "kg"})
+	if err != nil || got != 3 {
+		t.Errorf("productRepository.Count() = %d, %v, want 3, nil", got, err)
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM Product").WillReturnError(errors.New("SELECT failed"))
+	if _, err := r.Count(ctx, repository.Filter{}); err == nil {
+		t.Errorf("productRepository.Count() error = nil, want error")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_productRepository_InTx(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+	failed := errors.New("item failed")
+
+	tests := []struct {
+		name     string
+		readOnly bool
+		fn       func(tx repository.ProductTx) error
+		mock     func()
+		wantErr  error
+	}{
+		{
+			name: "Commit",
+			fn: func(tx repository.ProductTx) error {
+				_, err := tx.Create(ctx, &repository.Product{Name: "name", Date: tm})
+				return err
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO Product").WithArgs("name", 0, "", "", "", nil, "", tm).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Rollback",
+			fn: func(tx repository.ProductTx) error {
+				return failed
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			wantErr: failed,
+		},
+		{
+			name: "Savepoint",
+			fn: func(tx repository.ProductTx) error {
+				err := tx.Savepoint(ctx, func() error {
+					_, err := tx.Delete(ctx, 1, 0)
+					return err
+				})
+				if _, ok := err.(*repository.NotFoundError); !ok {
+					return errors.New("NotFoundError is expected")
+				}
+				return tx.Savepoint(ctx, func() error {
+					_, err := tx.Delete(ctx, 2, 0)
+					return err
+				})
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("ROLLBACK TO SAVEPOINT sp")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("SAVEPOINT sp").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE Product SET `DeletedAt`").WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT `Revision` FROM Product").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(2))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "Read only",
+			readOnly: true,
+			fn: func(tx repository.ProductTx) error {
+				_, err := tx.Get(ctx, 1)
+				return err
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "name", 0, "", "", "", nil, "", tm, 1, nil))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "BEGIN failed",
+			fn: func(tx repository.ProductTx) error {
+				return nil
+			},
+			mock: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("BEGIN failed"))
+			},
+			wantErr: &repository.StorageError{Op: "failed to begin transaction", Err: errors.New("BEGIN failed")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err := r.InTx(ctx, tt.readOnly, tt.fn)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.InTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_categoryRepository_MoveCategory(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewCategoryRepository(db)
+
+	tests := []struct {
+		name     string
+		id       int64
+		parentID int64
+		mock     func()
+		wantErr  bool
+	}{
+		{
+			name:     "OK",
+			id:       7,
+			parentID: 6,
+			mock: func() {
+				mock.ExpectBegin()
+				// Categories stay locked until commit, so that concurrent move can't create a cycle
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `ID`, `Name`, `ParentID` FROM Category ORDER BY `ID` FOR UPDATE")).
+					WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "ParentID"}).
+						AddRow(5, "vegetable", nil).AddRow(6, "roots", 5).AddRow(7, "fruit", nil))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE Category SET `ParentID`=? WHERE `ID`=?")).WithArgs(6, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT (.+) FROM Category WHERE").WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "ParentID"}).AddRow(7, "fruit", 6))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "Cycle",
+			id:       5,
+			parentID: 6,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT `ID`, `Name`, `ParentID` FROM Category ORDER BY `ID` FOR UPDATE")).
+					WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "ParentID"}).
+						AddRow(5, "vegetable", nil).AddRow(6, "roots", 5).AddRow(7, "fruit", nil))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.MoveCategory(ctx, tt.id, tt.parentID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("categoryRepository.MoveCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ParentID != tt.parentID {
+				t.Errorf("categoryRepository.MoveCategory() = %v, want parent %d", got, tt.parentID)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
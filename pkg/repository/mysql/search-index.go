@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+// scoreSQL is relevance of Product in natural language mode, term in name is more relevant
+// than term in category or description. It takes the search text three times.
+const scoreSQL = "3*MATCH(p.`Name`) AGAINST(?) + MATCH(p.`Description`) AGAINST(?) + 2*COALESCE(MATCH(c.`Name`) AGAINST(?), 0)"
+
+// matchSQL is condition of Product matching the search text. Every column is matched on its own,
+// so that its FULLTEXT index is used, the score is computed for matching Products only.
+// It takes the search text three times.
+const matchSQL = "(MATCH(p.`Name`) AGAINST(?) OR MATCH(p.`Description`) AGAINST(?) OR MATCH(c.`Name`) AGAINST(?))"
+
+// searchFromSQL selects Products which aren't deleted with their Category
+const searchFromSQL = " FROM Product p LEFT JOIN Category c ON c.`ID`=p.`CategoryID` WHERE p.`DeletedAt` IS NULL AND "
+
+// searchIndex is search.Index on top of FULLTEXT indexes of Product and Category tables,
+// the database keeps them in sync with the tables
+type searchIndex struct {
+	db *sql.DB
+}
+
+// NewSearchIndex creates index of Products stored in MySQL database,
+// database schema must be migrated by Migrator
+func NewSearchIndex(db *sql.DB) search.Index {
+	return &searchIndex{db: db}
+}
+
+// Search selects Products matching any of the terms by relevance
+func (x *searchIndex) Search(ctx context.Context, q search.Query) (*search.Result, error) {
+	text := strings.Join(q.Terms, " ")
+
+	var total int64
+	if err := x.db.QueryRowContext(ctx, "SELECT COUNT(*)"+searchFromSQL+matchSQL,
+		text, text, text).Scan(&total); err != nil {
+		return nil, dialect.StorageError("failed to count matching Product", err)
+	}
+
+	rows, err := x.db.QueryContext(ctx, "SELECT p.`ID`, "+scoreSQL+" AS `Score`"+searchFromSQL+matchSQL+
+		" ORDER BY `Score` DESC, p.`ID` LIMIT ? OFFSET ?",
+		text, text, text, text, text, text, q.Limit, q.Offset)
+	if err != nil {
+		return nil, dialect.StorageError("failed to search Product", err)
+	}
+	defer rows.Close()
+
+	res := &search.Result{Total: total}
+	for rows.Next() {
+		var h search.Hit
+		if err := rows.Scan(&h.ID, &h.Score); err != nil {
+			return nil, dialect.StorageError("failed to retrieve matching Product", err)
+		}
+		res.Hits = append(res.Hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dialect.StorageError("failed to retrieve matching Product", err)
+	}
+	return res, nil
+}
+
+// Index does nothing, FULLTEXT index is updated by the database
+func (x *searchIndex) Index(ctx context.Context, p *repository.Product) error {
+	return nil
+}
+
+// Remove does nothing, FULLTEXT index is updated by the database
+func (x *searchIndex) Remove(ctx context.Context, id int64, revision int64) error {
+	return nil
+}
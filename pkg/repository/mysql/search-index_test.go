@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+func Test_searchIndex_Search(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	x := NewSearchIndex(db)
+	q := search.Query{Terms: []string{"organic", "potato"}, Offset: 10, Limit: 2}
+	text := "organic potato"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*)"+searchFromSQL+matchSQL)).
+		WithArgs(text, text, text).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(12))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.`ID`, "+scoreSQL+" AS `Score`"+searchFromSQL+matchSQL+" ORDER BY `Score` DESC, p.`ID` LIMIT ? OFFSET ?")).
+		WithArgs(text, text, text, text, text, text, 2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Score"}).AddRow(3, 2.5).AddRow(1, 0.5))
+	got, err := x.Search(ctx, q)
+	want := &search.Result{Hits: []search.Hit{{ID: 3, Score: 2.5}, {ID: 1, Score: 0.5}}, Total: 12}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("searchIndex.Search() = %v, %v, want %v", got, err, want)
+	}
+
+	mock.ExpectQuery("SELECT COUNT").WithArgs(text, text, text).WillReturnError(errors.New("SELECT failed"))
+	wantErr := &repository.StorageError{Op: "failed to count matching Product", Err: errors.New("SELECT failed")}
+	if _, err := x.Search(ctx, q); !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("searchIndex.Search() error = %v, wantErr %v", err, wantErr)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// errorKind classifies errors of PostgreSQL driver by SQLSTATE code
+func errorKind(err error) repository.ErrorKind {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return repository.KindUnknown
+	}
+	switch code := string(pqErr.Code); {
+	// unique_violation
+	case code == "23505":
+		return repository.KindDuplicate
+	// serialization_failure, deadlock_detected, lock_not_available
+	case code == "40001", code == "40P01", code == "55P03":
+		return repository.KindConflict
+	// connection exceptions, too_many_connections, admin_shutdown, cannot_connect_now
+	case strings.HasPrefix(code, "08"), code == "53300", code == "57P01", code == "57P03":
+		return repository.KindUnavailable
+	// query_canceled, it is also reported when statement_timeout expires
+	case code == "57014":
+		return repository.KindCanceled
+	}
+	return repository.KindUnknown
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_errorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want repository.ErrorKind
+	}{
+		{
+			name: "Unique violation",
+			err:  &pq.Error{Code: "23505"},
+			want: repository.KindDuplicate,
+		},
+		{
+			name: "Deadlock",
+			err:  &pq.Error{Code: "40P01"},
+			want: repository.KindConflict,
+		},
+		{
+			name: "Lock not available",
+			err:  &pq.Error{Code: "55P03"},
+			want: repository.KindConflict,
+		},
+		{
+			name: "Connection failure",
+			err:  &pq.Error{Code: "08006"},
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Admin shutdown",
+			err:  &pq.Error{Code: "57P01"},
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Query canceled",
+			err:  &pq.Error{Code: "57014"},
+			want: repository.KindCanceled,
+		},
+		{
+			name: "Foreign key violation",
+			err:  &pq.Error{Code: "23503"},
+			want: repository.KindUnknown,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("failed"),
+			want: repository.KindUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorKind(tt.err); got != tt.want {
+				t.Errorf("errorKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// dialect is PostgreSQL flavor of SQL.
+// Table name is not quoted, so it is folded to lower case, column names keep their case.
+var dialect = &sqldb.Dialect{
+	Migrations:           migrations,
+	QuoteChar:            '"',
+	NumberedPlaceholders: true,
+	ForUpdate:            " FOR UPDATE",
+	ReturningID:          true,
+	ErrorKind:            errorKind,
+}
+
+// NewProductRepository creates Product repository stored in PostgreSQL database
+func NewProductRepository(db *sql.DB) repository.ProductRepository {
+	return sqldb.NewProductRepository(db, dialect)
+}
+
+// NewCategoryRepository creates Category repository stored in PostgreSQL database
+func NewCategoryRepository(db *sql.DB) repository.CategoryRepository {
+	return sqldb.NewCategoryRepository(db, dialect)
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// selectColumns are columns of Product table selected by queries
+const selectColumns = `"ID", "Name", "PriceMinor", "PriceCurrency", "Creator", "Unit", "CategoryID", "Description", "Date", "Revision", "DeletedAt"`
+
+// productColumns are columns selected from Product table
+var productColumns = []string{"ID", "Name", "PriceMinor", "PriceCurrency", "Creator", "Unit", "CategoryID", "Description", "Date", "Revision", "DeletedAt"}
+
+func Test_productRepository_Create(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *repository.Product
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO Product("Name", "PriceMinor", "PriceCurrency", "Creator", "Unit", "CategoryID", "Description", "Date") VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING "ID"`)).
+					WithArgs("name", 0, "", "", "", nil, "description", tm).
+					WillReturnRows(sqlmock.NewRows([]string{"ID"}).AddRow(7))
+			},
+			want: &repository.Product{ID: 7, Name: "name", Description: "description", Date: tm, Revision: 1},
+		},
+		{
+			name: "INSERT failed",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO Product").WithArgs("name", 0, "", "", "", nil, "description", tm).
+					WillReturnError(errors.New("INSERT failed"))
+			},
+			wantErr: &repository.StorageError{Op: "failed to insert into Product", Err: errors.New("INSERT failed")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Create(ctx, &repository.Product{Name: "name", Description: "description", Date: tm})
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Create() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_Update(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	tests := []struct {
+		name             string
+		expectedRevision int64
+		mock             func()
+		want             *repository.Product
+		wantErr          error
+	}{
+		{
+			name:             "OK",
+			expectedRevision: 1,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE Product SET "PriceMinor"=$1, "PriceCurrency"=$2, "Revision"="Revision"+1 WHERE "ID"=$3 AND "DeletedAt" IS NULL AND "Revision"=$4`)).
+					WithArgs(600, "EUR", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT ` + selectColumns + ` FROM Product WHERE "ID"=$1 AND "DeletedAt" IS NULL`)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows(productColumns).
+						AddRow(1, "name", 600, "EUR", "", "", nil, "", tm, 2, nil))
+				mock.ExpectCommit()
+			},
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 600}, Date: tm, Revision: 2},
+		},
+		{
+			name:             "Revision mismatch",
+			expectedRevision: 1,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE Product").WithArgs(600, "EUR", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "Revision" FROM Product WHERE "ID"=$1 AND "DeletedAt" IS NULL`)).WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"Revision"}).AddRow(2))
+				mock.ExpectRollback()
+			},
+			wantErr: &repository.RevisionMismatchError{ID: 1, Revision: 2, Expected: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			got, err := r.Update(ctx, &repository.Product{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 600}}, []repository.Field{repository.FieldPrice}, tt.expectedRevision)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("productRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productRepository.Update() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_productRepository_List(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+	tm := time.Now().In(time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "ID", "Name", "ParentID" FROM Category ORDER BY "ID"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Name", "ParentID"}).AddRow(5, "vegetable", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT `+selectColumns+` FROM Product WHERE "DeletedAt" IS NULL AND "CategoryID" IN ($1) AND "Name" LIKE $2 AND `+
+		`("Name">$3 OR ("Name"=$4 AND "ID">$5)) ORDER BY "Name" ASC, "ID" ASC LIMIT $6`)).
+		WithArgs(5, "po%", "potato", "potato", 1, 2).
+		WillReturnRows(sqlmock.NewRows(productColumns).
+			AddRow(2, "potato", 0, "", "", "", 5, "", tm, 1, nil))
+
+	got, err := r.List(ctx, repository.ListQuery{
+		Filter: repository.Filter{CategoryID: 5, NamePrefix: "po"},
+		Order:  repository.Order{Field: repository.FieldName},
+		After:  &repository.Product{ID: 1, Name: "potato"},
+		Limit:  2,
+	})
+	want := []*repository.Product{{ID: 2, Name: "potato", CategoryID: 5, Date: tm, Revision: 1}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("productRepository.List() = %v, %v, want %v", got, err, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_productRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := NewProductRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE Product SET "DeletedAt"=$1, "Revision"="Revision"+1 WHERE "ID"=$2 AND "DeletedAt" IS NULL`)).WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	_, err = r.Delete(ctx, 1, 0)
+	if !reflect.DeepEqual(err, &repository.NotFoundError{ID: 1}) {
+		t.Errorf("productRepository.Delete() error = %v, want NotFoundError", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+)
+
+// Product is Product entity as it is kept in storage
+type Product struct {
+	ID      int64
+	Name    string
+	Price   money.Amount
+	Creator string
+	Unit    string
+	// CategoryID is ID of Category of the Product, 0 if Product has no Category
+	CategoryID  int64
+	Description string
+	Date        time.Time
+	// Revision is incremented with every update of the Product
+	Revision int64
+	// DeletedAt is time the Product was soft deleted at, zero if it isn't deleted
+	DeletedAt time.Time
+}
+
+// Field is name of the Product field used for sorting and partial updates
+type Field string
+
+// Product fields
+const (
+	FieldID          Field = "id"
+	FieldName        Field = "name"
+	FieldPrice       Field = "price"
+	FieldUnit        Field = "unit"
+	FieldCategoryID  Field = "category_id"
+	FieldCreator     Field = "creator"
+	FieldDescription Field = "description"
+	FieldDate        Field = "date"
+)
+
+// Filter selects Products, empty fields match all Products
+type Filter struct {
+	// CategoryID selects Products of the Category and all its subcategories
+	CategoryID int64
+	Creator    string
+	Unit       string
+	// DateFrom is inclusive lower bound of Date
+	DateFrom time.Time
+	// DateTo is exclusive upper bound of Date
+	DateTo     time.Time
+	NamePrefix string
+	// PriceFrom is inclusive lower bound of Price in its currency
+	PriceFrom money.Amount
+	// PriceTo is exclusive upper bound of Price in its currency
+	PriceTo money.Amount
+	// ShowDeleted selects soft deleted Products too
+	ShowDeleted bool
+}
+
+// Order is sort order of Products, ID breaks ties of the sort field.
+// Products are sorted by price currency first and then by amount in minor units,
+// because amounts of different currencies aren't comparable.
+type Order struct {
+	Field Field
+	Desc  bool
+}
+
+// ListQuery selects page of Products
+type ListQuery struct {
+	Filter Filter
+	Order  Order
+	// After is the last Product of the previous page, only its ID and sort field are used
+	After *Product
+	// Limit is maximum number of Products returned, 0 means no limit
+	Limit int
+}
+
+// NotFoundError is returned if Product with the ID doesn't exist
+type NotFoundError struct {
+	ID int64
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("Product with ID='%d' is not found", e.ID)
+}
+
+// RevisionMismatchError is returned if Product has another revision than expected by conditional write
+type RevisionMismatchError struct {
+	ID       int64
+	Revision int64
+	Expected int64
+}
+
+func (e *RevisionMismatchError) Error() string {
+	return fmt.Sprintf("Product with ID='%d' has revision '%d', but '%d' is expected", e.ID, e.Revision, e.Expected)
+}
+
+// IdempotencyKey is response of write request saved under request ID supplied by client,
+// so that retries of the request get the same response instead of writing again
+type IdempotencyKey struct {
+	RequestID string
+	// Fingerprint identifies method and payload of the request, it tells retry from another request
+	// which reuses the request ID
+	Fingerprint string
+	// Response is serialized response of the request
+	Response []byte
+	// ExpiresAt is time the key expires at, the request ID can be reused afterwards
+	ExpiresAt time.Time
+}
+
+// IdempotencyKeyNotFoundError is returned if request ID is not saved or it is expired
+type IdempotencyKeyNotFoundError struct {
+	RequestID string
+}
+
+func (e *IdempotencyKeyNotFoundError) Error() string {
+	return fmt.Sprintf("idempotency key with request ID='%s' is not found", e.RequestID)
+}
+
+// ErrorKind classifies failure of the database for clients
+type ErrorKind int
+
+// Kinds of database failures
+const (
+	// KindUnknown is failure which is not classified
+	KindUnknown ErrorKind = iota
+	// KindDuplicate is violation of unique key
+	KindDuplicate
+	// KindConflict is deadlock or lock wait timeout, the transaction can be retried
+	KindConflict
+	// KindUnavailable is lost or refused connection to the database, the request can be retried later
+	KindUnavailable
+	// KindCanceled is request canceled by client
+	KindCanceled
+	// KindDeadlineExceeded is request which exceeded its deadline
+	KindDeadlineExceeded
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindDuplicate:
+		return "DUPLICATE"
+	case KindConflict:
+		return "CONFLICT"
+	case KindUnavailable:
+		return "UNAVAILABLE"
+	case KindCanceled:
+		return "CANCELED"
+	case KindDeadlineExceeded:
+		return "DEADLINE_EXCEEDED"
+	}
+	return "UNKNOWN"
+}
+
+// StorageError is failure of the database operation, Err is the raw error of database driver
+type StorageError struct {
+	Kind ErrorKind
+	// Op describes failed operation, e.g. "failed to insert into Product"
+	Op  string
+	Err error
+}
+
+func (e *StorageError) Error() string {
+	return e.Op + "-> " + e.Err.Error()
+}
+
+// Unwrap returns the raw error of database driver
+func (e *StorageError) Unwrap() error {
+	return e.Err
+}
+
+// ProductStore provides access to Products
+type ProductStore interface {
+	// Create saves new Product and returns it with assigned ID and revision
+	Create(ctx context.Context, p *Product) (*Product, error)
+	// Get returns Product by ID, soft deleted Products are not found
+	Get(ctx context.Context, id int64) (*Product, error)
+	// Update writes the fields of Product and returns its new state.
+	// All fields except ID are written if fields is empty.
+	// Product is written only if it has the expected revision, unless it is 0.
+	Update(ctx context.Context, p *Product, fields []Field, expectedRevision int64) (*Product, error)
+	// Delete soft deletes Product, only if it has the expected revision unless it is 0,
+	// and returns its new revision. Deleted Product is hidden, but it is kept until it is purged.
+	Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error)
+	// Undelete restores soft deleted Product and returns its new state
+	Undelete(ctx context.Context, id int64) (*Product, error)
+	// List returns Products selected by the query
+	List(ctx context.Context, q ListQuery) ([]*Product, error)
+	// Stream calls fn for every Product selected by the query without loading them all into memory
+	Stream(ctx context.Context, q ListQuery, fn func(p *Product) error) error
+	// Count returns number of Products matching the filter
+	Count(ctx context.Context, f Filter) (int64, error)
+	// GetIdempotencyKey returns idempotency key by request ID unless it is expired
+	GetIdempotencyKey(ctx context.Context, requestID string) (*IdempotencyKey, error)
+	// SaveIdempotencyKey saves idempotency key, it replaces expired key with the same request ID.
+	// It fails with KindDuplicate StorageError if the request ID is saved already.
+	SaveIdempotencyKey(ctx context.Context, k *IdempotencyKey) error
+}
+
+// ProductRepository is storage of Products
+type ProductRepository interface {
+	ProductStore
+	// InTx runs fn in transaction, which is committed if fn succeeds and rolled back otherwise
+	InTx(ctx context.Context, readOnly bool, fn func(tx ProductTx) error) error
+	// Purge permanently removes Products soft deleted before the time and returns their number
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// PurgeIdempotencyKeys removes idempotency keys expired before the time and returns their number
+	PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
+
+// ProductTx is ProductStore bound to transaction
+type ProductTx interface {
+	ProductStore
+	// Savepoint runs fn, if it fails only changes made by fn are rolled back
+	// and the transaction can be continued
+	Savepoint(ctx context.Context, fn func() error) error
+}
@@ -0,0 +1,58 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// MigrateLegacyCategories creates root Category for every category name written as free text in `Category` column
+// and references it from `CategoryID` column of Products. The text stays in `Category` column.
+func MigrateLegacyCategories(ctx context.Context, tx *sql.Tx, d *Dialect) error {
+	s := &store{db: tx, dialect: d}
+
+	rows, err := s.query(ctx, "SELECT DISTINCT `Category` FROM Product WHERE `Category`<>''")
+	if err != nil {
+		return errors.New("failed to select from Product-> " + err.Error())
+	}
+	// rows are read first, as some drivers can't execute statements while rows are open
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return errors.New("failed to retrieve field values from Product row-> " + err.Error())
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return errors.New("failed to retrieve data from Product-> " + err.Error())
+	}
+	rows.Close()
+
+	for _, name := range names {
+		c, err := s.CreateCategory(ctx, &repository.Category{Name: name})
+		if err != nil {
+			return err
+		}
+		// names differing in case only are one category for MySQL, it is referenced once
+		if _, err := s.exec(ctx, "UPDATE Product SET `CategoryID`=? WHERE `Category`=? AND `CategoryID` IS NULL",
+			c.ID, name); err != nil {
+			return errors.New("failed to update Product-> " + err.Error())
+		}
+	}
+	return nil
+}
+
+// RestoreLegacyCategories writes names of Categories referenced by Products back to `Category` column
+func RestoreLegacyCategories(ctx context.Context, tx *sql.Tx, d *Dialect) error {
+	query := "UPDATE Product SET `Category`=(SELECT `Name` FROM Category WHERE Category.`ID`=Product.`CategoryID`) " +
+		"WHERE `CategoryID` IS NOT NULL"
+	if _, err := tx.ExecContext(ctx, d.rebind(query)); err != nil {
+		return errors.New("failed to update Product-> " + err.Error())
+	}
+	return nil
+}
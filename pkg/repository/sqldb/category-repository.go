@@ -0,0 +1,256 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// categoryRepository is SQL implementation of repository.CategoryRepository
+type categoryRepository struct {
+	store
+	db *sql.DB
+}
+
+// NewCategoryRepository creates Category repository stored in SQL database of the dialect,
+// database schema must be migrated by Migrator of the dialect
+func NewCategoryRepository(db *sql.DB, dialect *Dialect) repository.CategoryRepository {
+	return &categoryRepository{store: store{db: db, dialect: dialect}, db: db}
+}
+
+// inTx runs fn with store bound to transaction
+func (r *categoryRepository) inTx(ctx context.Context, fn func(s *store) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return r.dialect.storageError("failed to begin transaction", err)
+	}
+	// it is no-op if transaction is committed
+	defer tx.Rollback()
+
+	if err := fn(&store{db: tx, dialect: r.dialect}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return r.dialect.storageError("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// MoveCategory moves Category under the parent, the tree is checked for cycles in the same transaction
+// with all Categories locked, so that concurrent moves can't create a cycle together
+func (r *categoryRepository) MoveCategory(ctx context.Context, id int64, parentID int64) (*repository.Category, error) {
+	var moved *repository.Category
+	err := r.inTx(ctx, func(s *store) error {
+		var err error
+		moved, err = s.MoveCategory(ctx, id, parentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// DeleteCategory removes Category, it is checked for references in the same transaction
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(s *store) error {
+		return s.DeleteCategory(ctx, id)
+	})
+}
+
+// checkCategory checks that Category referenced by Product exists, 0 references no Category
+func (s *store) checkCategory(ctx context.Context, id int64) error {
+	if id == 0 {
+		return nil
+	}
+
+	var one int
+	err := s.queryRow(ctx, "SELECT 1 FROM Category WHERE `ID`=?", id).Scan(&one)
+	switch {
+	case err == sql.ErrNoRows:
+		return &repository.CategoryNotFoundError{ID: id}
+	case err != nil:
+		return s.dialect.storageError("failed to select from Category", err)
+	}
+	return nil
+}
+
+// filterCategories returns IDs of the filter Category and all its subcategories
+func (s *store) filterCategories(ctx context.Context, f repository.Filter) ([]int64, error) {
+	if f.CategoryID == 0 {
+		return nil, nil
+	}
+
+	categories, err := s.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := []int64{f.CategoryID}
+	for _, c := range repository.Descendants(categories, f.CategoryID) {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+// CreateCategory inserts Category
+func (s *store) CreateCategory(ctx context.Context, c *repository.Category) (*repository.Category, error) {
+	if err := s.checkCategory(ctx, c.ParentID); err != nil {
+		return nil, err
+	}
+
+	query := "INSERT INTO Category(`Name`, `ParentID`) VALUES(?, ?)"
+	args := []interface{}{c.Name, nullID(c.ParentID)}
+
+	var id int64
+	if s.dialect.ReturningID {
+		if err := s.queryRow(ctx, query+" RETURNING `ID`", args...).Scan(&id); err != nil {
+			return nil, s.dialect.storageError("failed to insert into Category", err)
+		}
+	} else {
+		res, err := s.exec(ctx, query, args...)
+		if err != nil {
+			return nil, s.dialect.storageError("failed to insert into Category", err)
+		}
+		id, err = res.LastInsertId()
+		if err != nil {
+			return nil, s.dialect.storageError("failed to retrieve id for created Category", err)
+		}
+	}
+
+	created := *c
+	created.ID = id
+	return &created, nil
+}
+
+// scanCategory reads Category from the current row
+func scanCategory(scan func(dest ...interface{}) error) (*repository.Category, error) {
+	var c repository.Category
+	var parentID sql.NullInt64
+	if err := scan(&c.ID, &c.Name, &parentID); err != nil {
+		return nil, err
+	}
+	c.ParentID = parentID.Int64
+	return &c, nil
+}
+
+// GetCategory selects Category by ID
+func (s *store) GetCategory(ctx context.Context, id int64) (*repository.Category, error) {
+	row := s.queryRow(ctx, "SELECT `ID`, `Name`, `ParentID` FROM Category WHERE `ID`=?", id)
+	c, err := scanCategory(row.Scan)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, &repository.CategoryNotFoundError{ID: id}
+	case err != nil:
+		return nil, s.dialect.storageError("failed to select from Category", err)
+	}
+	return c, nil
+}
+
+// UpdateCategory writes name of Category
+func (s *store) UpdateCategory(ctx context.Context, c *repository.Category) (*repository.Category, error) {
+	res, err := s.exec(ctx, "UPDATE Category SET `Name`=? WHERE `ID`=?", c.Name, c.ID)
+	if err != nil {
+		return nil, s.dialect.storageError("failed to update Category", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, s.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+	if rows == 0 {
+		return nil, &repository.CategoryNotFoundError{ID: c.ID}
+	}
+	return s.GetCategory(ctx, c.ID)
+}
+
+// MoveCategory writes parent of Category, it fails if the parent is the Category or any of its subcategories
+func (s *store) MoveCategory(ctx context.Context, id int64, parentID int64) (*repository.Category, error) {
+	categories, err := s.listCategories(ctx, s.dialect.ForUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMove(categories, id, parentID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.exec(ctx, "UPDATE Category SET `ParentID`=? WHERE `ID`=?", nullID(parentID), id); err != nil {
+		return nil, s.dialect.storageError("failed to update Category", err)
+	}
+	return s.GetCategory(ctx, id)
+}
+
+// checkMove checks that Category can be moved under the parent
+func checkMove(categories []*repository.Category, id int64, parentID int64) error {
+	found, parentFound := false, parentID == 0
+	for _, c := range categories {
+		found = found || c.ID == id
+		parentFound = parentFound || c.ID == parentID
+	}
+	if !found {
+		return &repository.CategoryNotFoundError{ID: id}
+	}
+	if !parentFound {
+		return &repository.CategoryNotFoundError{ID: parentID}
+	}
+
+	if parentID == id {
+		return &repository.CategoryCycleError{ID: id, ParentID: parentID}
+	}
+	for _, c := range repository.Descendants(categories, id) {
+		if c.ID == parentID {
+			return &repository.CategoryCycleError{ID: id, ParentID: parentID}
+		}
+	}
+	return nil
+}
+
+// DeleteCategory removes Category which has neither subcategories nor Products
+func (s *store) DeleteCategory(ctx context.Context, id int64) error {
+	if _, err := s.GetCategory(ctx, id); err != nil {
+		return err
+	}
+
+	var refs int64
+	query := "SELECT (SELECT COUNT(*) FROM Category WHERE `ParentID`=?) + (SELECT COUNT(*) FROM Product WHERE `CategoryID`=?)"
+	if err := s.queryRow(ctx, query, id, id).Scan(&refs); err != nil {
+		return s.dialect.storageError("failed to count Category references", err)
+	}
+	if refs > 0 {
+		return &repository.CategoryInUseError{ID: id}
+	}
+
+	if _, err := s.exec(ctx, "DELETE FROM Category WHERE `ID`=?", id); err != nil {
+		return s.dialect.storageError("failed to delete Category", err)
+	}
+	return nil
+}
+
+// ListCategories selects all Categories
+func (s *store) ListCategories(ctx context.Context) ([]*repository.Category, error) {
+	return s.listCategories(ctx, "")
+}
+
+// listCategories selects all Categories, lock is appended to the query, e.g. FOR UPDATE
+func (s *store) listCategories(ctx context.Context, lock string) ([]*repository.Category, error) {
+	rows, err := s.query(ctx, "SELECT `ID`, `Name`, `ParentID` FROM Category ORDER BY `ID`"+lock)
+	if err != nil {
+		return nil, s.dialect.storageError("failed to select from Category", err)
+	}
+	defer rows.Close()
+
+	list := []*repository.Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows.Scan)
+		if err != nil {
+			return nil, errors.New("failed to retrieve field values from Category row-> " + err.Error())
+		}
+		list = append(list, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, s.dialect.storageError("failed to retrieve data from Category", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// Dialect describes SQL flavor of the database.
+// Queries are written with MySQL style `quoted` identifiers and ? placeholders
+// and they are rewritten for the dialect before they are sent to the database.
+type Dialect struct {
+	// Migrations create and change schema of the database
+	Migrations []Migration
+	// QuoteChar quotes identifiers
+	QuoteChar byte
+	// NumberedPlaceholders is set if placeholders are numbered, e.g. $1, $2
+	NumberedPlaceholders bool
+	// LikeEscape is appended to LIKE condition if backslash is not default escape character:
+	// ESCAPE '\' with leading space
+	LikeEscape string
+	// ForUpdate is appended to SELECT to lock the selected rows until the transaction ends: FOR UPDATE with leading space.
+	// It is empty if write transactions lock the whole database when they begin, as SQLite ones do.
+	ForUpdate string
+	// ReturningID is set if ID of the inserted Product is returned by INSERT ... RETURNING
+	// instead of LastInsertId
+	ReturningID bool
+	// ErrorKind classifies errors specific to the database driver, e.g. by error number,
+	// errors common to all drivers are classified before it is called
+	ErrorKind func(err error) repository.ErrorKind
+}
+
+// errorKind classifies error returned by the database driver
+func (d *Dialect) errorKind(err error) repository.ErrorKind {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return repository.KindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return repository.KindDeadlineExceeded
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, syscall.ECONNREFUSED), errors.As(err, &netErr):
+		return repository.KindUnavailable
+	}
+	if d.ErrorKind != nil {
+		return d.ErrorKind(err)
+	}
+	return repository.KindUnknown
+}
+
+// storageError wraps error of the failed operation returned by the database driver
+func (d *Dialect) storageError(op string, err error) error {
+	return &repository.StorageError{Kind: d.errorKind(err), Op: op, Err: err}
+}
+
+// StorageError wraps error returned by the database driver for queries which dialect package runs itself
+func (d *Dialect) StorageError(op string, err error) error {
+	return d.storageError(op, err)
+}
+
+// rebind rewrites query written with `quoted` identifiers and ? placeholders for the dialect
+func (d *Dialect) rebind(query string) string {
+	if d.QuoteChar == '`' && !d.NumberedPlaceholders {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '`':
+			b.WriteByte(d.QuoteChar)
+		case c == '?' && d.NumberedPlaceholders:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
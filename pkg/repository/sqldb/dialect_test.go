@@ -0,0 +1,97 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func TestDialect_rebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect *Dialect
+		query   string
+		want    string
+	}{
+		{
+			name:    "MySQL",
+			dialect: &Dialect{QuoteChar: '`'},
+			query:   "SELECT `ID` FROM Product WHERE `Name`=? AND `Revision`=?",
+			want:    "SELECT `ID` FROM Product WHERE `Name`=? AND `Revision`=?",
+		},
+		{
+			name:    "PostgreSQL",
+			dialect: &Dialect{QuoteChar: '"', NumberedPlaceholders: true},
+			query:   "SELECT `ID` FROM Product WHERE `Name`=? AND `Revision`=?",
+			want:    `SELECT "ID" FROM Product WHERE "Name"=$1 AND "Revision"=$2`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.rebind(tt.query); got != tt.want {
+				t.Errorf("Dialect.rebind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialect_errorKind(t *testing.T) {
+	duplicate := errors.New("duplicate")
+	d := &Dialect{ErrorKind: func(err error) repository.ErrorKind {
+		if err == duplicate {
+			return repository.KindDuplicate
+		}
+		return repository.KindUnknown
+	}}
+
+	tests := []struct {
+		name string
+		err  error
+		want repository.ErrorKind
+	}{
+		{
+			name: "Canceled",
+			err:  context.Canceled,
+			want: repository.KindCanceled,
+		},
+		{
+			name: "Deadline exceeded",
+			err:  fmt.Errorf("query failed: %w", context.DeadlineExceeded),
+			want: repository.KindDeadlineExceeded,
+		},
+		{
+			name: "Connection refused",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Bad connection",
+			err:  driver.ErrBadConn,
+			want: repository.KindUnavailable,
+		},
+		{
+			name: "Driver error",
+			err:  duplicate,
+			want: repository.KindDuplicate,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("failed"),
+			want: repository.KindUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.errorKind(tt.err); got != tt.want {
+				t.Errorf("Dialect.errorKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
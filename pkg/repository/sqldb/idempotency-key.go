@@ -0,0 +1,52 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// GetIdempotencyKey selects idempotency key by request ID unless it is expired
+func (s *store) GetIdempotencyKey(ctx context.Context, requestID string) (*repository.IdempotencyKey, error) {
+	k := repository.IdempotencyKey{RequestID: requestID}
+	err := s.queryRow(ctx, "SELECT `Fingerprint`, `Response`, `ExpiresAt` FROM IdempotencyKey WHERE `RequestID`=? AND `ExpiresAt`>?",
+		requestID, time.Now().UTC()).Scan(&k.Fingerprint, &k.Response, &k.ExpiresAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, &repository.IdempotencyKeyNotFoundError{RequestID: requestID}
+	case err != nil:
+		return nil, s.dialect.storageError("failed to select from IdempotencyKey", err)
+	}
+	return &k, nil
+}
+
+// SaveIdempotencyKey inserts idempotency key, expired key with the same request ID is deleted first
+func (s *store) SaveIdempotencyKey(ctx context.Context, k *repository.IdempotencyKey) error {
+	if _, err := s.exec(ctx, "DELETE FROM IdempotencyKey WHERE `RequestID`=? AND `ExpiresAt`<=?",
+		k.RequestID, time.Now().UTC()); err != nil {
+		return s.dialect.storageError("failed to delete from IdempotencyKey", err)
+	}
+
+	// expiration times are written in UTC
+	if _, err := s.exec(ctx, "INSERT INTO IdempotencyKey(`RequestID`, `Fingerprint`, `Response`, `ExpiresAt`) VALUES(?, ?, ?, ?)",
+		k.RequestID, k.Fingerprint, k.Response, k.ExpiresAt.UTC()); err != nil {
+		return s.dialect.storageError("failed to insert into IdempotencyKey", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes idempotency keys expired before the time
+func (r *productRepository) PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	res, err := r.exec(ctx, "DELETE FROM IdempotencyKey WHERE `ExpiresAt`<?", expiredBefore.UTC())
+	if err != nil {
+		return 0, r.dialect.storageError("failed to purge IdempotencyKey", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, r.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+	return rows, nil
+}
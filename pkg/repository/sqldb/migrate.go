@@ -0,0 +1,175 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// createMigrationsTable creates table keeping versions of applied migrations
+const createMigrationsTable = "CREATE TABLE IF NOT EXISTS schema_migrations (`version` bigint NOT NULL PRIMARY KEY," +
+	"`name` varchar(200) NOT NULL," +
+	"`applied_at` timestamp NOT NULL)"
+
+// Migration is versioned change of database schema
+type Migration struct {
+	// Version orders migrations, it must be unique and it must never change once migration is released
+	Version int64
+	Name    string
+	// Up are statements applying the migration
+	Up []string
+	// Down are statements reverting the migration
+	Down []string
+	// UpData migrates data after Up statements are executed, it is optional
+	UpData DataMigration
+	// DownData reverts migration of data before Down statements are executed, it is optional
+	DownData DataMigration
+}
+
+// DataMigration changes rows of the database in transaction of the migration
+type DataMigration func(ctx context.Context, tx *sql.Tx, d *Dialect) error
+
+// MigrationStatus is migration with time it was applied at
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is zero if migration is pending
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts migrations of the dialect.
+// Every migration runs in its own transaction together with update of schema_migrations table,
+// but note that MySQL commits DDL statements implicitly.
+type Migrator struct {
+	db         *sql.DB
+	dialect    *Dialect
+	migrations []Migration
+}
+
+// NewMigrator creates migrator of database with migrations of the dialect
+func NewMigrator(db *sql.DB, dialect *Dialect) *Migrator {
+	migrations := append([]Migration(nil), dialect.Migrations...)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}
+}
+
+// applied returns versions of applied migrations with time they were applied at
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if _, err := m.db.ExecContext(ctx, m.dialect.rebind(createMigrationsTable)); err != nil {
+		return nil, errors.New("failed to create schema_migrations table-> " + err.Error())
+	}
+
+	rows, err := m.db.QueryContext(ctx, m.dialect.rebind("SELECT `version`, `applied_at` FROM schema_migrations"))
+	if err != nil {
+		return nil, errors.New("failed to select from schema_migrations-> " + err.Error())
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, errors.New("failed to retrieve field values from schema_migrations row-> " + err.Error())
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.New("failed to retrieve data from schema_migrations-> " + err.Error())
+	}
+	return applied, nil
+}
+
+// run executes statements of migration and records the change of its state in one transaction
+func (m *Migrator) run(ctx context.Context, mg Migration, statements []string, before, after DataMigration,
+	record string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.New("failed to begin transaction-> " + err.Error())
+	}
+	// it is no-op if transaction is committed
+	defer tx.Rollback()
+
+	if before != nil {
+		if err := before(ctx, tx, m.dialect); err != nil {
+			return fmt.Errorf("failed to migrate data of version %d '%s'-> %v", mg.Version, mg.Name, err)
+		}
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, m.dialect.rebind(stmt)); err != nil {
+			return fmt.Errorf("failed to migrate to version %d '%s'-> %v", mg.Version, mg.Name, err)
+		}
+	}
+	if after != nil {
+		if err := after(ctx, tx, m.dialect); err != nil {
+			return fmt.Errorf("failed to migrate data of version %d '%s'-> %v", mg.Version, mg.Name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, m.dialect.rebind(record), args...); err != nil {
+		return errors.New("failed to update schema_migrations-> " + err.Error())
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.New("failed to commit transaction-> " + err.Error())
+	}
+	return nil
+}
+
+// Up applies pending migrations in order of versions and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mg := range m.migrations {
+		if _, ok := applied[mg.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, mg, mg.Up, nil, mg.UpData, "INSERT INTO schema_migrations(`version`, `name`, `applied_at`) VALUES(?, ?, ?)",
+			mg.Version, mg.Name, time.Now().UTC())
+		if err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down reverts up to steps applied migrations, the latest first, and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mg := m.migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+		if err := m.run(ctx, mg, mg.Down, mg.DownData, nil, "DELETE FROM schema_migrations WHERE `version`=?", mg.Version); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Status returns all migrations in order of versions with time they were applied at
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		list = append(list, MigrationStatus{Migration: mg, AppliedAt: applied[mg.Version]})
+	}
+	return list, nil
+}
@@ -0,0 +1,82 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+)
+
+// legacyPrice is price of Product row kept as free text before prices were structured
+type legacyPrice struct {
+	id    int64
+	price string
+}
+
+// ParseLegacyPrices fills `PriceMinor` and `PriceCurrency` columns with prices parsed from free text of `Price` column.
+// Prices which can't be parsed are left unset, the text stays in `Price` column.
+func ParseLegacyPrices(ctx context.Context, tx *sql.Tx, d *Dialect) error {
+	rows, err := tx.QueryContext(ctx, d.rebind("SELECT `ID`, `Price` FROM Product WHERE `Price`<>''"))
+	if err != nil {
+		return errors.New("failed to select from Product-> " + err.Error())
+	}
+	// rows are read first, as some drivers can't execute statements while rows are open
+	var prices []legacyPrice
+	for rows.Next() {
+		var p legacyPrice
+		if err := rows.Scan(&p.id, &p.price); err != nil {
+			rows.Close()
+			return errors.New("failed to retrieve field values from Product row-> " + err.Error())
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return errors.New("failed to retrieve data from Product-> " + err.Error())
+	}
+	rows.Close()
+
+	for _, p := range prices {
+		amount, err := money.Parse(p.price)
+		if err != nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, d.rebind("UPDATE Product SET `PriceMinor`=?, `PriceCurrency`=? WHERE `ID`=?"),
+			amount.Minor, amount.Currency, p.id); err != nil {
+			return errors.New("failed to update Product-> " + err.Error())
+		}
+	}
+	return nil
+}
+
+// FormatLegacyPrices writes structured prices back to `Price` column as free text, e.g. "5.00 EUR"
+func FormatLegacyPrices(ctx context.Context, tx *sql.Tx, d *Dialect) error {
+	rows, err := tx.QueryContext(ctx, d.rebind("SELECT `ID`, `PriceMinor`, `PriceCurrency` FROM Product WHERE `PriceCurrency`<>''"))
+	if err != nil {
+		return errors.New("failed to select from Product-> " + err.Error())
+	}
+	var prices []legacyPrice
+	for rows.Next() {
+		var p legacyPrice
+		var amount money.Amount
+		if err := rows.Scan(&p.id, &amount.Minor, &amount.Currency); err != nil {
+			rows.Close()
+			return errors.New("failed to retrieve field values from Product row-> " + err.Error())
+		}
+		p.price = amount.String()
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return errors.New("failed to retrieve data from Product-> " + err.Error())
+	}
+	rows.Close()
+
+	for _, p := range prices {
+		if _, err := tx.ExecContext(ctx, d.rebind("UPDATE Product SET `Price`=? WHERE `ID`=?"), p.price, p.id); err != nil {
+			return errors.New("failed to update Product-> " + err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// dbtx is the subset of *sql.DB and *sql.Tx used to access Product table,
+// so the same queries serve single and batch requests
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// store implements repository.ProductStore on top of database or transaction
+type store struct {
+	db      dbtx
+	dialect *Dialect
+}
+
+// productRepository is SQL implementation of repository.ProductRepository
+type productRepository struct {
+	store
+	db *sql.DB
+}
+
+// productTx is SQL implementation of repository.ProductTx
+type productTx struct {
+	store
+	tx *sql.Tx
+}
+
+// NewProductRepository creates Product repository stored in SQL database of the dialect,
+// database schema must be migrated by Migrator of the dialect
+func NewProductRepository(db *sql.DB, dialect *Dialect) repository.ProductRepository {
+	return &productRepository{store: store{db: db, dialect: dialect}, db: db}
+}
+
+// Update writes the fields of Product and reads back its new state in one transaction
+func (r *productRepository) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	var updated *repository.Product
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		updated, err = tx.Update(ctx, p, fields, expectedRevision)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete marks Product as deleted and reads back its new revision in one transaction
+func (r *productRepository) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	var revision int64
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		revision, err = tx.Delete(ctx, id, expectedRevision)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return revision, nil
+}
+
+// Undelete restores Product and reads back its new state in one transaction
+func (r *productRepository) Undelete(ctx context.Context, id int64) (*repository.Product, error) {
+	var undeleted *repository.Product
+	err := r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		var err error
+		undeleted, err = tx.Undelete(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return undeleted, nil
+}
+
+// InTx runs fn in transaction
+func (r *productRepository) InTx(ctx context.Context, readOnly bool, fn func(tx repository.ProductTx) error) error {
+	var opts *sql.TxOptions
+	if readOnly {
+		opts = &sql.TxOptions{ReadOnly: true}
+	}
+
+	tx, err := r.db.BeginTx(ctx, opts)
+	if err != nil {
+		return r.dialect.storageError("failed to begin transaction", err)
+	}
+	// it is no-op if transaction is committed
+	defer tx.Rollback()
+
+	if err := fn(&productTx{store: store{db: tx, dialect: r.dialect}, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return r.dialect.storageError("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// Purge deletes Products marked as deleted before the time
+func (r *productRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	// deletion times are written in UTC
+	res, err := r.exec(ctx, "DELETE FROM Product WHERE `DeletedAt` IS NOT NULL AND `DeletedAt`<?", deletedBefore.UTC())
+	if err != nil {
+		return 0, r.dialect.storageError("failed to purge Product", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, r.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+	return rows, nil
+}
+
+// Savepoint runs fn and rolls back to savepoint created before fn if it fails
+func (t *productTx) Savepoint(ctx context.Context, fn func() error) error {
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT sp"); err != nil {
+		return t.dialect.storageError("failed to create savepoint", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rerr := t.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT sp"); rerr != nil {
+			return t.dialect.storageError("failed to rollback to savepoint", rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// exec executes query rewritten for the dialect
+func (s *store) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.dialect.rebind(query), args...)
+}
+
+// query runs query rewritten for the dialect
+func (s *store) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.dialect.rebind(query), args...)
+}
+
+// queryRow runs query rewritten for the dialect, which returns at most one row
+func (s *store) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.dialect.rebind(query), args...)
+}
+
+// scanProduct reads Product from the current row selected with selectColumns
+func scanProduct(rows *sql.Rows) (*repository.Product, error) {
+	var p repository.Product
+	var categoryID sql.NullInt64
+	var deletedAt sql.NullTime
+	if err := rows.Scan(&p.ID, &p.Name, &p.Price.Minor, &p.Price.Currency, &p.Creator, &p.Unit, &categoryID, &p.Description, &p.Date, &p.Revision, &deletedAt); err != nil {
+		return nil, errors.New("failed to retrieve field values from Product row-> " + err.Error())
+	}
+	p.CategoryID = categoryID.Int64
+	p.DeletedAt = deletedAt.Time
+	return &p, nil
+}
+
+// notWrittenError returns error for the conditional write of the Product which didn't affect any row:
+// either the Product doesn't exist, it is deleted or it has another revision than expected
+func (s *store) notWrittenError(ctx context.Context, id int64, expectedRevision int64) error {
+	if expectedRevision != 0 {
+		var revision int64
+		err := s.queryRow(ctx, "SELECT `Revision` FROM Product WHERE `ID`=? AND `DeletedAt` IS NULL", id).Scan(&revision)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return s.dialect.storageError("failed to select from Product", err)
+		default:
+			return &repository.RevisionMismatchError{ID: id, Revision: revision, Expected: expectedRevision}
+		}
+	}
+	return &repository.NotFoundError{ID: id}
+}
+
+// Create inserts Product
+func (s *store) Create(ctx context.Context, p *repository.Product) (*repository.Product, error) {
+	if err := s.checkCategory(ctx, p.CategoryID); err != nil {
+		return nil, err
+	}
+
+	// insert Product entity data
+	query := "INSERT INTO Product(`Name`, `PriceMinor`, `PriceCurrency`, `Creator`, `Unit`, `CategoryID`, `Description`, `Date`) VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	args := []interface{}{p.Name, p.Price.Minor, p.Price.Currency, p.Creator, p.Unit, nullID(p.CategoryID), p.Description, p.Date}
+
+	var id int64
+	if s.dialect.ReturningID {
+		// get ID of created Product from inserted row
+		if err := s.queryRow(ctx, query+" RETURNING `ID`", args...).Scan(&id); err != nil {
+			return nil, s.dialect.storageError("failed to insert into Product", err)
+		}
+	} else {
+		res, err := s.exec(ctx, query, args...)
+		if err != nil {
+			return nil, s.dialect.storageError("failed to insert into Product", err)
+		}
+
+		// get ID of creates Product
+		id, err = res.LastInsertId()
+		if err != nil {
+			return nil, s.dialect.storageError("failed to retrieve id for created Product", err)
+		}
+	}
+
+	created := *p
+	created.ID = id
+	created.Revision = 1
+	return &created, nil
+}
+
+// Get selects Product by ID unless it is deleted
+func (s *store) Get(ctx context.Context, id int64) (*repository.Product, error) {
+	// query product by ID
+	rows, err := s.query(ctx, "SELECT "+selectColumns+" FROM Product WHERE `ID`=? AND `DeletedAt` IS NULL",
+		id)
+	if err != nil {
+		return nil, s.dialect.storageError("failed to select from Product", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, s.dialect.storageError("failed to retrieve data from Product", err)
+		}
+		return nil, &repository.NotFoundError{ID: id}
+	}
+
+	// get Product data
+	p, err := scanProduct(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows.Next() {
+		return nil, fmt.Errorf("found multiple Product rows with ID='%d'",
+			id)
+	}
+
+	return p, nil
+}
+
+// Update writes the fields of Product and bumps its revision
+func (s *store) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	set, args, err := updateSQL(p, fields)
+	if err != nil {
+		return nil, err
+	}
+	if hasField(fields, repository.FieldCategoryID) {
+		if err := s.checkCategory(ctx, p.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	// update Product and bump its revision, optionally only if it is still the expected one
+	query := "UPDATE Product" + set + ", `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL"
+	args = append(args, p.ID)
+	if expectedRevision != 0 {
+		query += " AND `Revision`=?"
+		args = append(args, expectedRevision)
+	}
+	res, err := s.exec(ctx, query, args...)
+
+	if err != nil {
+		return nil, s.dialect.storageError("failed to update Product", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, s.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+
+	if rows == 0 {
+		return nil, s.notWrittenError(ctx, p.ID, expectedRevision)
+	}
+	return s.Get(ctx, p.ID)
+}
+
+// Delete marks Product as deleted and bumps its revision
+func (s *store) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	// delete Product, optionally only if it has the expected revision
+	query := "UPDATE Product SET `DeletedAt`=?, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NULL"
+	args := []interface{}{time.Now().UTC(), id}
+	if expectedRevision != 0 {
+		query += " AND `Revision`=?"
+		args = append(args, expectedRevision)
+	}
+	res, err := s.exec(ctx, query, args...)
+	if err != nil {
+		return 0, s.dialect.storageError("failed to delete Product", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, s.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+
+	if rows == 0 {
+		return 0, s.notWrittenError(ctx, id, expectedRevision)
+	}
+
+	var revision int64
+	if err := s.queryRow(ctx, "SELECT `Revision` FROM Product WHERE `ID`=?", id).Scan(&revision); err != nil {
+		return 0, s.dialect.storageError("failed to select Product revision", err)
+	}
+	return revision, nil
+}
+
+// Undelete clears deletion mark of Product and bumps its revision
+func (s *store) Undelete(ctx context.Context, id int64) (*repository.Product, error) {
+	res, err := s.exec(ctx, "UPDATE Product SET `DeletedAt`=NULL, `Revision`=`Revision`+1 WHERE `ID`=? AND `DeletedAt` IS NOT NULL",
+		id)
+	if err != nil {
+		return nil, s.dialect.storageError("failed to undelete Product", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, s.dialect.storageError("failed to retrieve rows affected value", err)
+	}
+
+	if rows == 0 {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	return s.Get(ctx, id)
+}
+
+// List selects Products
+func (s *store) List(ctx context.Context, q repository.ListQuery) ([]*repository.Product, error) {
+	list := []*repository.Product{}
+	err := s.Stream(ctx, q, func(p *repository.Product) error {
+		list = append(list, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Stream selects Products and passes them to fn row by row
+func (s *store) Stream(ctx context.Context, q repository.ListQuery, fn func(p *repository.Product) error) error {
+	categories, err := s.filterCategories(ctx, q.Filter)
+	if err != nil {
+		return err
+	}
+	query, args, err := listSQL(q, categories, s.dialect)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return s.dialect.storageError("failed to select from Product", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return s.dialect.storageError("failed to retrieve data from Product", err)
+	}
+	return nil
+}
+
+// Count counts Products matching the filter
+func (s *store) Count(ctx context.Context, f repository.Filter) (int64, error) {
+	categories, err := s.filterCategories(ctx, f)
+	if err != nil {
+		return 0, err
+	}
+	conds, args := filterSQL(f, categories, s.dialect)
+
+	var total int64
+	if err := s.queryRow(ctx, "SELECT COUNT(*) FROM Product"+whereSQL(conds), args...).Scan(&total); err != nil {
+		return 0, s.dialect.storageError("failed to count Product", err)
+	}
+	return total, nil
+}
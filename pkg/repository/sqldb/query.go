@@ -0,0 +1,245 @@
+package sqldb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+const (
+	// selectColumns are columns of Product table selected by queries, in order of scanProduct
+	selectColumns = "`ID`, `Name`, `PriceMinor`, `PriceCurrency`, `Creator`, `Unit`, `CategoryID`, `Description`, `Date`, `Revision`, `DeletedAt`"
+)
+
+// sortableColumns maps fields Products can be sorted by to Product table columns.
+// Only values from this map are put into ORDER BY clause.
+// Prices are sorted by currency first, amounts of different currencies aren't comparable.
+var sortableColumns = map[repository.Field][]string{
+	repository.FieldID:    {"`ID`"},
+	repository.FieldName:  {"`Name`"},
+	repository.FieldDate:  {"`Date`"},
+	repository.FieldPrice: {"`PriceCurrency`", "`PriceMinor`"},
+}
+
+// orderSQL returns ORDER BY clause, ID breaks ties for keyset pagination
+func orderSQL(o repository.Order) (string, error) {
+	dir := "ASC"
+	if o.Desc {
+		dir = "DESC"
+	}
+	if o.Field == "" || o.Field == repository.FieldID {
+		return " ORDER BY `ID` " + dir, nil
+	}
+	columns, ok := sortableColumns[o.Field]
+	if !ok {
+		return "", fmt.Errorf("sorting by field '%s' is not supported", o.Field)
+	}
+	var order []string
+	for _, column := range columns {
+		order = append(order, column+" "+dir)
+	}
+	return " ORDER BY " + strings.Join(order, ", ") + ", `ID` " + dir, nil
+}
+
+// keysetSQL returns condition selecting rows after the last row of the previous page
+func keysetSQL(o repository.Order, after *repository.Product) (string, []interface{}) {
+	cmp := ">"
+	if o.Desc {
+		cmp = "<"
+	}
+
+	// values of the sort columns of the last row
+	var last []interface{}
+	switch o.Field {
+	case repository.FieldName:
+		last = []interface{}{after.Name}
+	case repository.FieldPrice:
+		last = []interface{}{after.Price.Currency, after.Price.Minor}
+	case repository.FieldDate:
+		last = []interface{}{after.Date}
+	default:
+		return "`ID`" + cmp + "?", []interface{}{after.ID}
+	}
+
+	// rows after the last one have greater value of the first column or the same value
+	// and they are after the last row by the rest of the columns
+	cond, args := "`ID`"+cmp+"?", []interface{}{after.ID}
+	columns := sortableColumns[o.Field]
+	for i := len(columns) - 1; i >= 0; i-- {
+		cond = "(" + columns[i] + cmp + "? OR (" + columns[i] + "=? AND " + cond + "))"
+		args = append([]interface{}{last[i], last[i]}, args...)
+	}
+	return cond, args
+}
+
+// filterSQL translates filter to conditions of WHERE clause and their arguments,
+// categories are IDs of the filter Category and all its subcategories
+func filterSQL(f repository.Filter, categories []int64, d *Dialect) ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if !f.ShowDeleted {
+		conds = append(conds, "`DeletedAt` IS NULL")
+	}
+	if f.CategoryID != 0 {
+		conds = append(conds, "`CategoryID` IN (?"+strings.Repeat(", ?", len(categories)-1)+")")
+		for _, id := range categories {
+			args = append(args, id)
+		}
+	}
+	if len(f.Creator) > 0 {
+		conds = append(conds, "`Creator`=?")
+		args = append(args, f.Creator)
+	}
+	if len(f.Unit) > 0 {
+		conds = append(conds, "`Unit`=?")
+		args = append(args, f.Unit)
+	}
+	if !f.DateFrom.IsZero() {
+		conds = append(conds, "`Date`>=?")
+		args = append(args, f.DateFrom)
+	}
+	if !f.DateTo.IsZero() {
+		conds = append(conds, "`Date`<?")
+		args = append(args, f.DateTo)
+	}
+	if len(f.NamePrefix) > 0 {
+		conds = append(conds, "`Name` LIKE ?"+d.LikeEscape)
+		args = append(args, escapeLike(f.NamePrefix)+"%")
+	}
+	if !f.PriceFrom.IsZero() {
+		conds = append(conds, "`PriceCurrency`=? AND `PriceMinor`>=?")
+		args = append(args, f.PriceFrom.Currency, f.PriceFrom.Minor)
+	}
+	if !f.PriceTo.IsZero() {
+		conds = append(conds, "`PriceCurrency`=? AND `PriceMinor`<?")
+		args = append(args, f.PriceTo.Currency, f.PriceTo.Minor)
+	}
+
+	return conds, args
+}
+
+// whereSQL joins conditions into WHERE clause
+func whereSQL(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// escapeLike escapes wildcard characters of LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// listSQL returns query selecting Products and its arguments
+func listSQL(q repository.ListQuery, categories []int64, d *Dialect) (string, []interface{}, error) {
+	order, err := orderSQL(q.Order)
+	if err != nil {
+		return "", nil, err
+	}
+
+	conds, args := filterSQL(q.Filter, categories, d)
+	if q.After != nil {
+		cond, condArgs := keysetSQL(q.Order, q.After)
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+
+	query := "SELECT " + selectColumns + " FROM Product" + whereSQL(conds) + order
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+	return query, args, nil
+}
+
+// updatableColumns maps Product fields to Product table columns
+var updatableColumns = map[repository.Field]string{
+	repository.FieldName:        "`Name`",
+	repository.FieldPrice:       "`PriceMinor`",
+	repository.FieldUnit:        "`Unit`",
+	repository.FieldCategoryID:  "`CategoryID`",
+	repository.FieldCreator:     "`Creator`",
+	repository.FieldDescription: "`Description`",
+	repository.FieldDate:        "`Date`",
+}
+
+// fullUpdateFields are fields written by Update if no fields are listed
+var fullUpdateFields = []repository.Field{
+	repository.FieldName,
+	repository.FieldPrice,
+	repository.FieldUnit,
+	repository.FieldCategoryID,
+	repository.FieldCreator,
+	repository.FieldDescription,
+	repository.FieldDate,
+}
+
+// updateSQL translates fields to SET clause and its arguments
+func updateSQL(p *repository.Product, fields []repository.Field) (string, []interface{}, error) {
+	if len(fields) == 0 {
+		fields = fullUpdateFields
+	}
+
+	var sets []string
+	var args []interface{}
+	seen := map[repository.Field]bool{}
+	for _, field := range fields {
+		column, ok := updatableColumns[field]
+		if !ok {
+			return "", nil, fmt.Errorf("updating field '%s' is not supported", field)
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+
+		var value interface{}
+		switch field {
+		case repository.FieldName:
+			value = p.Name
+		case repository.FieldPrice:
+			// currency is written along with the amount
+			sets = append(sets, column+"=?")
+			args = append(args, p.Price.Minor)
+			column, value = "`PriceCurrency`", p.Price.Currency
+		case repository.FieldUnit:
+			value = p.Unit
+		case repository.FieldCategoryID:
+			value = nullID(p.CategoryID)
+		case repository.FieldCreator:
+			value = p.Creator
+		case repository.FieldDescription:
+			value = p.Description
+		case repository.FieldDate:
+			value = p.Date
+		}
+		sets = append(sets, column+"=?")
+		args = append(args, value)
+	}
+
+	return " SET " + strings.Join(sets, ", "), args, nil
+}
+
+// nullID converts ID of referenced row to column value, 0 means no row is referenced
+func nullID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// hasField checks if the Product field is written by update of fields
+func hasField(fields []repository.Field, field repository.Field) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
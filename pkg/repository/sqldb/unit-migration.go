@@ -0,0 +1,49 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/MartyKuentzel/projectX/pkg/measure"
+)
+
+// NormalizeUnits replaces units written as free text, e.g. "Kg" or "kilo", with unit codes.
+// Units which are not known are left as they are.
+func NormalizeUnits(ctx context.Context, tx *sql.Tx, d *Dialect) error {
+	// rows are not grouped by unit, as MySQL compares strings case-insensitively
+	rows, err := tx.QueryContext(ctx, d.rebind("SELECT `ID`, `Unit` FROM Product WHERE `Unit`<>''"))
+	if err != nil {
+		return errors.New("failed to select from Product-> " + err.Error())
+	}
+	// rows are read first, as some drivers can't execute statements while rows are open
+	type unit struct {
+		id   int64
+		name string
+	}
+	var units []unit
+	for rows.Next() {
+		var u unit
+		if err := rows.Scan(&u.id, &u.name); err != nil {
+			rows.Close()
+			return errors.New("failed to retrieve field values from Product row-> " + err.Error())
+		}
+		units = append(units, u)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return errors.New("failed to retrieve data from Product-> " + err.Error())
+	}
+	rows.Close()
+
+	for _, u := range units {
+		parsed, ok := measure.Parse(u.name)
+		if !ok || parsed.Code == u.name {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, d.rebind("UPDATE Product SET `Unit`=? WHERE `ID`=?"), parsed.Code, u.id); err != nil {
+			return errors.New("failed to update Product-> " + err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_categoryRepository(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "products.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+	if _, err := NewMigrator(db).Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	r := NewCategoryRepository(db)
+
+	// food (1) -> vegetable (2) -> roots (3)
+	for _, c := range []repository.Category{{Name: "food"}, {Name: "vegetable", ParentID: 1}, {Name: "roots", ParentID: 2}} {
+		if _, err := r.CreateCategory(ctx, &c); err != nil {
+			t.Fatalf("categoryRepository.CreateCategory() error = %v", err)
+		}
+	}
+	if _, err := r.CreateCategory(ctx, &repository.Category{Name: "fruit", ParentID: 7}); !reflect.DeepEqual(err,
+		&repository.CategoryNotFoundError{ID: 7}) {
+		t.Errorf("categoryRepository.CreateCategory() error = %v, want CategoryNotFoundError", err)
+	}
+
+	updated, err := r.UpdateCategory(ctx, &repository.Category{ID: 2, Name: "vegetables"})
+	want := &repository.Category{ID: 2, Name: "vegetables", ParentID: 1}
+	if err != nil || !reflect.DeepEqual(updated, want) {
+		t.Errorf("categoryRepository.UpdateCategory() = %v, %v, want %v", updated, err, want)
+	}
+
+	if _, err := r.MoveCategory(ctx, 1, 3); !reflect.DeepEqual(err, &repository.CategoryCycleError{ID: 1, ParentID: 3}) {
+		t.Errorf("categoryRepository.MoveCategory() error = %v, want CategoryCycleError", err)
+	}
+	moved, err := r.MoveCategory(ctx, 3, 0)
+	want = &repository.Category{ID: 3, Name: "roots"}
+	if err != nil || !reflect.DeepEqual(moved, want) {
+		t.Errorf("categoryRepository.MoveCategory() = %v, %v, want %v", moved, err, want)
+	}
+
+	// Category in use by subcategory or Product is not deleted
+	if err := r.DeleteCategory(ctx, 1); !reflect.DeepEqual(err, &repository.CategoryInUseError{ID: 1}) {
+		t.Errorf("categoryRepository.DeleteCategory() error = %v, want CategoryInUseError", err)
+	}
+	if _, err := NewProductRepository(db).Create(ctx, &repository.Product{Name: "potato", CategoryID: 3, Date: time.Now()}); err != nil {
+		t.Fatalf("productRepository.Create() error = %v", err)
+	}
+	if err := r.DeleteCategory(ctx, 3); !reflect.DeepEqual(err, &repository.CategoryInUseError{ID: 3}) {
+		t.Errorf("categoryRepository.DeleteCategory() error = %v, want CategoryInUseError", err)
+	}
+	if err := r.DeleteCategory(ctx, 2); err != nil {
+		t.Errorf("categoryRepository.DeleteCategory() error = %v", err)
+	}
+	if _, err := r.GetCategory(ctx, 2); !reflect.DeepEqual(err, &repository.CategoryNotFoundError{ID: 2}) {
+		t.Errorf("categoryRepository.GetCategory() error = %v, want CategoryNotFoundError", err)
+	}
+
+	list, err := r.ListCategories(ctx)
+	wantList := []*repository.Category{{ID: 1, Name: "food"}, {ID: 3, Name: "roots"}}
+	if err != nil || !reflect.DeepEqual(list, wantList) {
+		t.Errorf("categoryRepository.ListCategories() = %v, %v, want %v", list, err, wantList)
+	}
+
+	// foreign key is enforced by database as well
+	if _, err := db.ExecContext(ctx, "UPDATE Product SET `CategoryID`=7"); err == nil {
+		t.Errorf("Product references Category which doesn't exist")
+	}
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// errorKind classifies errors of SQLite driver by result code,
+// extended result codes carry the primary result code in the least significant byte
+func errorKind(err error) repository.ErrorKind {
+	var liteErr *sqlite.Error
+	if !errors.As(err, &liteErr) {
+		return repository.KindUnknown
+	}
+	switch code := liteErr.Code(); {
+	case code == sqlite3.SQLITE_CONSTRAINT_UNIQUE, code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return repository.KindDuplicate
+	case code&0xff == sqlite3.SQLITE_BUSY, code&0xff == sqlite3.SQLITE_LOCKED:
+		return repository.KindConflict
+	case code&0xff == sqlite3.SQLITE_INTERRUPT:
+		return repository.KindCanceled
+	}
+	return repository.KindUnknown
+}
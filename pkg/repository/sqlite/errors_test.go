@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_errorKind(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "products.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+	if _, err := NewMigrator(db).Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+
+	insert := "INSERT INTO Category (`ID`, `Name`) VALUES (1, 'food')"
+	if _, err := db.ExecContext(ctx, insert); err != nil {
+		t.Fatalf("failed to insert Category: %v", err)
+	}
+	_, dupErr := db.ExecContext(ctx, insert)
+	_, fkErr := db.ExecContext(ctx, "INSERT INTO Category (`ID`, `Name`, `ParentID`) VALUES (2, 'fruit', 7)")
+
+	tests := []struct {
+		name string
+		err  error
+		want repository.ErrorKind
+	}{
+		{
+			name: "Duplicate primary key",
+			err:  dupErr,
+			want: repository.KindDuplicate,
+		},
+		{
+			name: "Foreign key violation",
+			err:  fkErr,
+			want: repository.KindUnknown,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("failed"),
+			want: repository.KindUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				t.Fatal("statement succeeded, want error")
+			}
+			if got := errorKind(tt.err); got != tt.want {
+				t.Errorf("errorKind(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// migrations of SQLite database schema, released migrations must never be changed
+var migrations = []sqldb.Migration{
+	{
+		Version: 1,
+		Name:    "create_product",
+		// table could be created by the server before migrations were introduced, so it must have the same schema
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS `Product` (`ID` INTEGER PRIMARY KEY AUTOINCREMENT," +
+				"`Name` varchar(200) DEFAULT NULL," +
+				"`Price` varchar(200) DEFAULT NULL," +
+				"`Creator` varchar(200) DEFAULT NULL," +
+				"`Unit` varchar(200) DEFAULT NULL," +
+				"`Category` varchar(200) DEFAULT NULL," +
+				"`Description` varchar(1024) DEFAULT NULL," +
+				"`Date` timestamp NULL DEFAULT NULL)",
+		},
+		Down: []string{
+			"DROP TABLE `Product`",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "product_revision",
+		// Revision of existing Products starts at 1
+		Up: []string{
+			"ALTER TABLE `Product` ADD COLUMN `Revision` INTEGER NOT NULL DEFAULT 1",
+		},
+		Down: []string{
+			"ALTER TABLE `Product` DROP COLUMN `Revision`",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "structured_price",
+		// legacy Price column is kept, but it is not written anymore
+		Up: []string{
+			// SQLite alters one column per statement
+			"ALTER TABLE `Product` ADD COLUMN `PriceMinor` INTEGER NOT NULL DEFAULT 0",
+			"ALTER TABLE `Product` ADD COLUMN `PriceCurrency` varchar(3) NOT NULL DEFAULT ''",
+		},
+		UpData: sqldb.ParseLegacyPrices,
+		Down: []string{
+			"ALTER TABLE `Product` DROP COLUMN `PriceMinor`",
+			"ALTER TABLE `Product` DROP COLUMN `PriceCurrency`",
+		},
+		DownData: sqldb.FormatLegacyPrices,
+	},
+	{
+		Version: 4,
+		Name:    "normalize_unit",
+		// normalized units are valid free text as well, so there is nothing to revert
+		UpData: sqldb.NormalizeUnits,
+	},
+	{
+		Version: 5,
+		Name:    "category_tree",
+		// legacy Category column is kept, but it is not written anymore
+		Up: []string{
+			"CREATE TABLE `Category` (`ID` INTEGER PRIMARY KEY AUTOINCREMENT," +
+				"`Name` varchar(200) NOT NULL," +
+				"`ParentID` INTEGER NULL REFERENCES `Category` (`ID`))",
+			"ALTER TABLE `Product` ADD COLUMN `CategoryID` INTEGER NULL REFERENCES `Category` (`ID`)",
+		},
+		UpData: sqldb.MigrateLegacyCategories,
+		Down: []string{
+			"ALTER TABLE `Product` DROP COLUMN `CategoryID`",
+			"DROP TABLE `Category`",
+		},
+		DownData: sqldb.RestoreLegacyCategories,
+	},
+	{
+		Version: 6,
+		Name:    "soft_delete",
+		// Products deleted before the migration is rolled back are removed permanently
+		Up: []string{
+			"ALTER TABLE `Product` ADD COLUMN `DeletedAt` timestamp NULL DEFAULT NULL",
+			"CREATE INDEX `idx_product_deleted_at` ON `Product` (`DeletedAt`)",
+		},
+		Down: []string{
+			"DELETE FROM `Product` WHERE `DeletedAt` IS NOT NULL",
+			"DROP INDEX `idx_product_deleted_at`",
+			"ALTER TABLE `Product` DROP COLUMN `DeletedAt`",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "idempotency_key",
+		Up: []string{
+			"CREATE TABLE `IdempotencyKey` (`RequestID` varchar(128) PRIMARY KEY," +
+				"`Fingerprint` char(64) NOT NULL," +
+				"`Response` BLOB NOT NULL," +
+				"`ExpiresAt` timestamp NOT NULL)",
+			"CREATE INDEX `idx_idempotency_key_expires_at` ON `IdempotencyKey` (`ExpiresAt`)",
+		},
+		Down: []string{
+			"DROP TABLE `IdempotencyKey`",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "product_fulltext",
+		// FULLTEXT indexes are created in MySQL only, Products of other databases are searched by in-process index
+	},
+}
+
+// NewMigrator creates migrator of SQLite database schema
+func NewMigrator(db *sql.DB) *sqldb.Migrator {
+	return sqldb.NewMigrator(db, dialect)
+}
@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "products.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+	m := NewMigrator(db)
+
+	done, err := m.Up(ctx)
+	if err != nil || len(done) != len(migrations) {
+		t.Fatalf("Migrator.Up() = %v, %v, want all migrations", done, err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT 1 FROM Product"); err != nil {
+		t.Errorf("table Product is not created: %v", err)
+	}
+
+	// applied migrations are skipped
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Errorf("Migrator.Up() = %v, %v, want no migrations", done, err)
+	}
+
+	list, err := m.Status(ctx)
+	if err != nil || len(list) != len(migrations) {
+		t.Fatalf("Migrator.Status() = %v, %v, want all migrations", list, err)
+	}
+	for _, s := range list {
+		if s.AppliedAt.IsZero() {
+			t.Errorf("Migrator.Status() migration %d is pending, want applied", s.Version)
+		}
+	}
+
+	done, err = m.Down(ctx, len(migrations))
+	if err != nil || len(done) != len(migrations) || done[0].Version != migrations[len(migrations)-1].Version {
+		t.Fatalf("Migrator.Down() = %v, %v, want all migrations, the latest first", done, err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT 1 FROM Product"); err == nil {
+		t.Errorf("table Product is not dropped")
+	}
+	list, err = m.Status(ctx)
+	if err != nil || !list[0].AppliedAt.IsZero() {
+		t.Errorf("Migrator.Status() = %v, %v, want pending migration", list, err)
+	}
+}
+
+func TestMigrator_legacyData(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "products.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+	m := NewMigrator(db)
+
+	// legacy data is written into the table created by the first migration, it has schema of the table created
+	// by the server before migrations were introduced
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	if _, err := m.Down(ctx, len(migrations)-1); err != nil {
+		t.Fatalf("Migrator.Down() error = %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO Product(`Name`, `Price`, `Creator`, `Unit`, `Category`, `Description`, `Date`) "+
+		"VALUES('potato', '5,50 €', '', 'Kilo', 'vegetable', '', CURRENT_TIMESTAMP), ('tomato', 'cheap', '', 'bag', 'vegetable', '', CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatalf("failed to insert legacy prices: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+
+	r := NewProductRepository(db)
+	tests := []struct {
+		id        int64
+		wantPrice money.Amount
+		wantUnit  string
+	}{
+		{id: 1, wantPrice: money.Amount{Currency: "EUR", Minor: 550}, wantUnit: "kg"},
+		{id: 2, wantUnit: "bag"},
+	}
+	for _, tt := range tests {
+		p, err := r.Get(ctx, tt.id)
+		if err != nil || p.Price != tt.wantPrice || p.Unit != tt.wantUnit || p.CategoryID != 1 || p.Revision != 1 {
+			t.Errorf("productRepository.Get(%d) = %v, %v, want price %v, unit %s, Category 1 and revision 1", tt.id, p, err, tt.wantPrice, tt.wantUnit)
+		}
+	}
+	if _, err := NewCategoryRepository(db).UpdateCategory(ctx, &repository.Category{ID: 1, Name: "vegetables"}); err != nil {
+		t.Fatalf("categoryRepository.UpdateCategory() error = %v", err)
+	}
+
+	if _, err := r.Update(ctx, &repository.Product{ID: 1, Price: money.Amount{Currency: "USD", Minor: 600}},
+		[]repository.Field{repository.FieldPrice}, 0); err != nil {
+		t.Fatalf("productRepository.Update() error = %v", err)
+	}
+	if _, err := m.Down(ctx, len(migrations)-1); err != nil {
+		t.Fatalf("Migrator.Down() error = %v", err)
+	}
+	var price string
+	if err := db.QueryRowContext(ctx, "SELECT `Price` FROM Product WHERE `ID`=1").Scan(&price); err != nil || price != "6.00 USD" {
+		t.Errorf("legacy price = %q, %v, want '6.00 USD'", price, err)
+	}
+	var category string
+	if err := db.QueryRowContext(ctx, "SELECT `Category` FROM Product WHERE `ID`=2").Scan(&category); err != nil || category != "vegetables" {
+		t.Errorf("legacy category = %q, %v, want 'vegetables'", category, err)
+	}
+}
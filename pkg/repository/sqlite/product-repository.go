@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+
+	// pure Go sqlite driver, it doesn't need cgo
+	_ "modernc.org/sqlite"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/sqldb"
+)
+
+// dialect is SQLite flavor of SQL, it accepts MySQL style `quoted` identifiers
+var dialect = &sqldb.Dialect{
+	Migrations: migrations,
+	QuoteChar:  '`',
+	ErrorKind:  errorKind,
+	LikeEscape: ` ESCAPE '\'`,
+}
+
+// Open opens SQLite database file in WAL mode, the file is created if it doesn't exist.
+// Write transactions take the write lock when they begin and wait for it up to busy timeout,
+// so that concurrent transactions don't fail with SQLITE_BUSY on their first write.
+// Foreign keys are enforced, SQLite doesn't do it by default.
+func Open(file string) (*sql.DB, error) {
+	q := url.Values{}
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Set("_txlock", "immediate")
+	return sql.Open("sqlite", "file:"+file+"?"+q.Encode())
+}
+
+// NewProductRepository creates Product repository stored in SQLite database
+func NewProductRepository(db *sql.DB) repository.ProductRepository {
+	return sqldb.NewProductRepository(db, dialect)
+}
+
+// NewCategoryRepository creates Category repository stored in SQLite database
+func NewCategoryRepository(db *sql.DB) repository.CategoryRepository {
+	return sqldb.NewCategoryRepository(db, dialect)
+}
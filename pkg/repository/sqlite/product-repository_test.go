@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_productRepository(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "products")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := Open(filepath.Join(dir, "products.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+	if _, err := NewMigrator(db).Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	r := NewProductRepository(db)
+	categories := NewCategoryRepository(db)
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("journal_mode = %q, %v, want wal", mode, err)
+	}
+
+	vegetable, err := categories.CreateCategory(ctx, &repository.Category{Name: "vegetable"})
+	if err != nil {
+		t.Fatalf("categoryRepository.CreateCategory() error = %v", err)
+	}
+	roots, err := categories.CreateCategory(ctx, &repository.Category{Name: "roots", ParentID: vegetable.ID})
+	if err != nil {
+		t.Fatalf("categoryRepository.CreateCategory() error = %v", err)
+	}
+
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, p := range []repository.Product{
+		{Name: "potato", CategoryID: roots.ID, Date: tm},
+		{Name: "pot_ato", CategoryID: vegetable.ID, Date: tm},
+		{Name: "tomato", CategoryID: vegetable.ID, Date: tm},
+	} {
+		if _, err := r.Create(ctx, &p); err != nil {
+			t.Fatalf("productRepository.Create() error = %v", err)
+		}
+	}
+	if _, err := r.Create(ctx, &repository.Product{Name: "apple", CategoryID: 7, Date: tm}); !reflect.DeepEqual(err,
+		&repository.CategoryNotFoundError{ID: 7}) {
+		t.Errorf("productRepository.Create() error = %v, want CategoryNotFoundError", err)
+	}
+
+	got, err := r.Get(ctx, 1)
+	want := &repository.Product{ID: 1, Name: "potato", CategoryID: roots.ID, Date: tm, Revision: 1}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("productRepository.Get() = %v, %v, want %v", got, err, want)
+	}
+
+	updated, err := r.Update(ctx, &repository.Product{ID: 3, Price: money.Amount{Currency: "EUR", Minor: 200}}, []repository.Field{repository.FieldPrice}, 1)
+	want = &repository.Product{ID: 3, Name: "tomato", Price: money.Amount{Currency: "EUR", Minor: 200}, CategoryID: vegetable.ID, Date: tm, Revision: 2}
+	if err != nil || !reflect.DeepEqual(updated, want) {
+		t.Errorf("productRepository.Update() = %v, %v, want %v", updated, err, want)
+	}
+	if _, err := r.Update(ctx, &repository.Product{ID: 3}, nil, 1); !reflect.DeepEqual(err,
+		&repository.RevisionMismatchError{ID: 3, Revision: 2, Expected: 1}) {
+		t.Errorf("productRepository.Update() error = %v, want RevisionMismatchError", err)
+	}
+
+	// Products without price go first, prices are sorted by currency
+	if list, err := r.List(ctx, repository.ListQuery{
+		Order: repository.Order{Field: repository.FieldPrice},
+		After: &repository.Product{ID: 1},
+	}); err != nil || len(list) != 2 || list[0].ID != 2 || list[1].ID != 3 {
+		t.Errorf("productRepository.List() = %v, %v, want Products 2 and 3", list, err)
+	}
+
+	// subcategories are included in the filter
+	if total, err := r.Count(ctx, repository.Filter{CategoryID: vegetable.ID}); err != nil || total != 3 {
+		t.Errorf("productRepository.Count() = %d, %v, want 3", total, err)
+	}
+	if list, err := r.List(ctx, repository.ListQuery{Filter: repository.Filter{CategoryID: roots.ID}}); err != nil ||
+		len(list) != 1 || list[0].ID != 1 {
+		t.Errorf("productRepository.List() = %v, %v, want Product 1", list, err)
+	}
+
+	// underscore in name prefix is not a wildcard
+	list, err := r.List(ctx, repository.ListQuery{
+		Filter: repository.Filter{NamePrefix: "pot_"},
+		Order:  repository.Order{Field: repository.FieldName, Desc: true},
+	})
+	if err != nil || len(list) != 1 || list[0].ID != 2 {
+		t.Errorf("productRepository.List() = %v, %v, want Product 2", list, err)
+	}
+
+	err = r.InTx(ctx, false, func(tx repository.ProductTx) error {
+		err := tx.Savepoint(ctx, func() error {
+			_, err := tx.Delete(ctx, 5, 0)
+			return err
+		})
+		if err == nil {
+			t.Errorf("productTx.Delete() error = nil, want NotFoundError")
+		}
+		return tx.Savepoint(ctx, func() error {
+			_, err := tx.Delete(ctx, 1, 0)
+			return err
+		})
+	})
+	if err != nil {
+		t.Errorf("productRepository.InTx() error = %v", err)
+	}
+	if total, err := r.Count(ctx, repository.Filter{CategoryID: vegetable.ID}); err != nil || total != 2 {
+		t.Errorf("productRepository.Count() = %d, %v, want 2", total, err)
+	}
+	err = r.InTx(ctx, true, func(tx repository.ProductTx) error {
+		_, err := tx.Get(ctx, 1)
+		return err
+	})
+	if !reflect.DeepEqual(err, &repository.NotFoundError{ID: 1}) {
+		t.Errorf("productRepository.InTx() error = %v, want NotFoundError", err)
+	}
+
+	// deleted Product is kept until it is purged
+	list, err = r.List(ctx, repository.ListQuery{Filter: repository.Filter{CategoryID: roots.ID, ShowDeleted: true}})
+	if err != nil || len(list) != 1 || list[0].DeletedAt.IsZero() || list[0].Revision != 2 {
+		t.Errorf("productRepository.List() = %v, %v, want deleted Product 1", list, err)
+	}
+	undeleted, err := r.Undelete(ctx, 1)
+	want = &repository.Product{ID: 1, Name: "potato", CategoryID: roots.ID, Date: tm, Revision: 3}
+	if err != nil || !reflect.DeepEqual(undeleted, want) {
+		t.Errorf("productRepository.Undelete() = %v, %v, want %v", undeleted, err, want)
+	}
+	if _, err := r.Undelete(ctx, 1); !reflect.DeepEqual(err, &repository.NotFoundError{ID: 1}) {
+		t.Errorf("productRepository.Undelete() error = %v, want NotFoundError", err)
+	}
+	if revision, err := r.Delete(ctx, 1, 3); err != nil || revision != 4 {
+		t.Errorf("productRepository.Delete() = %d, %v, want 4", revision, err)
+	}
+	if purged, err := r.Purge(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("productRepository.Purge() = %d, %v, want 0", purged, err)
+	}
+	if purged, err := r.Purge(ctx, time.Now().Add(time.Minute)); err != nil || purged != 1 {
+		t.Errorf("productRepository.Purge() = %d, %v, want 1", purged, err)
+	}
+	if total, err := r.Count(ctx, repository.Filter{ShowDeleted: true}); err != nil || total != 2 {
+		t.Errorf("productRepository.Count() = %d, %v, want 2", total, err)
+	}
+
+	// idempotency key is replaced once it expires
+	key := &repository.IdempotencyKey{RequestID: "r1", Fingerprint: "f1", Response: []byte("response"), ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := r.SaveIdempotencyKey(ctx, key); err != nil {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v", err)
+	}
+	if _, err := r.GetIdempotencyKey(ctx, "r1"); !reflect.DeepEqual(err, &repository.IdempotencyKeyNotFoundError{RequestID: "r1"}) {
+		t.Errorf("productRepository.GetIdempotencyKey() error = %v, want IdempotencyKeyNotFoundError", err)
+	}
+	key.ExpiresAt = time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	if err := r.SaveIdempotencyKey(ctx, key); err != nil {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v", err)
+	}
+	if got, err := r.GetIdempotencyKey(ctx, "r1"); err != nil || !reflect.DeepEqual(got, key) {
+		t.Errorf("productRepository.GetIdempotencyKey() = %v, %v, want %v", got, err, key)
+	}
+	var storageErr *repository.StorageError
+	if err := r.SaveIdempotencyKey(ctx, key); !errors.As(err, &storageErr) || storageErr.Kind != repository.KindDuplicate {
+		t.Errorf("productRepository.SaveIdempotencyKey() error = %v, want KindDuplicate", err)
+	}
+	if purged, err := r.PurgeIdempotencyKeys(ctx, time.Now().Add(2*time.Hour)); err != nil || purged != 1 {
+		t.Errorf("productRepository.PurgeIdempotencyKeys() = %d, %v, want 1", purged, err)
+	}
+}
@@ -0,0 +1,86 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// HighlightStart and HighlightEnd wrap matched terms in snippets
+	HighlightStart = "<em>"
+	HighlightEnd   = "</em>"
+
+	// ellipsis marks text cut off from snippet
+	ellipsis = "…"
+)
+
+// Snippet returns part of text around the first matched term with all matched terms highlighted.
+// Text is HTML escaped, so highlighting tags are the only markup of the snippet.
+// Snippet is at most about maxLength characters long, it is empty if text contains none of the terms.
+func Snippet(text string, terms []string, maxLength int) string {
+	matched := map[string]bool{}
+	for _, t := range terms {
+		matched[t] = true
+	}
+
+	var hits []token
+	for _, t := range tokens(text) {
+		if matched[t.term] {
+			hits = append(hits, t)
+		}
+	}
+	if len(hits) == 0 {
+		return ""
+	}
+
+	// window starts a bit before the first hit, so that the hit has some context
+	start, end := 0, len(text)
+	if utf8.RuneCountInString(text) > maxLength {
+		start = backward(text, hits[0].start, maxLength/4)
+		end = forward(text, start, maxLength)
+		if end == len(text) {
+			start = backward(text, end, maxLength)
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := start
+	for _, h := range hits {
+		if h.start < start {
+			continue
+		}
+		if h.end > end {
+			break
+		}
+		b.WriteString(html.EscapeString(text[pos:h.start]))
+		b.WriteString(HighlightStart + html.EscapeString(text[h.start:h.end]) + HighlightEnd)
+		pos = h.end
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// backward returns byte offset n characters before offset i, or 0
+func backward(text string, i int, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(text[:i])
+		i -= size
+	}
+	return i
+}
+
+// forward returns byte offset n characters after offset i, or length of text
+func forward(text string, i int, n int) int {
+	for ; n > 0 && i < len(text); n-- {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return i
+}
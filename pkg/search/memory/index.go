@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+// weights of Product fields, term in name is more relevant than term in description
+const (
+	nameWeight        = 3
+	categoryWeight    = 2
+	descriptionWeight = 1
+)
+
+// BM25 ranking parameters
+const (
+	// k1 limits impact of repeated term
+	k1 = 1.2
+	// b is impact of document length
+	b = 0.75
+)
+
+// document is indexed Product
+type document struct {
+	// terms are frequencies of terms of the Product, every field containing the term adds its weight
+	terms map[string]float64
+	// length is sum of frequencies of all terms of the Product
+	length float64
+	// revision is revision of the indexed Product
+	revision int64
+}
+
+// index is in-process inverted index of Products
+type index struct {
+	mu         sync.RWMutex
+	categories repository.CategoryRepository
+
+	docs map[int64]document
+	// removed are revisions Products were deleted at, they are kept
+	// so that Product written before deletion isn't indexed again
+	removed map[int64]int64
+	// postings are IDs of Products containing the term
+	postings map[string]map[int64]bool
+	// totalLength is sum of lengths of all documents
+	totalLength float64
+}
+
+// NewIndex creates empty in-process index of Products ranked by BM25.
+// Category names are read from categories when Product is indexed, so Products of renamed Category
+// must be indexed again, see search.ReindexCategory.
+func NewIndex(categories repository.CategoryRepository) search.Index {
+	return &index{
+		categories: categories,
+		docs:       map[int64]document{},
+		removed:    map[int64]int64{},
+		postings:   map[string]map[int64]bool{},
+	}
+}
+
+// Index adds or replaces Product in the index unless it is older than the indexed or removed revision.
+// The same revision is indexed again, so that renamed Category is reflected.
+func (x *index) Index(ctx context.Context, p *repository.Product) error {
+	d := document{terms: map[string]float64{}, revision: p.Revision}
+	add := func(text string, weight float64) {
+		for _, t := range search.Terms(text) {
+			d.terms[t] += weight
+			d.length += weight
+		}
+	}
+	add(p.Name, nameWeight)
+	add(p.Description, descriptionWeight)
+	if p.CategoryID != 0 {
+		c, err := x.categories.GetCategory(ctx, p.CategoryID)
+		var notFound *repository.CategoryNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			// Product is indexed without Category
+		case err != nil:
+			return err
+		default:
+			add(c.Name, categoryWeight)
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if old, ok := x.docs[p.ID]; ok && p.Revision < old.revision {
+		return nil
+	}
+	if revision, ok := x.removed[p.ID]; ok {
+		if p.Revision <= revision {
+			return nil
+		}
+		// Product is undeleted
+		delete(x.removed, p.ID)
+	}
+	x.remove(p.ID)
+	x.docs[p.ID] = d
+	x.totalLength += d.length
+	for t := range d.terms {
+		if x.postings[t] == nil {
+			x.postings[t] = map[int64]bool{}
+		}
+		x.postings[t][p.ID] = true
+	}
+	return nil
+}
+
+// Remove removes Product deleted at the revision from the index unless its newer revision is indexed
+func (x *index) Remove(ctx context.Context, id int64, revision int64) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if d, ok := x.docs[id]; ok && revision < d.revision {
+		return nil
+	}
+	if revision > x.removed[id] {
+		x.removed[id] = revision
+	}
+	x.remove(id)
+	return nil
+}
+
+// remove removes Product from the index, caller holds the lock
+func (x *index) remove(id int64) {
+	d, ok := x.docs[id]
+	if !ok {
+		return
+	}
+	for t := range d.terms {
+		delete(x.postings[t], id)
+		if len(x.postings[t]) == 0 {
+			delete(x.postings, t)
+		}
+	}
+	x.totalLength -= d.length
+	delete(x.docs, id)
+}
+
+// Search scores Products containing any of the terms
+func (x *index) Search(ctx context.Context, q search.Query) (*search.Result, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	n := float64(len(x.docs))
+	avgLength := x.totalLength / math.Max(n, 1)
+	scores := map[int64]float64{}
+	for _, t := range q.Terms {
+		ids := x.postings[t]
+		df := float64(len(ids))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id := range ids {
+			d := x.docs[id]
+			tf := d.terms[t]
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*d.length/avgLength))
+		}
+	}
+
+	hits := make([]search.Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, search.Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	res := &search.Result{Total: int64(len(hits))}
+	if q.Offset < len(hits) {
+		hits = hits[q.Offset:]
+		if q.Limit > 0 && len(hits) > q.Limit {
+			hits = hits[:q.Limit]
+		}
+		res.Hits = hits
+	}
+	return res, nil
+}
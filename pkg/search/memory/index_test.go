@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	store "github.com/MartyKuentzel/projectX/pkg/repository/memory"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+// hitIDs returns IDs of the hits in order
+func hitIDs(res *search.Result) []int64 {
+	var ids []int64
+	for _, h := range res.Hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func Test_index(t *testing.T) {
+	ctx := context.Background()
+	repo, err := store.NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	organic, err := repo.CreateCategory(ctx, &repository.Category{Name: "Organic"})
+	if err != nil {
+		t.Fatalf("failed to create Category: %v", err)
+	}
+
+	x := NewIndex(repo)
+	for _, p := range []*repository.Product{
+		{ID: 1, Name: "Potato", Description: "Yellow potato from the farm"},
+		{ID: 2, Name: "Tomato", Description: "Goes well with potato"},
+		{ID: 3, Name: "Potato", Description: "Organic potato", CategoryID: organic.ID},
+		{ID: 4, Name: "Carrot", CategoryID: organic.ID},
+		{ID: 5, Name: "Onion"},
+	} {
+		if err := x.Index(ctx, p); err != nil {
+			t.Fatalf("index.Index() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		q         search.Query
+		wantIDs   []int64
+		wantTotal int64
+	}{
+		{
+			name:      "More matched terms and rarer terms rank higher",
+			q:         search.Query{Terms: []string{"organic", "potato"}, Limit: 10},
+			wantIDs:   []int64{3, 4, 1, 2},
+			wantTotal: 4,
+		},
+		{
+			name:      "Name ranks higher than description",
+			q:         search.Query{Terms: []string{"potato"}, Limit: 10},
+			wantIDs:   []int64{3, 1, 2},
+			wantTotal: 3,
+		},
+		{
+			name:      "Page",
+			q:         search.Query{Terms: []string{"potato"}, Offset: 1, Limit: 1},
+			wantIDs:   []int64{1},
+			wantTotal: 3,
+		},
+		{
+			name:      "Offset after the last hit",
+			q:         search.Query{Terms: []string{"potato"}, Offset: 3, Limit: 1},
+			wantTotal: 3,
+		},
+		{
+			name: "No match",
+			q:    search.Query{Terms: []string{"cucumber"}, Limit: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := x.Search(ctx, tt.q)
+			if err != nil {
+				t.Fatalf("index.Search() error = %v", err)
+			}
+			if ids := hitIDs(got); !reflect.DeepEqual(ids, tt.wantIDs) || got.Total != tt.wantTotal {
+				t.Errorf("index.Search() = %v, total %d, want %v, total %d", ids, got.Total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+
+	// replaced and removed Products aren't found by their old terms
+	if err := x.Index(ctx, &repository.Product{ID: 1, Name: "Onion"}); err != nil {
+		t.Fatalf("index.Index() error = %v", err)
+	}
+	if err := x.Remove(ctx, 3, 1); err != nil {
+		t.Fatalf("index.Remove() error = %v", err)
+	}
+	got, err := x.Search(ctx, search.Query{Terms: []string{"potato"}, Limit: 10})
+	if err != nil || !reflect.DeepEqual(hitIDs(got), []int64{2}) {
+		t.Errorf("index.Search() = %v, %v, want [2]", hitIDs(got), err)
+	}
+	got, err = x.Search(ctx, search.Query{Terms: []string{"onion"}, Limit: 10})
+	if err != nil || !reflect.DeepEqual(hitIDs(got), []int64{1, 5}) {
+		t.Errorf("index.Search() = %v, %v, want [1 5]", hitIDs(got), err)
+	}
+}
+
+func Test_index_revisions(t *testing.T) {
+	ctx := context.Background()
+	x := NewIndex(nil)
+	find := func(term string) []int64 {
+		got, err := x.Search(ctx, search.Query{Terms: []string{term}, Limit: 10})
+		if err != nil {
+			t.Fatalf("index.Search() error = %v", err)
+		}
+		if int(got.Total) != len(got.Hits) {
+			t.Errorf("index.Search() total = %d, want %d", got.Total, len(got.Hits))
+		}
+		return hitIDs(got)
+	}
+
+	// write finished late doesn't replace newer revision
+	for _, p := range []*repository.Product{{ID: 1, Name: "Tomato", Revision: 2}, {ID: 1, Name: "Potato", Revision: 1}} {
+		if err := x.Index(ctx, p); err != nil {
+			t.Fatalf("index.Index() error = %v", err)
+		}
+	}
+	if ids := find("tomato"); !reflect.DeepEqual(ids, []int64{1}) {
+		t.Errorf("index.Search() = %v, want [1]", ids)
+	}
+
+	// write finished after deletion doesn't resurrect Product
+	if err := x.Remove(ctx, 1, 3); err != nil {
+		t.Fatalf("index.Remove() error = %v", err)
+	}
+	if err := x.Index(ctx, &repository.Product{ID: 1, Name: "Tomato", Revision: 2}); err != nil {
+		t.Fatalf("index.Index() error = %v", err)
+	}
+	if ids := find("tomato"); len(ids) != 0 {
+		t.Errorf("index.Search() = %v, want none", ids)
+	}
+
+	// undeleted Product is indexed again
+	if err := x.Index(ctx, &repository.Product{ID: 1, Name: "Tomato", Revision: 4}); err != nil {
+		t.Fatalf("index.Index() error = %v", err)
+	}
+	if err := x.Remove(ctx, 1, 3); err != nil {
+		t.Fatalf("index.Remove() error = %v", err)
+	}
+	if ids := find("tomato"); !reflect.DeepEqual(ids, []int64{1}) {
+		t.Errorf("index.Search() = %v, want [1]", ids)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	ctx := context.Background()
+	repo, err := store.NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	for _, name := range []string{"potato", "tomato"} {
+		if _, err := repo.Create(ctx, &repository.Product{Name: name}); err != nil {
+			t.Fatalf("failed to create Product: %v", err)
+		}
+	}
+	if _, err := repo.Delete(ctx, 2, 0); err != nil {
+		t.Fatalf("failed to delete Product: %v", err)
+	}
+
+	x := NewIndex(repo)
+	if err := search.Rebuild(ctx, x, repo); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	got, err := x.Search(ctx, search.Query{Terms: []string{"potato", "tomato"}, Limit: 10})
+	if err != nil || !reflect.DeepEqual(hitIDs(got), []int64{1}) {
+		t.Errorf("index.Search() = %v, %v, want [1]", hitIDs(got), err)
+	}
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// Query selects page of Products matching search terms
+type Query struct {
+	// Terms are normalized search terms, see Terms
+	Terms []string
+	// Offset is number of hits skipped. Hits are ranked again by every search, so pages of offsets skip
+	// or repeat hits if Products are written between the searches.
+	Offset int
+	// Limit is maximum number of hits returned, it must be positive
+	Limit int
+}
+
+// Hit is Product matching the query
+type Hit struct {
+	ID int64
+	// Score is relevance of the Product, it is comparable between hits of the same query only
+	Score float64
+}
+
+// Result is page of hits ordered by relevance, ID breaks ties
+type Result struct {
+	Hits []Hit
+	// Total is number of Products matching the query
+	Total int64
+}
+
+// Index finds Products by text of their name, description and category.
+// Products match if they contain any of the terms, Products containing more terms rank higher.
+type Index interface {
+	// Search returns page of hits of the query
+	Search(ctx context.Context, q Query) (*Result, error)
+	// Index adds or replaces Product in the index. Writes of Product can reach the index out of order,
+	// so Product is ignored if the index has its newer revision or it is removed at a newer revision.
+	Index(ctx context.Context, p *repository.Product) error
+	// Remove removes Product deleted at the revision from the index
+	Remove(ctx context.Context, id int64, revision int64) error
+}
+
+// Rebuild indexes all Products of the repository, deleted Products are skipped
+func Rebuild(ctx context.Context, idx Index, repo repository.ProductStore) error {
+	return repo.Stream(ctx, repository.ListQuery{Order: repository.Order{Field: repository.FieldID}}, func(p *repository.Product) error {
+		return idx.Index(ctx, p)
+	})
+}
+
+// ReindexCategory indexes Products of the Category again, so that they are found by its new name
+func ReindexCategory(ctx context.Context, idx Index, repo repository.ProductStore, categoryID int64) error {
+	return repo.Stream(ctx, repository.ListQuery{Filter: repository.Filter{CategoryID: categoryID}}, func(p *repository.Product) error {
+		// Products of subcategories are selected by the filter too
+		if p.CategoryID != categoryID {
+			return nil
+		}
+		return idx.Index(ctx, p)
+	})
+}
+
+// Terms splits text into lower case words, duplicates are removed
+func Terms(text string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, t := range tokens(text) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// token is word of text and its byte range
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokens splits text into lower case words, words consist of letters and digits
+func tokens(text string) []token {
+	var list []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			list = append(list, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		list = append(list, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return list
+}
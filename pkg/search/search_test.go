@@ -0,0 +1,73 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "Words", text: "Organic  potato, organic!", want: []string{"organic", "potato"}},
+		{name: "Letters and digits", text: "Käse 200g", want: []string{"käse", "200g"}},
+		{name: "No words", text: " - ", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terms(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("x ", 50) + "organic potato from <farm>" + strings.Repeat(" y", 50)
+
+	tests := []struct {
+		name      string
+		text      string
+		terms     []string
+		maxLength int
+		want      string
+	}{
+		{
+			name:      "Whole text",
+			text:      "Organic potato & onion",
+			terms:     []string{"organic", "onion"},
+			maxLength: 100,
+			want:      "<em>Organic</em> potato &amp; <em>onion</em>",
+		},
+		{
+			name:      "No match",
+			text:      "tomato",
+			terms:     []string{"potato"},
+			maxLength: 100,
+		},
+		{
+			name:      "Window around the first match",
+			text:      long,
+			terms:     []string{"potato"},
+			maxLength: 40,
+			want:      "…x organic <em>potato</em> from &lt;farm&gt; y y y y y y…",
+		},
+		{
+			name:      "Match at the end",
+			text:      "x x x x x x x potato",
+			terms:     []string{"potato"},
+			maxLength: 10,
+			want:      "…x x <em>potato</em>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(tt.text, tt.terms, tt.maxLength); got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package v1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+// categoryServiceServer is implementation of v1.CategoryServiceServer proto interface
+type categoryServiceServer struct {
+	repo repository.CategoryRepository
+
+	// products of renamed Category are indexed again in index, it is nil if search is disabled
+	products repository.ProductStore
+	index    search.Index
+}
+
+// NewCategoryServiceServer creates Category service,
+// Products of renamed Category are read from products and indexed again in index unless it is nil
+func NewCategoryServiceServer(repo repository.CategoryRepository, products repository.ProductStore,
+	index search.Index) v1.CategoryServiceServer {
+	return &categoryServiceServer{repo: repo, products: products, index: index}
+}
+
+// categoryToProto converts Category from repository to API representation
+func categoryToProto(c *repository.Category) *v1.Category {
+	return &v1.Category{Id: c.ID, Name: c.Name, ParentId: c.ParentID}
+}
+
+// categoryName validates name of Category, its length matches column of Category table
+func categoryName(c *v1.Category) (string, error) {
+	v := &violations{}
+	name := strings.TrimSpace(c.GetName())
+	if len(name) == 0 {
+		v.add("category.name", "is required")
+	}
+	v.length("category.name", name, maxNameLength)
+	return name, v.err()
+}
+
+// findCategory returns Category with the ID from the list
+func findCategory(categories []*repository.Category, id int64) (*repository.Category, error) {
+	for _, c := range categories {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, &repository.CategoryNotFoundError{ID: id}
+}
+
+// reindexCategory indexes Products of renamed Category again. Failure is logged only, because Category
+// is renamed already, its Products are indexed again when they are written next time or the index is rebuilt.
+func (s *categoryServiceServer) reindexCategory(ctx context.Context, id int64) {
+	if s.index == nil {
+		return
+	}
+	if err := search.ReindexCategory(ctx, s.index, s.products, id); err != nil {
+		ctxzap.Extract(ctx).Error("failed to index Products of Category", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// CreateCategory creates new category
+func (s *categoryServiceServer) CreateCategory(ctx context.Context, req *v1.CreateCategoryRequest) (*v1.CreateCategoryResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	name, err := categoryName(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateCategory(ctx, &repository.Category{Name: name, ParentID: req.Category.ParentId})
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	return &v1.CreateCategoryResponse{
+		Api: apiVersion,
+		Id:  created.ID,
+	}, nil
+}
+
+// ReadCategory reads category by ID
+func (s *categoryServiceServer) ReadCategory(ctx context.Context, req *v1.ReadCategoryRequest) (*v1.ReadCategoryResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.GetCategory(ctx, req.Id)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	return &v1.ReadCategoryResponse{
+		Api:      apiVersion,
+		Category: categoryToProto(c),
+	}, nil
+}
+
+// UpdateCategory renames category
+func (s *categoryServiceServer) UpdateCategory(ctx context.Context, req *v1.UpdateCategoryRequest) (*v1.UpdateCategoryResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	name, err := categoryName(req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateCategory(ctx, &repository.Category{ID: req.Category.Id, Name: name})
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	s.reindexCategory(ctx, updated.ID)
+
+	return &v1.UpdateCategoryResponse{
+		Api:      apiVersion,
+		Category: categoryToProto(updated),
+	}, nil
+}
+
+// DeleteCategory deletes category which has neither subcategories nor products
+func (s *categoryServiceServer) DeleteCategory(ctx context.Context, req *v1.DeleteCategoryRequest) (*v1.DeleteCategoryResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteCategory(ctx, req.Id); err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	return &v1.DeleteCategoryResponse{
+		Api:     apiVersion,
+		Deleted: 1,
+	}, nil
+}
+
+// MoveCategory moves category with its subcategories under another parent
+func (s *categoryServiceServer) MoveCategory(ctx context.Context, req *v1.MoveCategoryRequest) (*v1.MoveCategoryResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	moved, err := s.repo.MoveCategory(ctx, req.Id, req.ParentId)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	return &v1.MoveCategoryResponse{
+		Api:      apiVersion,
+		Category: categoryToProto(moved),
+	}, nil
+}
+
+// ReadCategoryTree reads category with all its subcategories, or the whole tree if root is not set
+func (s *categoryServiceServer) ReadCategoryTree(ctx context.Context, req *v1.ReadCategoryTreeRequest) (*v1.ReadCategoryTreeResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	// parents go before their children, so nodes are linked in one pass
+	nodes := map[int64]*v1.CategoryNode{}
+	roots := []*v1.CategoryNode{}
+	if req.RootId != 0 {
+		root, err := findCategory(categories, req.RootId)
+		if err != nil {
+			return nil, repositoryError(ctx, err)
+		}
+		nodes[root.ID] = &v1.CategoryNode{Category: categoryToProto(root)}
+		roots = append(roots, nodes[root.ID])
+	}
+	for _, c := range repository.Descendants(categories, req.RootId) {
+		node := &v1.CategoryNode{Category: categoryToProto(c)}
+		nodes[c.ID] = node
+		if parent, ok := nodes[c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return &v1.ReadCategoryTreeResponse{
+		Api:   apiVersion,
+		Roots: roots,
+	}, nil
+}
+
+// ListDescendants lists all subcategories of category
+func (s *categoryServiceServer) ListDescendants(ctx context.Context, req *v1.ListDescendantsRequest) (*v1.ListDescendantsResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	if _, err := findCategory(categories, req.Id); err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	list := []*v1.Category{}
+	for _, c := range repository.Descendants(categories, req.Id) {
+		list = append(list, categoryToProto(c))
+	}
+
+	return &v1.ListDescendantsResponse{
+		Api:        apiVersion,
+		Categories: list,
+	}, nil
+}
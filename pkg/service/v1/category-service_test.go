@@ -0,0 +1,213 @@
+package v1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/memory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newCategoryRepository creates repository with Categories food (1) -> vegetable (2) -> roots (3), food (1) -> fruit (4)
+// and Product of Category roots
+func newCategoryRepository(t *testing.T) repository.Repository {
+	ctx := context.Background()
+	r, err := memory.NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	for _, c := range []repository.Category{{Name: "food"}, {Name: "vegetable", ParentID: 1}, {Name: "roots", ParentID: 2}, {Name: "fruit", ParentID: 1}} {
+		if _, err := r.CreateCategory(ctx, &c); err != nil {
+			t.Fatalf("failed to create Category: %v", err)
+		}
+	}
+	if _, err := r.Create(ctx, &repository.Product{Name: "potato", CategoryID: 3}); err != nil {
+		t.Fatalf("failed to create Product: %v", err)
+	}
+	return r
+}
+
+func Test_categoryServiceServer_CreateCategory(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		req      *v1.CreateCategoryRequest
+		want     *v1.CreateCategoryResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "OK",
+			req:  &v1.CreateCategoryRequest{Api: "v1", Category: &v1.Category{Name: " berries ", ParentId: 4}},
+			want: &v1.CreateCategoryResponse{Api: "v1", Id: 5},
+		},
+		{
+			name:     "Unsupported API",
+			req:      &v1.CreateCategoryRequest{Api: "v1000", Category: &v1.Category{Name: "berries"}},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name:     "Empty name",
+			req:      &v1.CreateCategoryRequest{Api: "v1", Category: &v1.Category{Name: " "}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "No category",
+			req:      &v1.CreateCategoryRequest{Api: "v1"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown parent",
+			req:      &v1.CreateCategoryRequest{Api: "v1", Category: &v1.Category{Name: "berries", ParentId: 7}},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewCategoryServiceServer(newCategoryRepository(t), nil, nil)
+			got, err := s.CreateCategory(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("categoryServiceServer.CreateCategory() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("categoryServiceServer.CreateCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_categoryServiceServer_CRUD(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func(s v1.CategoryServiceServer) (interface{}, error)
+		want     interface{}
+		wantCode codes.Code
+	}{
+		{
+			name: "Read",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.ReadCategory(ctx, &v1.ReadCategoryRequest{Api: "v1", Id: 2})
+			},
+			want: &v1.ReadCategoryResponse{Api: "v1", Category: &v1.Category{Id: 2, Name: "vegetable", ParentId: 1}},
+		},
+		{
+			name: "Read unknown",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.ReadCategory(ctx, &v1.ReadCategoryRequest{Api: "v1", Id: 7})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Rename",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.UpdateCategory(ctx, &v1.UpdateCategoryRequest{Api: "v1", Category: &v1.Category{Id: 2, Name: "vegetables", ParentId: 4}})
+			},
+			want: &v1.UpdateCategoryResponse{Api: "v1", Category: &v1.Category{Id: 2, Name: "vegetables", ParentId: 1}},
+		},
+		{
+			name: "Move",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.MoveCategory(ctx, &v1.MoveCategoryRequest{Api: "v1", Id: 3, ParentId: 4})
+			},
+			want: &v1.MoveCategoryResponse{Api: "v1", Category: &v1.Category{Id: 3, Name: "roots", ParentId: 4}},
+		},
+		{
+			name: "Move under subcategory",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.MoveCategory(ctx, &v1.MoveCategoryRequest{Api: "v1", Id: 1, ParentId: 3})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Delete",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.DeleteCategory(ctx, &v1.DeleteCategoryRequest{Api: "v1", Id: 4})
+			},
+			want: &v1.DeleteCategoryResponse{Api: "v1", Deleted: 1},
+		},
+		{
+			name: "Delete with Products",
+			call: func(s v1.CategoryServiceServer) (interface{}, error) {
+				return s.DeleteCategory(ctx, &v1.DeleteCategoryRequest{Api: "v1", Id: 3})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call(NewCategoryServiceServer(newCategoryRepository(t), nil, nil))
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("categoryServiceServer error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("categoryServiceServer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_categoryServiceServer_ReadCategoryTree(t *testing.T) {
+	ctx := context.Background()
+	s := NewCategoryServiceServer(newCategoryRepository(t), nil, nil)
+	node := func(id int64, name string, parentID int64, children ...*v1.CategoryNode) *v1.CategoryNode {
+		return &v1.CategoryNode{Category: &v1.Category{Id: id, Name: name, ParentId: parentID}, Children: children}
+	}
+
+	tests := []struct {
+		name     string
+		req      *v1.ReadCategoryTreeRequest
+		want     []*v1.CategoryNode
+		wantCode codes.Code
+	}{
+		{
+			name: "Whole tree",
+			req:  &v1.ReadCategoryTreeRequest{Api: "v1"},
+			want: []*v1.CategoryNode{node(1, "food", 0, node(2, "vegetable", 1, node(3, "roots", 2)), node(4, "fruit", 1))},
+		},
+		{
+			name: "Subtree",
+			req:  &v1.ReadCategoryTreeRequest{Api: "v1", RootId: 2},
+			want: []*v1.CategoryNode{node(2, "vegetable", 1, node(3, "roots", 2))},
+		},
+		{
+			name:     "Unknown root",
+			req:      &v1.ReadCategoryTreeRequest{Api: "v1", RootId: 7},
+			wantCode: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ReadCategoryTree(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("categoryServiceServer.ReadCategoryTree() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got.Roots, tt.want) {
+				t.Errorf("categoryServiceServer.ReadCategoryTree() = %v, want %v", got.Roots, tt.want)
+			}
+		})
+	}
+}
+
+func Test_categoryServiceServer_ListDescendants(t *testing.T) {
+	ctx := context.Background()
+	s := NewCategoryServiceServer(newCategoryRepository(t), nil, nil)
+
+	got, err := s.ListDescendants(ctx, &v1.ListDescendantsRequest{Api: "v1", Id: 1})
+	want := []*v1.Category{{Id: 2, Name: "vegetable", ParentId: 1}, {Id: 3, Name: "roots", ParentId: 2}, {Id: 4, Name: "fruit", ParentId: 1}}
+	if err != nil || !reflect.DeepEqual(got.Categories, want) {
+		t.Errorf("categoryServiceServer.ListDescendants() = %v, %v, want %v", got, err, want)
+	}
+
+	if _, err := s.ListDescendants(ctx, &v1.ListDescendantsRequest{Api: "v1", Id: 7}); status.Code(err) != codes.NotFound {
+		t.Errorf("categoryServiceServer.ListDescendants() error = %v, wantCode %v", err, codes.NotFound)
+	}
+}
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+const (
+	// changeLogSize is number of the latest Product changes kept for watchers to resume from
+	changeLogSize = 10000
+)
+
+var (
+	// errMalformedResumeToken is returned for resume token not issued by the change log
+	errMalformedResumeToken = errors.New("malformed resume token")
+	// errExpiredResumeToken is returned if changes after the resume token are no longer in the change log
+	errExpiredResumeToken = errors.New("resume token is expired, resync with ReadAll")
+)
+
+// changeEvent is Product change saved in the change log
+type changeEvent struct {
+	// seq is sequence number of the change, it increases by one with every change
+	seq int64
+	res *v1.WatchResponse
+}
+
+// watchToken is resume token handed out to watchers with every event
+type watchToken struct {
+	// Epoch identifies the change log the token was issued by
+	Epoch string `json:"e"`
+	// Seq is sequence number of the event
+	Seq int64 `json:"s"`
+}
+
+// changeLog is bounded in-memory log of Product changes.
+// It lives only as long as the server process, so tokens issued
+// before restart are rejected as expired.
+type changeLog struct {
+	mu sync.Mutex
+
+	epoch  string
+	size   int
+	events []changeEvent
+	// next is sequence number of the next change
+	next int64
+	// revisions are the latest revisions of Products having changes in the log
+	revisions map[int64]int64
+	// notify is closed and replaced when a change is appended
+	notify chan struct{}
+}
+
+// newChangeLog creates change log keeping at most size changes
+func newChangeLog(size int) *changeLog {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic("failed to generate change log epoch: " + err.Error())
+	}
+	return &changeLog{
+		epoch:  hex.EncodeToString(b),
+		size:   size,
+		next:      1,
+		revisions: make(map[int64]int64),
+		notify:    make(chan struct{}),
+	}
+}
+
+// append saves the change and wakes up watchers.
+// Changes are appended after their transactions commit, so concurrent writers of a Product
+// can arrive out of order; the change older than the latest change of the Product
+// in the log is dropped, watchers received its newer state already.
+func (l *changeLog) append(t v1.ChangeType, p *v1.ProductProto) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if rev, ok := l.revisions[p.Id]; ok && p.Revision <= rev {
+		return
+	}
+	l.revisions[p.Id] = p.Revision
+
+	seq := l.next
+	l.next++
+	l.events = append(l.events, changeEvent{
+		seq: seq,
+		res: &v1.WatchResponse{
+			Api:         apiVersion,
+			Type:        t,
+			Product:     p,
+			ResumeToken: l.token(seq),
+		},
+	})
+	if len(l.events) > l.size {
+		n := len(l.events) - l.size
+		for _, e := range l.events[:n] {
+			// forget the Product if its latest change is dropped
+			if p := e.res.Product; l.revisions[p.Id] == p.Revision {
+				delete(l.revisions, p.Id)
+			}
+		}
+		l.events = l.events[n:]
+	}
+
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// token returns resume token of the change with sequence number seq
+func (l *changeLog) token(seq int64) string {
+	b, err := json.Marshal(watchToken{Epoch: l.epoch, Seq: seq})
+	if err != nil {
+		// watchToken consists of plain fields only
+		panic("failed to marshal resume token: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// resume returns sequence number of the first change the watcher hasn't received yet.
+// Empty token means the watcher is interested in changes made from now on.
+func (l *changeLog) resume(token string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(token) == 0 {
+		return l.next, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errMalformedResumeToken
+	}
+	var t watchToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return 0, errMalformedResumeToken
+	}
+	if t.Epoch != l.epoch {
+		return 0, errExpiredResumeToken
+	}
+	if t.Seq < 1 || t.Seq >= l.next {
+		return 0, errMalformedResumeToken
+	}
+	return t.Seq + 1, nil
+}
+
+// since returns changes starting with sequence number seq and channel closed on the next change.
+// It fails if some of the requested changes were already dropped from the log.
+func (l *changeLog) since(seq int64) ([]*v1.WatchResponse, <-chan struct{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if seq < l.next && (len(l.events) == 0 || seq < l.events[0].seq) {
+		return nil, nil, errExpiredResumeToken
+	}
+
+	var res []*v1.WatchResponse
+	if n := l.next - seq; n > 0 {
+		res = make([]*v1.WatchResponse, 0, n)
+		for _, e := range l.events[len(l.events)-int(n):] {
+			res = append(res, e.res)
+		}
+	}
+	return res, l.notify, nil
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"testing"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+func Test_changeLog(t *testing.T) {
+	l := newChangeLog(2)
+
+	seq, err := l.resume("")
+	if err != nil || seq != 1 {
+		t.Fatalf("changeLog.resume() = %d, %v, want 1, nil", seq, err)
+	}
+
+	events, wait, err := l.since(seq)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("changeLog.since() = %v, %v, want no events", events, err)
+	}
+
+	l.append(v1.ChangeType_CREATED, &v1.ProductProto{Id: 1, Revision: 1})
+	select {
+	case <-wait:
+	default:
+		t.Fatalf("changeLog.append() didn't notify watchers")
+	}
+
+	l.append(v1.ChangeType_UPDATED, &v1.ProductProto{Id: 1, Revision: 2})
+	l.append(v1.ChangeType_DELETED, &v1.ProductProto{Id: 1, Revision: 3})
+
+	// the first change is dropped from the log
+	if _, _, err := l.since(1); err != errExpiredResumeToken {
+		t.Errorf("changeLog.since(1) error = %v, want %v", err, errExpiredResumeToken)
+	}
+
+	events, _, err = l.since(2)
+	if err != nil {
+		t.Fatalf("changeLog.since(2) error = %v", err)
+	}
+	if len(events) != 2 || events[0].Type != v1.ChangeType_UPDATED || events[1].Type != v1.ChangeType_DELETED {
+		t.Fatalf("changeLog.since(2) = %v, want UPDATED and DELETED events", events)
+	}
+
+	seq, err = l.resume(events[0].ResumeToken)
+	if err != nil || seq != 3 {
+		t.Errorf("changeLog.resume() = %d, %v, want 3, nil", seq, err)
+	}
+
+	if _, err := l.resume(l.token(4)); err != errMalformedResumeToken {
+		t.Errorf("changeLog.resume() of future token error = %v, want %v", err, errMalformedResumeToken)
+	}
+}
+
+func Test_changeLog_stale(t *testing.T) {
+	l := newChangeLog(2)
+
+	l.append(v1.ChangeType_UPDATED, &v1.ProductProto{Id: 1, Revision: 3})
+	l.append(v1.ChangeType_DELETED, &v1.ProductProto{Id: 1, Revision: 4})
+	// update committed before the deletion is appended late
+	l.append(v1.ChangeType_UPDATED, &v1.ProductProto{Id: 1, Revision: 2})
+
+	events, _, err := l.since(1)
+	if err != nil {
+		t.Fatalf("changeLog.since(1) error = %v", err)
+	}
+	if len(events) != 2 || events[1].Type != v1.ChangeType_DELETED {
+		t.Fatalf("changeLog.since(1) = %v, want UPDATED and DELETED events", events)
+	}
+
+	// the Product is forgotten when its changes are dropped from the log
+	l.append(v1.ChangeType_CREATED, &v1.ProductProto{Id: 2, Revision: 1})
+	l.append(v1.ChangeType_CREATED, &v1.ProductProto{Id: 3, Revision: 1})
+	if _, ok := l.revisions[1]; ok {
+		t.Errorf("changeLog.revisions = %v, want Product 1 forgotten", l.revisions)
+	}
+}
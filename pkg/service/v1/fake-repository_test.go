@@ -0,0 +1,278 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"google.golang.org/genproto/protobuf/field_mask"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// fakeRepository keeps Products in map, it sorts them by ID and filters them by category
+// and deletion only
+type fakeRepository struct {
+	products map[int64]repository.Product
+	nextID   int64
+	keys     map[string]repository.IdempotencyKey
+
+	// err is returned by every call if it is set
+	err error
+	// query is the last query passed to List or Stream
+	query repository.ListQuery
+}
+
+// fakeTx is transaction of fakeRepository working on copy of the Products
+type fakeTx struct {
+	*fakeRepository
+}
+
+func newFakeRepository(products ...repository.Product) *fakeRepository {
+	r := &fakeRepository{products: map[int64]repository.Product{}, nextID: 1, keys: map[string]repository.IdempotencyKey{}}
+	for _, p := range products {
+		r.products[p.ID] = p
+		if p.ID >= r.nextID {
+			r.nextID = p.ID + 1
+		}
+	}
+	return r
+}
+
+func (r *fakeRepository) clone() *fakeRepository {
+	c := *r
+	c.products = map[int64]repository.Product{}
+	for id, p := range r.products {
+		c.products[id] = p
+	}
+	c.keys = map[string]repository.IdempotencyKey{}
+	for requestID, k := range r.keys {
+		c.keys[requestID] = k
+	}
+	return &c
+}
+
+func (r *fakeRepository) Create(ctx context.Context, p *repository.Product) (*repository.Product, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	created := *p
+	created.ID = r.nextID
+	created.Revision = 1
+	r.nextID++
+	r.products[created.ID] = created
+	return &created, nil
+}
+
+func (r *fakeRepository) Get(ctx context.Context, id int64) (*repository.Product, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	p, ok := r.products[id]
+	if !ok || !p.DeletedAt.IsZero() {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	return &p, nil
+}
+
+func (r *fakeRepository) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	old, err := r.Get(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if expectedRevision != 0 && old.Revision != expectedRevision {
+		return nil, &repository.RevisionMismatchError{ID: p.ID, Revision: old.Revision, Expected: expectedRevision}
+	}
+
+	updated := *old
+	if hasField(fields, repository.FieldName) {
+		updated.Name = p.Name
+	}
+	if hasField(fields, repository.FieldPrice) {
+		updated.Price = p.Price
+	}
+	if hasField(fields, repository.FieldUnit) {
+		updated.Unit = p.Unit
+	}
+	if hasField(fields, repository.FieldCategoryID) {
+		updated.CategoryID = p.CategoryID
+	}
+	if hasField(fields, repository.FieldCreator) {
+		updated.Creator = p.Creator
+	}
+	if hasField(fields, repository.FieldDescription) {
+		updated.Description = p.Description
+	}
+	if hasField(fields, repository.FieldDate) {
+		updated.Date = p.Date
+	}
+	updated.Revision++
+	r.products[p.ID] = updated
+	return &updated, nil
+}
+
+func (r *fakeRepository) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	old, err := r.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if expectedRevision != 0 && old.Revision != expectedRevision {
+		return 0, &repository.RevisionMismatchError{ID: id, Revision: old.Revision, Expected: expectedRevision}
+	}
+	old.DeletedAt = time.Now()
+	old.Revision++
+	r.products[id] = *old
+	return old.Revision, nil
+}
+
+func (r *fakeRepository) Undelete(ctx context.Context, id int64) (*repository.Product, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	p, ok := r.products[id]
+	if !ok || p.DeletedAt.IsZero() {
+		return nil, &repository.NotFoundError{ID: id}
+	}
+	p.DeletedAt = time.Time{}
+	p.Revision++
+	r.products[id] = p
+	return &p, nil
+}
+
+func (r *fakeRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var n int64
+	for id, p := range r.products {
+		if !p.DeletedAt.IsZero() && p.DeletedAt.Before(deletedBefore) {
+			delete(r.products, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, q repository.ListQuery) ([]*repository.Product, error) {
+	list := []*repository.Product{}
+	err := r.Stream(ctx, q, func(p *repository.Product) error {
+		list = append(list, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *fakeRepository) Stream(ctx context.Context, q repository.ListQuery, fn func(p *repository.Product) error) error {
+	r.query = q
+	if r.err != nil {
+		return r.err
+	}
+
+	var ids []int64
+	for id, p := range r.products {
+		if !match(q.Filter, p) {
+			continue
+		}
+		if q.After != nil && id <= q.After.ID {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if q.Limit > 0 && len(ids) > q.Limit {
+		ids = ids[:q.Limit]
+	}
+
+	for _, id := range ids {
+		p := r.products[id]
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) Count(ctx context.Context, f repository.Filter) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var n int64
+	for _, p := range r.products {
+		if match(f, p) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeRepository) InTx(ctx context.Context, readOnly bool, fn func(tx repository.ProductTx) error) error {
+	if r.err != nil {
+		return r.err
+	}
+	tx := &fakeTx{r.clone()}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.products, r.nextID, r.keys = tx.products, tx.nextID, tx.keys
+	return nil
+}
+
+func (tx *fakeTx) Savepoint(ctx context.Context, fn func() error) error {
+	saved := tx.clone()
+	if err := fn(); err != nil {
+		tx.products, tx.nextID, tx.keys = saved.products, saved.nextID, saved.keys
+		return err
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetIdempotencyKey(ctx context.Context, requestID string) (*repository.IdempotencyKey, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	k, ok := r.keys[requestID]
+	if !ok || !k.ExpiresAt.After(time.Now()) {
+		return nil, &repository.IdempotencyKeyNotFoundError{RequestID: requestID}
+	}
+	return &k, nil
+}
+
+func (r *fakeRepository) SaveIdempotencyKey(ctx context.Context, k *repository.IdempotencyKey) error {
+	if r.err != nil {
+		return r.err
+	}
+	if saved, ok := r.keys[k.RequestID]; ok && saved.ExpiresAt.After(time.Now()) {
+		return &repository.StorageError{Kind: repository.KindDuplicate, Op: "failed to save idempotency key", Err: errors.New("duplicate")}
+	}
+	r.keys[k.RequestID] = *k
+	return nil
+}
+
+func (r *fakeRepository) PurgeIdempotencyKeys(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var n int64
+	for requestID, k := range r.keys {
+		if k.ExpiresAt.Before(expiredBefore) {
+			delete(r.keys, requestID)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// match checks if Product is selected by category and deletion of the filter
+func match(f repository.Filter, p repository.Product) bool {
+	return (f.CategoryID == 0 || p.CategoryID == f.CategoryID) && (f.ShowDeleted || p.DeletedAt.IsZero())
+}
+
+// nameMask is update mask of the name field
+func nameMask() *field_mask.FieldMask {
+	return &field_mask.FieldMask{Paths: []string{"name"}}
+}
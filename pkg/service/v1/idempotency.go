@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+const (
+	// idempotencyKeyTTL is period request IDs are kept for, clients are expected to retry within it
+	idempotencyKeyTTL = 24 * time.Hour
+)
+
+// errRequestInProgress is returned if request with the same request ID is saved by concurrent transaction,
+// retry gets the response of that request once it is committed
+var errRequestInProgress = status.Error(codes.Aborted, "request with the same request_id is in progress")
+
+// requestKey identifies write request with client-supplied request ID
+type requestKey struct {
+	requestID string
+	// fingerprint is hash of the method and payload of the request
+	fingerprint string
+}
+
+// newRequestKey returns key of the request, nil if the request has no request ID.
+// Payload is the part of the request which must match for retries.
+func newRequestKey(method string, requestID string, payload proto.Message) (*requestKey, error) {
+	if len(requestID) == 0 {
+		return nil, nil
+	}
+
+	b := proto.NewBuffer([]byte(method + "\n"))
+	b.SetDeterministic(true)
+	if err := b.Marshal(payload); err != nil {
+		return nil, status.Error(codes.Internal, "failed to marshal request-> "+err.Error())
+	}
+	sum := sha256.Sum256(b.Bytes())
+	return &requestKey{requestID: requestID, fingerprint: hex.EncodeToString(sum[:])}, nil
+}
+
+// once runs write fn once per request ID. Response filled by fn is saved with the request ID
+// in the same transaction as the write, so retries get the saved response in res without running fn.
+// It reports if the response is replayed. fn simply runs if key is nil.
+func once(ctx context.Context, tx repository.ProductStore, key *requestKey, res proto.Message, fn func() error) (bool, error) {
+	if key == nil {
+		return false, fn()
+	}
+
+	saved, err := tx.GetIdempotencyKey(ctx, key.requestID)
+	var notFound *repository.IdempotencyKeyNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+	case err != nil:
+		return false, err
+	case saved.Fingerprint != key.fingerprint:
+		return false, status.Errorf(codes.AlreadyExists, "request_id '%s' is used by another request", key.requestID)
+	default:
+		if err := proto.Unmarshal(saved.Response, res); err != nil {
+			return false, status.Error(codes.Internal, "failed to unmarshal saved response-> "+err.Error())
+		}
+		return true, nil
+	}
+
+	if err := fn(); err != nil {
+		return false, err
+	}
+
+	b, err := proto.Marshal(res)
+	if err != nil {
+		return false, status.Error(codes.Internal, "failed to marshal response-> "+err.Error())
+	}
+	err = tx.SaveIdempotencyKey(ctx, &repository.IdempotencyKey{
+		RequestID:   key.requestID,
+		Fingerprint: key.fingerprint,
+		Response:    b,
+		ExpiresAt:   time.Now().Add(idempotencyKeyTTL),
+	})
+	if err != nil && errorKind(err) == repository.KindDuplicate {
+		return false, errRequestInProgress
+	}
+	return false, err
+}
+
+// writeOnce runs write fn once per request ID of the key in transaction, see once.
+// fn runs without transaction if key is nil.
+func (s *productServiceServer) writeOnce(ctx context.Context, key *requestKey, res proto.Message,
+	fn func(store repository.ProductStore) error) error {
+	if key == nil {
+		return fn(s.repo)
+	}
+
+	return s.repo.InTx(ctx, false, func(tx repository.ProductTx) error {
+		_, err := once(ctx, tx, key, res, func() error {
+			return fn(tx)
+		})
+		return err
+	})
+}
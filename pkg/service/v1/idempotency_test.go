@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_productServiceServer_Create_requestID(t *testing.T) {
+	ctx := context.Background()
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	repo := newFakeRepository()
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil).(*productServiceServer)
+
+	req := &v1.CreateRequest{Api: "v1", Product: &v1.ProductProto{Name: "potato", Date: date}, RequestId: "r1"}
+	first, err := s.Create(ctx, req)
+	if err != nil {
+		t.Fatalf("productServiceServer.Create() error = %v", err)
+	}
+
+	// retry gets the same response without creating another Product
+	retry, err := s.Create(ctx, proto.Clone(req).(*v1.CreateRequest))
+	if err != nil {
+		t.Fatalf("productServiceServer.Create() retry error = %v", err)
+	}
+	if !proto.Equal(retry, first) {
+		t.Errorf("productServiceServer.Create() retry = %v, want %v", retry, first)
+	}
+	if len(repo.products) != 1 {
+		t.Errorf("productServiceServer.Create() created %d Products, want 1", len(repo.products))
+	}
+	if n := len(s.changes.events); n != 1 {
+		t.Errorf("productServiceServer.Create() announced %d changes, want 1", n)
+	}
+
+	// the same request ID with another payload is rejected
+	other := &v1.CreateRequest{Api: "v1", Product: &v1.ProductProto{Name: "tomato", Date: date}, RequestId: "r1"}
+	if _, err := s.Create(ctx, other); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("productServiceServer.Create() conflicting payload error = %v, want AlreadyExists", err)
+	}
+
+	// expired request ID can be reused
+	k := repo.keys["r1"]
+	k.ExpiresAt = time.Now().Add(-time.Second)
+	repo.keys["r1"] = k
+	res, err := s.Create(ctx, other)
+	if err != nil {
+		t.Fatalf("productServiceServer.Create() after expiration error = %v", err)
+	}
+	if res.Id != 2 {
+		t.Errorf("productServiceServer.Create() after expiration ID = %d, want 2", res.Id)
+	}
+
+	// requests without request ID are not deduplicated
+	req.RequestId = ""
+	for i := 0; i < 2; i++ {
+		if _, err := s.Create(ctx, req); err != nil {
+			t.Fatalf("productServiceServer.Create() without request ID error = %v", err)
+		}
+	}
+	if len(repo.products) != 4 {
+		t.Errorf("productServiceServer.Create() created %d Products, want 4", len(repo.products))
+	}
+}
+
+func Test_productServiceServer_Update_requestID(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository(repository.Product{ID: 1, Name: "potato", Revision: 1})
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	req := &v1.UpdateRequest{
+		Api:              "v1",
+		Product:          &v1.ProductProto{Id: 1, Name: "tomato"},
+		UpdateMask:       nameMask(),
+		ExpectedRevision: 1,
+		RequestId:        "r1",
+	}
+	for i := 0; i < 2; i++ {
+		// retry doesn't fail on revision which was bumped by the first attempt
+		if _, err := s.Update(ctx, req); err != nil {
+			t.Fatalf("productServiceServer.Update() attempt %d error = %v", i+1, err)
+		}
+	}
+	if got := repo.products[1].Revision; got != 2 {
+		t.Errorf("productServiceServer.Update() revision = %d, want 2", got)
+	}
+
+	// request ID of Update can't be reused by Create
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	create := &v1.CreateRequest{Api: "v1", Product: &v1.ProductProto{Name: "tomato", Date: date}, RequestId: "r1"}
+	if _, err := s.Create(ctx, create); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("productServiceServer.Create() with request ID of Update error = %v, want AlreadyExists", err)
+	}
+}
+
+func Test_productServiceServer_BatchCreate_requestID(t *testing.T) {
+	ctx := context.Background()
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	repo := newFakeRepository()
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	item := func(name string, requestID string) *v1.CreateRequest {
+		return &v1.CreateRequest{Api: "v1", Product: &v1.ProductProto{Name: name, Date: date}, RequestId: requestID}
+	}
+	first, err := s.BatchCreate(ctx, &v1.BatchCreateRequest{Api: "v1", Requests: []*v1.CreateRequest{
+		item("potato", "r1"),
+		// repeated item of the same batch is created once
+		item("potato", "r1"),
+		item("tomato", ""),
+	}})
+	if err != nil {
+		t.Fatalf("productServiceServer.BatchCreate() error = %v", err)
+	}
+	if first.Responses[0].Id != 1 || first.Responses[1].Id != 1 || first.Responses[2].Id != 2 {
+		t.Errorf("productServiceServer.BatchCreate() responses = %v, want IDs 1, 1, 2", first.Responses)
+	}
+
+	retry, err := s.BatchCreate(ctx, &v1.BatchCreateRequest{Api: "v1", BestEffort: true, Requests: []*v1.CreateRequest{
+		item("potato", "r1"),
+		item("cucumber", "r1"),
+	}})
+	if err != nil {
+		t.Fatalf("productServiceServer.BatchCreate() retry error = %v", err)
+	}
+	if retry.Responses[0].Id != 1 {
+		t.Errorf("productServiceServer.BatchCreate() retry ID = %d, want 1", retry.Responses[0].Id)
+	}
+	if code := codes.Code(retry.Statuses[1].Code); code != codes.AlreadyExists {
+		t.Errorf("productServiceServer.BatchCreate() conflicting item code = %v, want AlreadyExists", code)
+	}
+	if len(repo.products) != 2 {
+		t.Errorf("productServiceServer.BatchCreate() created %d Products, want 2", len(repo.products))
+	}
+}
+
+// racingRepository doesn't see idempotency keys saved by concurrent transaction until it saves its own
+type racingRepository struct {
+	*fakeRepository
+}
+
+func (r racingRepository) GetIdempotencyKey(ctx context.Context, requestID string) (*repository.IdempotencyKey, error) {
+	return nil, &repository.IdempotencyKeyNotFoundError{RequestID: requestID}
+}
+
+func Test_once_inProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	repo.keys["r1"] = repository.IdempotencyKey{RequestID: "r1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	key, err := newRequestKey("Create", "r1", &v1.ProductProto{Name: "potato"})
+	if err != nil {
+		t.Fatalf("newRequestKey() error = %v", err)
+	}
+	_, err = once(ctx, racingRepository{repo}, key, &v1.CreateResponse{}, func() error { return nil })
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("once() error = %v, want Aborted", err)
+	}
+}
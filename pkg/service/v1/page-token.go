@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	// pageTokenMACSize is number of bytes of the HMAC appended to the page token
+	pageTokenMACSize = 16
+)
+
+// pageToken is the keyset cursor handed out to clients as next_page_token.
+// It is opaque for clients: the payload is signed with the server key,
+// so it can't be forged or modified to skip into arbitrary positions.
+type pageToken struct {
+	// Query is fingerprint of filter and sort order the token was issued for
+	Query string `json:"q,omitempty"`
+	// LastID is ID of the last Product returned on the previous page
+	LastID int64 `json:"id"`
+	// LastValue is value of the sort column of the last Product returned on the previous page
+	LastValue string `json:"v,omitempty"`
+	// Offset is number of results returned on the previous pages of Search,
+	// results ranked by relevance have no stable sort key to continue after
+	Offset int `json:"o,omitempty"`
+}
+
+// encodePageToken serializes and signs page token
+func encodePageToken(key []byte, t pageToken) string {
+	payload, err := json.Marshal(t)
+	if err != nil {
+		// pageToken consists of plain fields only
+		panic("failed to marshal page token: " + err.Error())
+	}
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, pageTokenMAC(key, payload)...))
+}
+
+// decodePageToken verifies signature and deserializes page token
+func decodePageToken(key []byte, s string) (pageToken, error) {
+	var t pageToken
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, errors.New("malformed page token")
+	}
+	if len(b) <= pageTokenMACSize {
+		return t, errors.New("malformed page token")
+	}
+
+	payload, mac := b[:len(b)-pageTokenMACSize], b[len(b)-pageTokenMACSize:]
+	if !hmac.Equal(mac, pageTokenMAC(key, payload)) {
+		return t, errors.New("page token signature mismatch")
+	}
+
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return t, errors.New("malformed page token")
+	}
+
+	return t, nil
+}
+
+// pageTokenMAC returns truncated HMAC-SHA256 of the page token payload
+func pageTokenMAC(key []byte, payload []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(payload)
+	return h.Sum(nil)[:pageTokenMACSize]
+}
@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+const (
+	// maxBatchSize is upper limit of number of requests in one batch
+	maxBatchSize = 1000
+)
+
+// checkBatch checks API versions and size of the batch
+func (s *productServiceServer) checkBatch(api string, n int, itemAPI func(i int) string) error {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(api); err != nil {
+		return err
+	}
+	if n > maxBatchSize {
+		return fieldError("requests", fmt.Errorf("batch contains %d requests, but at most %d are allowed", n, maxBatchSize))
+	}
+	for i := 0; i < n; i++ {
+		if err := checkAPI(itemAPI(i)); err != nil {
+			return status.Errorf(codes.Unimplemented, "requests[%d]: %s", i, status.Convert(err).Message())
+		}
+	}
+	return nil
+}
+
+// runBatch runs fn for every request of the batch inside one transaction.
+// In all-or-nothing mode the first failed request rolls back the whole batch.
+// In best effort mode every writing request is isolated by savepoint,
+// so its failure is reported in its status and rolls back only its own changes.
+func (s *productServiceServer) runBatch(ctx context.Context, readOnly bool, n int, bestEffort bool,
+	fn func(tx repository.ProductTx, i int) error) ([]*rpcstatus.Status, error) {
+	statuses := make([]*rpcstatus.Status, n)
+	err := s.repo.InTx(ctx, readOnly, func(tx repository.ProductTx) error {
+		for i := 0; i < n; i++ {
+			var err error
+			if bestEffort && !readOnly {
+				var itemErr error
+				err = tx.Savepoint(ctx, func() error {
+					itemErr = fn(tx, i)
+					return itemErr
+				})
+				if err != itemErr {
+					// savepoint itself failed, transaction can't be continued
+					return err
+				}
+			} else {
+				err = fn(tx, i)
+			}
+
+			if err != nil && !bestEffort {
+				// details such as field violations are kept
+				st := status.Convert(repositoryError(ctx, err)).Proto()
+				st.Message = fmt.Sprintf("requests[%d]: %s", i, st.Message)
+				return status.ErrorProto(st)
+			}
+			if err == nil {
+				statuses[i] = status.New(codes.OK, "").Proto()
+				continue
+			}
+			statuses[i] = status.Convert(repositoryError(ctx, err)).Proto()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	return statuses, nil
+}
+
+// publish appends changes of products written by successful requests of the batch to the change log
+func (s *productServiceServer) publish(ctx context.Context, t v1.ChangeType, products []*repository.Product) {
+	for _, p := range products {
+		if p != nil {
+			s.notify(ctx, t, p)
+		}
+	}
+}
+
+// BatchCreate creates several products in one transaction
+func (s *productServiceServer) BatchCreate(ctx context.Context, req *v1.BatchCreateRequest) (*v1.BatchCreateResponse, error) {
+	if err := s.checkBatch(req.Api, len(req.Requests), func(i int) string { return req.Requests[i].GetApi() }); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*v1.CreateResponse, len(req.Requests))
+	created := make([]*repository.Product, len(req.Requests))
+	statuses, err := s.runBatch(ctx, false, len(req.Requests), req.BestEffort, func(tx repository.ProductTx, i int) error {
+		responses[i] = &v1.CreateResponse{}
+		if err := validateCreate(fmt.Sprintf("requests[%d].", i), req.Requests[i]); err != nil {
+			return err
+		}
+		p, err := productFromProto(req.Requests[i].Product, true)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		key, err := newRequestKey("Create", req.Requests[i].RequestId, req.Requests[i].Product)
+		if err != nil {
+			return err
+		}
+
+		// response and product are kept only if the request ID is saved too
+		res := &v1.CreateResponse{}
+		var written *repository.Product
+		_, err = once(ctx, tx, key, res, func() error {
+			var err error
+			if written, err = tx.Create(ctx, p); err != nil {
+				return err
+			}
+			res.Api, res.Id, res.Revision = apiVersion, written.ID, written.Revision
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		responses[i] = res
+		created[i] = written
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, v1.ChangeType_CREATED, created)
+
+	return &v1.BatchCreateResponse{
+		Api:       apiVersion,
+		Responses: responses,
+		Statuses:  statuses,
+	}, nil
+}
+
+// BatchRead reads several products in one transaction
+func (s *productServiceServer) BatchRead(ctx context.Context, req *v1.BatchReadRequest) (*v1.BatchReadResponse, error) {
+	if err := s.checkBatch(req.Api, len(req.Requests), func(i int) string { return req.Requests[i].GetApi() }); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*v1.ReadResponse, len(req.Requests))
+	statuses, err := s.runBatch(ctx, true, len(req.Requests), req.BestEffort, func(tx repository.ProductTx, i int) error {
+		responses[i] = &v1.ReadResponse{}
+		if err := validateRead(fmt.Sprintf("requests[%d].", i), req.Requests[i]); err != nil {
+			return err
+		}
+		p, err := tx.Get(ctx, req.Requests[i].Id)
+		if err != nil {
+			return err
+		}
+		td, err := toProto(p)
+		if err != nil {
+			return err
+		}
+		responses[i] = &v1.ReadResponse{
+			Api:     apiVersion,
+			Product: td,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.BatchReadResponse{
+		Api:       apiVersion,
+		Responses: responses,
+		Statuses:  statuses,
+	}, nil
+}
+
+// BatchUpdate updates several products in one transaction
+func (s *productServiceServer) BatchUpdate(ctx context.Context, req *v1.BatchUpdateRequest) (*v1.BatchUpdateResponse, error) {
+	if err := s.checkBatch(req.Api, len(req.Requests), func(i int) string { return req.Requests[i].GetApi() }); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*v1.UpdateResponse, len(req.Requests))
+	updated := make([]*repository.Product, len(req.Requests))
+	statuses, err := s.runBatch(ctx, false, len(req.Requests), req.BestEffort, func(tx repository.ProductTx, i int) error {
+		responses[i] = &v1.UpdateResponse{}
+		if err := validateUpdate(fmt.Sprintf("requests[%d].", i), req.Requests[i]); err != nil {
+			return err
+		}
+		p, fields, err := updateProduct(req.Requests[i])
+		if err != nil {
+			return err
+		}
+		key, err := newRequestKey("Update", req.Requests[i].RequestId, updatePayload(req.Requests[i]))
+		if err != nil {
+			return err
+		}
+
+		// response and product are kept only if the request ID is saved too
+		res := &v1.UpdateResponse{}
+		var written *repository.Product
+		_, err = once(ctx, tx, key, res, func() error {
+			var err error
+			if written, err = tx.Update(ctx, p, fields, req.Requests[i].ExpectedRevision); err != nil {
+				return err
+			}
+			res.Api, res.Updated = apiVersion, 1
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		responses[i] = res
+		updated[i] = written
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(ctx, v1.ChangeType_UPDATED, updated)
+
+	return &v1.BatchUpdateResponse{
+		Api:       apiVersion,
+		Responses: responses,
+		Statuses:  statuses,
+	}, nil
+}
+
+// BatchDelete deletes several products in one transaction
+func (s *productServiceServer) BatchDelete(ctx context.Context, req *v1.BatchDeleteRequest) (*v1.BatchDeleteResponse, error) {
+	if err := s.checkBatch(req.Api, len(req.Requests), func(i int) string { return req.Requests[i].GetApi() }); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*v1.DeleteResponse, len(req.Requests))
+	// revisions of the deleted products, 0 if the product isn't deleted
+	revisions := make([]int64, len(req.Requests))
+	statuses, err := s.runBatch(ctx, false, len(req.Requests), req.BestEffort, func(tx repository.ProductTx, i int) error {
+		responses[i] = &v1.DeleteResponse{}
+		if err := validateDelete(fmt.Sprintf("requests[%d].", i), req.Requests[i]); err != nil {
+			return err
+		}
+		revision, err := tx.Delete(ctx, req.Requests[i].Id, req.Requests[i].ExpectedRevision)
+		if err != nil {
+			return err
+		}
+		responses[i] = &v1.DeleteResponse{
+			Api:     apiVersion,
+			Deleted: 1,
+		}
+		revisions[i] = revision
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, revision := range revisions {
+		if revision != 0 {
+			s.notifyDeleted(ctx, req.Requests[i].Id, revision)
+		}
+	}
+
+	return &v1.BatchDeleteResponse{
+		Api:       apiVersion,
+		Responses: responses,
+		Statuses:  statuses,
+	}, nil
+}
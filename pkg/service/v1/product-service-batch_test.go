@@ -0,0 +1,324 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_productServiceServer_BatchCreate(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	invalidDate := &timestamp.Timestamp{Seconds: 1, Nanos: -1}
+	_, dateErr := ptypes.Timestamp(invalidDate)
+
+	tests := []struct {
+		name     string
+		repo     *fakeRepository
+		req      *v1.BatchCreateRequest
+		want     *v1.BatchCreateResponse
+		wantIDs  []int64
+		wantCode codes.Code
+	}{
+		{
+			name: "OK",
+			repo: newFakeRepository(),
+			req: &v1.BatchCreateRequest{
+				Api: "v1",
+				Requests: []*v1.CreateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 1", Date: date}},
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 2", Date: date}},
+				},
+			},
+			want: &v1.BatchCreateResponse{
+				Api: "v1",
+				Responses: []*v1.CreateResponse{
+					{Api: "v1", Id: 1, Revision: 1},
+					{Api: "v1", Id: 2, Revision: 1},
+				},
+				Statuses: []*rpcstatus.Status{
+					status.New(codes.OK, "").Proto(),
+					status.New(codes.OK, "").Proto(),
+				},
+			},
+			wantIDs: []int64{1, 2},
+		},
+		{
+			name: "All or nothing",
+			repo: newFakeRepository(),
+			req: &v1.BatchCreateRequest{
+				Api: "v1",
+				Requests: []*v1.CreateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 1", Date: date}},
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 2", Date: invalidDate}},
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Best effort",
+			repo: newFakeRepository(),
+			req: &v1.BatchCreateRequest{
+				Api: "v1",
+				Requests: []*v1.CreateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 1", Date: invalidDate}},
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 2", Date: date}},
+				},
+				BestEffort: true,
+			},
+			want: &v1.BatchCreateResponse{
+				Api: "v1",
+				Responses: []*v1.CreateResponse{
+					{},
+					{Api: "v1", Id: 1, Revision: 1},
+				},
+				Statuses: []*rpcstatus.Status{
+					status.Convert(fieldError("requests[0].product.date", fmt.Errorf("is invalid timestamp: %v", dateErr))).Proto(),
+					status.New(codes.OK, "").Proto(),
+				},
+			},
+			wantIDs: []int64{1},
+		},
+		{
+			name: "Transaction failed",
+			repo: &fakeRepository{err: errors.New("failed to begin transaction-> BEGIN failed")},
+			req: &v1.BatchCreateRequest{
+				Api: "v1",
+				Requests: []*v1.CreateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Name: "name 1", Date: date}},
+				},
+			},
+			wantCode: codes.Unknown,
+		},
+		{
+			name: "Unsupported item API",
+			repo: newFakeRepository(),
+			req: &v1.BatchCreateRequest{
+				Api: "v1",
+				Requests: []*v1.CreateRequest{
+					{Api: "v1000", Product: &v1.ProductProto{Name: "name 1", Date: date}},
+				},
+			},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name: "Unsupported API",
+			repo: newFakeRepository(),
+			req: &v1.BatchCreateRequest{
+				Api: "v1000",
+			},
+			wantCode: codes.Unimplemented,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewProductServiceServer(tt.repo, nil, []byte("secret"), nil)
+			got, err := s.BatchCreate(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.BatchCreate() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.BatchCreate() = %v, want %v", got, tt.want)
+			}
+			var ids []int64
+			for id := range tt.repo.products {
+				ids = append(ids, id)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Errorf("productServiceServer.BatchCreate() stored %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func Test_productServiceServer_BatchRead(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	repo := newFakeRepository(repository.Product{ID: 1, Name: "name", Date: tm, Revision: 1})
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	tests := []struct {
+		name     string
+		req      *v1.BatchReadRequest
+		want     *v1.BatchReadResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "All or nothing",
+			req: &v1.BatchReadRequest{
+				Api:      "v1",
+				Requests: []*v1.ReadRequest{{Api: "v1", Id: 1}, {Api: "v1", Id: 2}},
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Best effort",
+			req: &v1.BatchReadRequest{
+				Api:        "v1",
+				Requests:   []*v1.ReadRequest{{Api: "v1", Id: 1}, {Api: "v1", Id: 2}},
+				BestEffort: true,
+			},
+			want: &v1.BatchReadResponse{
+				Api: "v1",
+				Responses: []*v1.ReadResponse{
+					{Api: "v1", Product: &v1.ProductProto{Id: 1, Name: "name", Date: date, Revision: 1}},
+					{},
+				},
+				Statuses: []*rpcstatus.Status{
+					status.New(codes.OK, "").Proto(),
+					status.New(codes.NotFound, "Product with ID='2' is not found").Proto(),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.BatchRead(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.BatchRead() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.BatchRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_productServiceServer_BatchUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		req      *v1.BatchUpdateRequest
+		want     *v1.BatchUpdateResponse
+		wantName string
+		wantCode codes.Code
+	}{
+		{
+			name: "All or nothing",
+			req: &v1.BatchUpdateRequest{
+				Api: "v1",
+				Requests: []*v1.UpdateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Id: 1, Name: "new name"}, UpdateMask: nameMask()},
+					{Api: "v1", Product: &v1.ProductProto{Id: 2, Name: "new name"}, UpdateMask: nameMask()},
+				},
+			},
+			wantName: "name",
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Best effort",
+			req: &v1.BatchUpdateRequest{
+				Api: "v1",
+				Requests: []*v1.UpdateRequest{
+					{Api: "v1", Product: &v1.ProductProto{Id: 1, Name: "new name"}, UpdateMask: nameMask()},
+					{Api: "v1", Product: &v1.ProductProto{Id: 2, Name: "new name"}, UpdateMask: nameMask()},
+				},
+				BestEffort: true,
+			},
+			want: &v1.BatchUpdateResponse{
+				Api: "v1",
+				Responses: []*v1.UpdateResponse{
+					{Api: "v1", Updated: 1},
+					{},
+				},
+				Statuses: []*rpcstatus.Status{
+					status.New(codes.OK, "").Proto(),
+					status.New(codes.NotFound, "Product with ID='2' is not found").Proto(),
+				},
+			},
+			wantName: "new name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository(repository.Product{ID: 1, Name: "name", Revision: 1})
+			s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+			got, err := s.BatchUpdate(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.BatchUpdate() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.BatchUpdate() = %v, want %v", got, tt.want)
+			}
+			if p, _ := repo.Get(ctx, 1); p.Name != tt.wantName {
+				t.Errorf("productServiceServer.BatchUpdate() stored name %q, want %q", p.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func Test_productServiceServer_BatchDelete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		req         *v1.BatchDeleteRequest
+		want        *v1.BatchDeleteResponse
+		wantDeleted bool
+		wantCode    codes.Code
+	}{
+		{
+			name: "All or nothing",
+			req: &v1.BatchDeleteRequest{
+				Api:      "v1",
+				Requests: []*v1.DeleteRequest{{Api: "v1", Id: 1}, {Api: "v1", Id: 2}},
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Best effort",
+			req: &v1.BatchDeleteRequest{
+				Api:        "v1",
+				Requests:   []*v1.DeleteRequest{{Api: "v1", Id: 1}, {Api: "v1", Id: 1, ExpectedRevision: 1}},
+				BestEffort: true,
+			},
+			want: &v1.BatchDeleteResponse{
+				Api: "v1",
+				Responses: []*v1.DeleteResponse{
+					{Api: "v1", Deleted: 1},
+					{},
+				},
+				Statuses: []*rpcstatus.Status{
+					status.New(codes.OK, "").Proto(),
+					status.New(codes.NotFound, "Product with ID='1' is not found").Proto(),
+				},
+			},
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository(repository.Product{ID: 1, Name: "name", Revision: 1})
+			s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+			got, err := s.BatchDelete(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.BatchDelete() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.BatchDelete() = %v, want %v", got, tt.want)
+			}
+			if _, err := repo.Get(ctx, 1); (err != nil) != tt.wantDeleted {
+				t.Errorf("productServiceServer.BatchDelete() deleted = %v, want %v", err != nil, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+const (
+	// maxSnippetLength is length of highlighted snippets of product fields in characters
+	maxSnippetLength = 160
+)
+
+// searchFingerprint identifies search terms, so that page token can't be used to continue another search.
+// Order of terms doesn't change results, so it doesn't change fingerprint either.
+func searchFingerprint(terms []string) string {
+	sorted := append([]string(nil), terms...)
+	sort.Strings(sorted)
+	h := sha256.Sum256([]byte("search|" + strings.Join(sorted, " ")))
+	return base64.RawURLEncoding.EncodeToString(h[:8])
+}
+
+// highlights returns snippets of Product fields and name of its Category containing the terms
+func highlights(p *repository.Product, category string, terms []string) []*v1.Highlight {
+	var list []*v1.Highlight
+	for _, f := range []struct {
+		name string
+		text string
+	}{
+		{name: "name", text: p.Name},
+		{name: "description", text: p.Description},
+		{name: "category", text: category},
+	} {
+		if snippet := search.Snippet(f.text, terms, maxSnippetLength); len(snippet) > 0 {
+			list = append(list, &v1.Highlight{Field: f.name, Snippet: snippet})
+		}
+	}
+	return list
+}
+
+// indexProduct adds or replaces Product in search index. Failure is logged only, because Product
+// is written already, it is indexed again when it is written next time or the index is rebuilt.
+func (s *productServiceServer) indexProduct(ctx context.Context, p *repository.Product) {
+	if s.index == nil {
+		return
+	}
+	if err := s.index.Index(ctx, p); err != nil {
+		ctxzap.Extract(ctx).Error("failed to index Product", zap.Int64("id", p.ID), zap.Error(err))
+	}
+}
+
+// categoryName returns name of the Product Category for highlighting, names already read by the search
+// are cached in names. Category deleted in the meantime has no name.
+func (s *productServiceServer) categoryName(ctx context.Context, id int64, names map[int64]string) (string, error) {
+	if s.categories == nil || id == 0 {
+		return "", nil
+	}
+	if name, ok := names[id]; ok {
+		return name, nil
+	}
+
+	c, err := s.categories.GetCategory(ctx, id)
+	var notFound *repository.CategoryNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		names[id] = ""
+	case err != nil:
+		return "", err
+	default:
+		names[id] = c.Name
+	}
+	return names[id], nil
+}
+
+// unindexProduct removes Product deleted at the revision from search index, failure is logged only
+func (s *productServiceServer) unindexProduct(ctx context.Context, id int64, revision int64) {
+	if s.index == nil {
+		return
+	}
+	if err := s.index.Remove(ctx, id, revision); err != nil {
+		ctxzap.Extract(ctx).Error("failed to remove Product from index", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// Search finds products by text in order of relevance
+func (s *productServiceServer) Search(ctx context.Context, req *v1.SearchRequest) (*v1.SearchResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	if s.index == nil {
+		return nil, status.Error(codes.Unimplemented, "search is not enabled on this server")
+	}
+
+	if err := validateSearch(req); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	terms := search.Terms(req.Query)
+	query := searchFingerprint(terms)
+
+	// continue after the results of the previous pages
+	offset := 0
+	if len(req.PageToken) > 0 {
+		token, err := decodePageToken(s.pageTokenKey, req.PageToken)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token-> "+err.Error())
+		}
+		if token.Query != query {
+			return nil, status.Error(codes.InvalidArgument, "page_token doesn't match query of the request")
+		}
+		offset = token.Offset
+	}
+
+	found, err := s.index.Search(ctx, search.Query{Terms: terms, Offset: offset, Limit: pageSize})
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	results := make([]*v1.SearchResult, 0, len(found.Hits))
+	names := map[int64]string{}
+	for _, h := range found.Hits {
+		p, err := s.repo.Get(ctx, h.ID)
+		var notFound *repository.NotFoundError
+		if errors.As(err, &notFound) {
+			// Product is deleted after the index was searched
+			continue
+		}
+		if err != nil {
+			return nil, repositoryError(ctx, err)
+		}
+
+		category, err := s.categoryName(ctx, p.CategoryID, names)
+		if err != nil {
+			return nil, repositoryError(ctx, err)
+		}
+
+		td, err := toProto(p)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &v1.SearchResult{
+			Product:    td,
+			Score:      h.Score,
+			Highlights: highlights(p, category, terms),
+		})
+	}
+
+	var next string
+	if n := offset + len(found.Hits); len(found.Hits) > 0 && int64(n) < found.Total {
+		next = encodePageToken(s.pageTokenKey, pageToken{Query: query, Offset: n})
+	}
+
+	return &v1.SearchResponse{
+		Api:           apiVersion,
+		Results:       results,
+		NextPageToken: next,
+		TotalSize:     found.Total,
+	}, nil
+}
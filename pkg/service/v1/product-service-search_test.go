@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	store "github.com/MartyKuentzel/projectX/pkg/repository/memory"
+	"github.com/MartyKuentzel/projectX/pkg/search/memory"
+)
+
+// resultIDs returns IDs of found products in order
+func resultIDs(res *v1.SearchResponse) []int64 {
+	var ids []int64
+	for _, r := range res.Results {
+		ids = append(ids, r.Product.Id)
+	}
+	return ids
+}
+
+func Test_productServiceServer_Search(t *testing.T) {
+	ctx := context.Background()
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	// Products have no Category, so the index doesn't read Categories
+	s := NewProductServiceServer(newFakeRepository(), nil, []byte("secret"), memory.NewIndex(nil))
+
+	for _, p := range []*v1.ProductProto{
+		{Name: "Potato", Description: "Organic potato from the farm", Date: date},
+		{Name: "Tomato", Description: "Goes well with potato", Date: date},
+		{Name: "Onion", Date: date},
+	} {
+		if _, err := s.Create(ctx, &v1.CreateRequest{Api: "v1", Product: p}); err != nil {
+			t.Fatalf("productServiceServer.Create() error = %v", err)
+		}
+	}
+
+	// created Products are indexed
+	res, err := s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "organic potato", PageSize: 1})
+	if err != nil {
+		t.Fatalf("productServiceServer.Search() error = %v", err)
+	}
+	if !reflect.DeepEqual(resultIDs(res), []int64{1}) || res.TotalSize != 2 || len(res.NextPageToken) == 0 {
+		t.Errorf("productServiceServer.Search() = %v, total %d, want [1], total 2 and next page", resultIDs(res), res.TotalSize)
+	}
+	wantHighlights := []*v1.Highlight{
+		{Field: "name", Snippet: "<em>Potato</em>"},
+		{Field: "description", Snippet: "<em>Organic</em> <em>potato</em> from the farm"},
+	}
+	if got := res.Results[0].Highlights; !reflect.DeepEqual(got, wantHighlights) {
+		t.Errorf("productServiceServer.Search() highlights = %v, want %v", got, wantHighlights)
+	}
+
+	next, err := s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "Potato, organic!", PageSize: 1, PageToken: res.NextPageToken})
+	if err != nil {
+		t.Fatalf("productServiceServer.Search() next page error = %v", err)
+	}
+	if !reflect.DeepEqual(resultIDs(next), []int64{2}) || len(next.NextPageToken) != 0 {
+		t.Errorf("productServiceServer.Search() next page = %v, %q, want [2] and no next page", resultIDs(next), next.NextPageToken)
+	}
+
+	_, err = s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "onion", PageToken: res.NextPageToken})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("productServiceServer.Search() with page token of another query error = %v, want InvalidArgument", err)
+	}
+
+	// deleted Products are removed from the index, updated Products are indexed again
+	if _, err := s.Delete(ctx, &v1.DeleteRequest{Api: "v1", Id: 1}); err != nil {
+		t.Fatalf("productServiceServer.Delete() error = %v", err)
+	}
+	if _, err := s.Update(ctx, &v1.UpdateRequest{Api: "v1", Product: &v1.ProductProto{Id: 3, Name: "Potato onion"}, UpdateMask: nameMask()}); err != nil {
+		t.Fatalf("productServiceServer.Update() error = %v", err)
+	}
+	res, err = s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "potato"})
+	if err != nil || !reflect.DeepEqual(resultIDs(res), []int64{3, 2}) {
+		t.Errorf("productServiceServer.Search() = %v, %v, want [3 2]", resultIDs(res), err)
+	}
+
+	if _, err := s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: " ! "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("productServiceServer.Search() without words error = %v, want InvalidArgument", err)
+	}
+}
+
+func Test_productServiceServer_Search_category(t *testing.T) {
+	ctx := context.Background()
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	r, err := store.NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	organic, err := r.CreateCategory(ctx, &repository.Category{Name: "Organic vegetables"})
+	if err != nil {
+		t.Fatalf("failed to create Category: %v", err)
+	}
+	index := memory.NewIndex(r)
+	s := NewProductServiceServer(r, r, []byte("secret"), index)
+	categories := NewCategoryServiceServer(r, r, index)
+
+	for _, p := range []*v1.ProductProto{
+		{Name: "Potato", CategoryId: organic.ID, Date: date},
+		{Name: "Organic tomato", Date: date},
+	} {
+		if _, err := s.Create(ctx, &v1.CreateRequest{Api: "v1", Product: p}); err != nil {
+			t.Fatalf("productServiceServer.Create() error = %v", err)
+		}
+	}
+
+	res, err := s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "organic"})
+	if err != nil || !reflect.DeepEqual(resultIDs(res), []int64{2, 1}) {
+		t.Fatalf("productServiceServer.Search() = %v, %v, want [2 1]", resultIDs(res), err)
+	}
+	wantHighlights := []*v1.Highlight{{Field: "category", Snippet: "<em>Organic</em> vegetables"}}
+	if got := res.Results[1].Highlights; !reflect.DeepEqual(got, wantHighlights) {
+		t.Errorf("productServiceServer.Search() highlights = %v, want %v", got, wantHighlights)
+	}
+
+	// Products of renamed Category are found by its new name only
+	if _, err := categories.UpdateCategory(ctx, &v1.UpdateCategoryRequest{Api: "v1",
+		Category: &v1.Category{Id: organic.ID, Name: "Bio vegetables"}}); err != nil {
+		t.Fatalf("categoryServiceServer.UpdateCategory() error = %v", err)
+	}
+	res, err = s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "organic"})
+	if err != nil || !reflect.DeepEqual(resultIDs(res), []int64{2}) {
+		t.Errorf("productServiceServer.Search() = %v, %v, want [2]", resultIDs(res), err)
+	}
+	res, err = s.Search(ctx, &v1.SearchRequest{Api: "v1", Query: "bio"})
+	if err != nil || !reflect.DeepEqual(resultIDs(res), []int64{1}) {
+		t.Fatalf("productServiceServer.Search() = %v, %v, want [1]", resultIDs(res), err)
+	}
+	wantHighlights = []*v1.Highlight{{Field: "category", Snippet: "<em>Bio</em> vegetables"}}
+	if got := res.Results[0].Highlights; !reflect.DeepEqual(got, wantHighlights) {
+		t.Errorf("productServiceServer.Search() highlights = %v, want %v", got, wantHighlights)
+	}
+}
+
+func Test_productServiceServer_Search_disabled(t *testing.T) {
+	s := NewProductServiceServer(newFakeRepository(), nil, []byte("secret"), nil)
+	_, err := s.Search(context.Background(), &v1.SearchRequest{Api: "v1", Query: "potato"})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("productServiceServer.Search() error = %v, want Unimplemented", err)
+	}
+}
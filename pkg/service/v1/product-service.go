@@ -2,35 +2,62 @@ package v1
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
-	"time"
+	"crypto/rand"
 
-	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
-	"github.com/MartyKuentzel/projectX/pkg/logger"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
 )
 
 const (
 	// apiVersion is version of API is provided by server
 	apiVersion = "v1"
+
+	// defaultPageSize is number of products returned by ReadAll if client doesn't specify page size
+	defaultPageSize = 100
+	// maxPageSize is upper limit of page size, larger values are coerced to it
+	maxPageSize = 1000
 )
 
 // productServiceServer is implementation of v1.ProductServiceServer proto interface
 type productServiceServer struct {
-	db *sql.DB
+	repo repository.ProductRepository
+
+	// pageTokenKey is secret to sign page tokens
+	pageTokenKey []byte
+
+	// changes is log of Product changes streamed to watchers
+	changes *changeLog
+
+	// index finds Products by text, it is updated on every change, Search is unimplemented if it is nil
+	index search.Index
+
+	// categories resolve Category names highlighted in search results, they aren't highlighted if it is nil
+	categories repository.CategoryRepository
 }
 
 // NewProductServiceServer creates Product service
-func NewProductServiceServer(db *sql.DB) v1.ProductServiceServer {
-	return &productServiceServer{db: db}
+// categories resolve Category names highlighted by Search, they aren't highlighted if it is nil,
+// pageTokenKey is secret to sign page tokens, random key is generated if it is empty
+// (page tokens don't survive server restart in this case),
+// index is search index of Products, Search is unimplemented if it is nil
+func NewProductServiceServer(repo repository.ProductRepository, categories repository.CategoryRepository, pageTokenKey []byte,
+	index search.Index) v1.ProductServiceServer {
+	if len(pageTokenKey) == 0 {
+		pageTokenKey = make([]byte, 32)
+		if _, err := rand.Read(pageTokenKey); err != nil {
+			panic("failed to generate page token key: " + err.Error())
+		}
+	}
+	return &productServiceServer{repo: repo, pageTokenKey: pageTokenKey, changes: newChangeLog(changeLogSize), index: index,
+		categories: categories}
 }
 
 // checkAPI checks if the API version requested by client is supported by server
-func (s *productServiceServer) checkAPI(api string) error {
+func checkAPI(api string) error {
 	// API version is "" means use current version of the service
 	if len(api) > 0 {
 		if apiVersion != api {
@@ -41,255 +68,386 @@ func (s *productServiceServer) checkAPI(api string) error {
 	return nil
 }
 
-// connect returns SQL database connection from the pool
-func (s *productServiceServer) connect(ctx context.Context) (*sql.Conn, error) {
-	c, err := s.db.Conn(ctx)
+// toProto converts Product returned by repository to API representation
+func toProto(p *repository.Product) (*v1.ProductProto, error) {
+	td, err := productToProto(p)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to connect to database-> "+err.Error())
+		return nil, status.Error(codes.Unknown, err.Error())
 	}
-	return c, nil
-}
-
-// initialize table Product
-func (s *productServiceServer) createTable(ctx context.Context, c *sql.Conn) error {
-
-	_, err := c.ExecContext(ctx, "CREATE TABLE `Product` (`ID` bigint(20) NOT NULL AUTO_INCREMENT,"+
-		"`Name` varchar(200) DEFAULT NULL,"+
-		"`Price` varchar(200) DEFAULT NULL,"+
-		"`Creator` varchar(200) DEFAULT NULL,"+
-		"`Unit` varchar(200) DEFAULT NULL,"+
-		"`Category` varchar(200) DEFAULT NULL,"+
-		"`Description` varchar(1024) DEFAULT NULL,"+
-		"`Date` timestamp NULL DEFAULT NULL,"+
-		"PRIMARY KEY (`ID`),"+
-		"UNIQUE KEY `ID_UNIQUE` (`ID`))")
-
-	if err != nil {
-		return status.Error(codes.Unknown, "failed to create table -> "+err.Error())
-	}
-	return nil
+	return td, nil
 }
 
 // Create new product task
 func (s *productServiceServer) Create(ctx context.Context, req *v1.CreateRequest) (*v1.CreateResponse, error) {
 	// check if the API version requested by client is supported by server
-	if err := s.checkAPI(req.Api); err != nil {
+	if err := checkAPI(req.Api); err != nil {
 		return nil, err
 	}
 
-	// get SQL connection from pool
-	c, err := s.connect(ctx)
-	if err != nil {
+	if err := validateCreate("", req); err != nil {
 		return nil, err
 	}
-	defer c.Close()
 
-	date, err := ptypes.Timestamp(req.Product.Date)
+	p, err := productFromProto(req.Product, true)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "date field has invalid format-> "+err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	_, err = c.ExecContext(ctx, "SELECT 1 FROM Product LIMIT 1 ;")
 
+	key, err := newRequestKey("Create", req.RequestId, req.Product)
 	if err != nil {
-		logger.Log.Warn("Table 'Product' doesn't exist: It will be created now.")
-		err = s.createTable(ctx, c)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
-	// insert Product entity data
-	res, err := c.ExecContext(ctx, "INSERT INTO Product(`Name`, `Price`, `Creator`, `Unit`, `Category`, `Description`, `Date`) VALUES(?, ?, ?, ?, ?, ?, ?)",
-		req.Product.Name, req.Product.Price, req.Product.Creator, req.Product.Unit, req.Product.Category, req.Product.Description, date)
+	// created is nil if the response of retried request is replayed
+	res := &v1.CreateResponse{}
+	var created *repository.Product
+	err = s.writeOnce(ctx, key, res, func(store repository.ProductStore) error {
+		var err error
+		if created, err = store.Create(ctx, p); err != nil {
+			return err
+		}
+		res.Api, res.Id, res.Revision = apiVersion, created.ID, created.Revision
+		return nil
+	})
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to insert into Product-> "+err.Error())
+		return nil, repositoryError(ctx, err)
 	}
-
-	// get ID of creates Product
-	id, err := res.LastInsertId()
-	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve id for created Product-> "+err.Error())
+	if created != nil {
+		s.notify(ctx, v1.ChangeType_CREATED, created)
 	}
 
-	return &v1.CreateResponse{
-		Api: apiVersion,
-		Id:  id,
-	}, nil
+	return res, nil
 }
 
 // Read product task
 func (s *productServiceServer) Read(ctx context.Context, req *v1.ReadRequest) (*v1.ReadResponse, error) {
 	// check if the API version requested by client is supported by server
-	if err := s.checkAPI(req.Api); err != nil {
+	if err := checkAPI(req.Api); err != nil {
 		return nil, err
 	}
 
-	// get SQL connection from pool
-	c, err := s.connect(ctx)
-	if err != nil {
+	if err := validateRead("", req); err != nil {
 		return nil, err
 	}
-	defer c.Close()
 
-	// query product by ID
-	rows, err := c.QueryContext(ctx, "SELECT `ID`, `Name`, `Price`, `Creator`, `Unit`, `Category`, `Description`, `Date` FROM Product WHERE `ID`=?",
-		req.Id)
+	p, err := s.repo.Get(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to select from Product-> "+err.Error())
+		return nil, repositoryError(ctx, err)
 	}
-	defer rows.Close()
 
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			return nil, status.Error(codes.Unknown, "failed to retrieve data from Product-> "+err.Error())
-		}
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("Product with ID='%d' is not found",
-			req.Id))
-	}
-
-	// get Product data
-	var td v1.ProductProto
-	var date time.Time
-	if err := rows.Scan(&td.Id, &td.Name, &td.Price, &td.Creator, &td.Unit, &td.Category, &td.Description, &date); err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve field values from Product row-> "+err.Error())
-	}
-	td.Date, err = ptypes.TimestampProto(date)
+	td, err := toProto(p)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "date field has invalid format-> "+err.Error())
-	}
-
-	if rows.Next() {
-		return nil, status.Error(codes.Unknown, fmt.Sprintf("found multiple Product rows with ID='%d'",
-			req.Id))
+		return nil, err
 	}
 
 	return &v1.ReadResponse{
 		Api:     apiVersion,
-		Product: &td,
+		Product: td,
 	}, nil
 
 }
 
+// updateProduct converts update request to Product and fields to write
+func updateProduct(req *v1.UpdateRequest) (*repository.Product, []repository.Field, error) {
+	fields, err := updateFields(req.UpdateMask)
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// date is converted only if it is written
+	p, err := productFromProto(req.Product, hasField(fields, repository.FieldDate))
+	if err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return p, fields, nil
+}
+
+// updatePayload returns the part of update request which must match for retries with the same request ID
+func updatePayload(req *v1.UpdateRequest) *v1.UpdateRequest {
+	return &v1.UpdateRequest{Product: req.Product, UpdateMask: req.UpdateMask, ExpectedRevision: req.ExpectedRevision}
+}
+
 // Update product task
 func (s *productServiceServer) Update(ctx context.Context, req *v1.UpdateRequest) (*v1.UpdateResponse, error) {
 	// check if the API version requested by client is supported by server
-	if err := s.checkAPI(req.Api); err != nil {
+	if err := checkAPI(req.Api); err != nil {
 		return nil, err
 	}
 
-	// get SQL connection from pool
-	c, err := s.connect(ctx)
-	if err != nil {
+	if err := validateUpdate("", req); err != nil {
 		return nil, err
 	}
-	defer c.Close()
 
-	date, err := ptypes.Timestamp(req.Product.Date)
+	p, fields, err := updateProduct(req)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "date field has invalid format-> "+err.Error())
+		return nil, err
 	}
 
-	// update Product
-	res, err := c.ExecContext(ctx, "UPDATE Product SET `Name`=?, `Price`=?, `Unit`=?, `Category`=?, `Creator`=?, `Description`=?, `Date`=? WHERE `ID`=?",
-		req.Product.Name, req.Product.Price, req.Product.Unit, req.Product.Category, req.Product.Creator, req.Product.Description, date, req.Product.Id)
-
+	key, err := newRequestKey("Update", req.RequestId, updatePayload(req))
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to update Product-> "+err.Error())
+		return nil, err
 	}
 
-	rows, err := res.RowsAffected()
+	// updated is nil if the response of retried request is replayed
+	res := &v1.UpdateResponse{}
+	var updated *repository.Product
+	err = s.writeOnce(ctx, key, res, func(store repository.ProductStore) error {
+		var err error
+		if updated, err = store.Update(ctx, p, fields, req.ExpectedRevision); err != nil {
+			return err
+		}
+		res.Api, res.Updated = apiVersion, 1
+		return nil
+	})
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve rows affected value-> "+err.Error())
+		return nil, repositoryError(ctx, err)
 	}
-
-	if rows == 0 {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("Product with ID='%d' is not found",
-			req.Product.Id))
+	if updated != nil {
+		s.notify(ctx, v1.ChangeType_UPDATED, updated)
 	}
 
-	return &v1.UpdateResponse{
-		Api:     apiVersion,
-		Updated: rows,
-	}, nil
+	return res, nil
 }
 
-// Delete product task
+// Delete product task, it is kept until it is purged
 func (s *productServiceServer) Delete(ctx context.Context, req *v1.DeleteRequest) (*v1.DeleteResponse, error) {
 	// check if the API version requested by client is supported by server
-	if err := s.checkAPI(req.Api); err != nil {
+	if err := checkAPI(req.Api); err != nil {
 		return nil, err
 	}
 
-	// get SQL connection from pool
-	c, err := s.connect(ctx)
-	if err != nil {
+	if err := validateDelete("", req); err != nil {
 		return nil, err
 	}
-	defer c.Close()
 
-	// delete Product
-	res, err := c.ExecContext(ctx, "DELETE FROM Product WHERE `ID`=?", req.Id)
+	revision, err := s.repo.Delete(ctx, req.Id, req.ExpectedRevision)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to delete Product-> "+err.Error())
+		return nil, repositoryError(ctx, err)
 	}
+	s.notifyDeleted(ctx, req.Id, revision)
 
-	rows, err := res.RowsAffected()
+	return &v1.DeleteResponse{
+		Api:     apiVersion,
+		Deleted: 1,
+	}, nil
+}
+
+// Undelete restores deleted product task
+func (s *productServiceServer) Undelete(ctx context.Context, req *v1.UndeleteRequest) (*v1.UndeleteResponse, error) {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return nil, err
+	}
+
+	if err := validateUndelete(req); err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.Undelete(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve rows affected value-> "+err.Error())
+		return nil, repositoryError(ctx, err)
 	}
+	s.notify(ctx, v1.ChangeType_UNDELETED, p)
 
-	if rows == 0 {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("Product with ID='%d' is not found",
-			req.Id))
+	td, err := toProto(p)
+	if err != nil {
+		return nil, err
 	}
 
-	return &v1.DeleteResponse{
+	return &v1.UndeleteResponse{
 		Api:     apiVersion,
-		Deleted: rows,
+		Product: td,
 	}, nil
 }
 
-// Read all Product tasks
+// Read all product tasks
 func (s *productServiceServer) ReadAll(ctx context.Context, req *v1.ReadAllRequest) (*v1.ReadAllResponse, error) {
 	// check if the API version requested by client is supported by server
-	if err := s.checkAPI(req.Api); err != nil {
+	if err := checkAPI(req.Api); err != nil {
 		return nil, err
 	}
 
-	// get SQL connection from pool
-	c, err := s.connect(ctx)
-	if err != nil {
+	if err := validateReadAll(req); err != nil {
 		return nil, err
 	}
-	defer c.Close()
 
-	// get Product list
-	rows, err := c.QueryContext(ctx, "SELECT `ID`, `Name`, `Price`, `Creator`, `Unit`, `Category`, `Description`, `Date` FROM Product")
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	order, err := parseOrderBy(req.OrderBy)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to select from Product-> "+err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	defer rows.Close()
 
-	var date time.Time
-	list := []*v1.ProductProto{}
-	for rows.Next() {
-		td := new(v1.ProductProto)
-		if err := rows.Scan(&td.Id, &td.Name, &td.Price, &td.Unit, &td.Category, &td.Creator, &td.Description, &date); err != nil {
-			return nil, status.Error(codes.Unknown, "failed to retrieve field values from Product row-> "+err.Error())
-		}
-		td.Date, err = ptypes.TimestampProto(date)
+	filter, err := productFilter(req.Filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	pricePer, err := unitFromProto(req.PricePer)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "price_per field is invalid-> "+err.Error())
+	}
+	filter.ShowDeleted = req.ShowDeleted
+	query := queryFingerprint(req.Filter, req.ShowDeleted, order)
+
+	// get Product page, one extra Product tells if there is a next page
+	q := repository.ListQuery{Filter: filter, Order: order, Limit: pageSize + 1}
+
+	// continue after the last Product of the previous page
+	if len(req.PageToken) > 0 {
+		token, err := decodePageToken(s.pageTokenKey, req.PageToken)
 		if err != nil {
-			return nil, status.Error(codes.Unknown, "date field has invalid format-> "+err.Error())
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token-> "+err.Error())
 		}
-		list = append(list, td)
+		if token.Query != query {
+			return nil, status.Error(codes.InvalidArgument, "page_token doesn't match filter and order_by of the request")
+		}
+		if q.After, err = afterProduct(order, token); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token-> "+err.Error())
+		}
+	}
+
+	// count all matching Products
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve data from Product-> "+err.Error())
+	products, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+
+	var next string
+	if len(products) > pageSize {
+		products = products[:pageSize]
+		last := products[len(products)-1]
+		next = encodePageToken(s.pageTokenKey, pageToken{Query: query, LastID: last.ID, LastValue: sortValue(order, last)})
+	}
+
+	list := make([]*v1.ProductProto, 0, len(products))
+	for _, p := range products {
+		td, err := toProto(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(pricePer) > 0 {
+			td.UnitPrice = unitPrice(p, pricePer)
+		}
+		list = append(list, td)
 	}
 
 	return &v1.ReadAllResponse{
-		Api:      apiVersion,
-		Products: list,
+		Api:           apiVersion,
+		Products:      list,
+		NextPageToken: next,
+		TotalSize:     total,
 	}, nil
 }
+
+// StreamProducts sends all Products one by one as they are read from database
+func (s *productServiceServer) StreamProducts(req *v1.StreamProductsRequest, stream v1.ProductService_StreamProductsServer) error {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return err
+	}
+
+	if err := validateStreamProducts(req); err != nil {
+		return err
+	}
+
+	order, err := parseOrderBy(req.OrderBy)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter, err := productFilter(req.Filter)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// query is canceled as soon as client cancels the stream
+	ctx := stream.Context()
+
+	err = s.repo.Stream(ctx, repository.ListQuery{Filter: filter, Order: order}, func(p *repository.Product) error {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		td, err := toProto(p)
+		if err != nil {
+			return err
+		}
+		return stream.Send(&v1.StreamProductsResponse{
+			Api:     apiVersion,
+			Product: td,
+		})
+	})
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if err != nil {
+		return repositoryError(ctx, err)
+	}
+	return nil
+}
+
+// notify appends change of the created or updated Product to the change log and updates it in search index
+func (s *productServiceServer) notify(ctx context.Context, t v1.ChangeType, p *repository.Product) {
+	td, err := productToProto(p)
+	if err != nil {
+		// Product was written already, so the change is announced without date
+		td = &v1.ProductProto{Id: p.ID, Revision: p.Revision}
+	}
+	s.changes.append(t, td)
+	s.indexProduct(ctx, p)
+}
+
+// notifyDeleted appends deletion of the Product to the change log and removes it from search index
+func (s *productServiceServer) notifyDeleted(ctx context.Context, id int64, revision int64) {
+	s.changes.append(v1.ChangeType_DELETED, &v1.ProductProto{Id: id, Revision: revision})
+	s.unindexProduct(ctx, id, revision)
+}
+
+// Watch streams changes of products made after the resume token,
+// or from now on if the token is empty
+func (s *productServiceServer) Watch(req *v1.WatchRequest, stream v1.ProductService_WatchServer) error {
+	// check if the API version requested by client is supported by server
+	if err := checkAPI(req.Api); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+
+	seq, err := s.changes.resume(req.ResumeToken)
+	if err == errExpiredResumeToken {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+	if err != nil {
+		return fieldError("resume_token", err)
+	}
+
+	for {
+		events, wait, err := s.changes.since(seq)
+		if err != nil {
+			// the watcher is too slow to keep up with the change log
+			return status.Error(codes.OutOfRange, err.Error())
+		}
+		for _, e := range events {
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+			seq++
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-wait:
+		}
+	}
+}
@@ -4,261 +4,243 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/measure"
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/repository/memory"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
-	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func Test_productoServiceServer_Create(t *testing.T) {
 	ctx := context.Background()
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-	s := NewProductServiceServer(db)
 	tm := time.Now().In(time.UTC)
 	date, _ := ptypes.TimestampProto(tm)
 
-	type args struct {
-		ctx context.Context
-		req *v1.CreateRequest
-	}
 	tests := []struct {
-		name    string
-		s       v1.ProductServiceServer
-		args    args
-		mock    func()
-		want    *v1.CreateResponse
-		wantErr bool
+		name     string
+		repo     *fakeRepository
+		req      *v1.CreateRequest
+		want     *v1.CreateResponse
+		wantCode codes.Code
 	}{
 		{
 			name: "OK",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.CreateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Name:        "Name",
-						Description: "Description",
-						Date:        date,
-					},
+			repo: newFakeRepository(repository.Product{ID: 1}),
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name:        "Name",
+					Description: "Description",
+					Date:        date,
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("INSERT INTO Product").WithArgs("Name", "Description", tm).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-			},
 			want: &v1.CreateResponse{
-				Api: "v1",
-				Id:  1,
+				Api:      "v1",
+				Id:       2,
+				Revision: 1,
 			},
 		},
 		{
 			name: "Unsupported API",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.CreateRequest{
-					Api: "v1000",
-					Product: &v1.ProductProto{
-						Name:        "Name",
-						Description: "Description",
-						Date: &timestamp.Timestamp{
-							Seconds: 1,
-							Nanos:   -1,
-						},
-					},
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1000",
+				Product: &v1.ProductProto{
+					Name: "Name",
+					Date: date,
 				},
 			},
-			mock:    func() {},
-			wantErr: true,
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name: "Missing product",
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1",
+			},
+			wantCode: codes.InvalidArgument,
 		},
 		{
 			name: "Invalid Date field format",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.CreateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Name:        "Name",
-						Description: "Description",
-						Date: &timestamp.Timestamp{
-							Seconds: 1,
-							Nanos:   -1,
-						},
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name: "Name",
+					Date: &timestamp.Timestamp{
+						Seconds: 1,
+						Nanos:   -1,
 					},
 				},
 			},
-			mock:    func() {},
-			wantErr: true,
+			wantCode: codes.InvalidArgument,
 		},
 		{
-			name: "INSERT failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.CreateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Name:        "name",
-						Description: "description",
-						Date:        date,
-					},
+			name: "Unknown price currency",
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name:  "Name",
+					Price: &v1.Money{CurrencyCode: "EURO", Units: 5},
+					Date:  date,
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("INSERT INTO Product").WithArgs("name", "description", tm).
-					WillReturnError(errors.New("INSERT failed"))
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown unit",
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name: "Name",
+					Unit: v1.UnitOfMeasure(100),
+					Date: date,
+				},
 			},
-			wantErr: true,
+			wantCode: codes.InvalidArgument,
 		},
 		{
-			name: "LastInsertId failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.CreateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Name:        "name",
-						Description: "description",
-						Date:        date,
-					},
+			name: "Price below minor unit",
+			repo: newFakeRepository(),
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name:  "Name",
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 5, Nanos: 5000000},
+					Date:  date,
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("INSERT INTO Product").WithArgs("name", "description", tm).
-					WillReturnResult(sqlmock.NewErrorResult(errors.New("LastInsertId failed")))
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Repository failed",
+			repo: &fakeRepository{err: errors.New("failed to insert into Product-> INSERT failed")},
+			req: &v1.CreateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Name: "name",
+					Date: date,
+				},
 			},
-			wantErr: true,
+			wantCode: codes.Unknown,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-			got, err := tt.s.Create(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("productServiceServer.Create() error = %v, wantErr %v", err, tt.wantErr)
+			s := NewProductServiceServer(tt.repo, nil, []byte("secret"), nil)
+			got, err := s.Create(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Create() error = %v, wantCode %v", err, tt.wantCode)
 				return
 			}
-			if err == nil && !reflect.DeepEqual(got, tt.want) {
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("productServiceServer.Create() = %v, want %v", got, tt.want)
 			}
+			if err == nil {
+				p, _ := tt.repo.Get(ctx, got.Id)
+				if p == nil || p.Name != tt.req.Product.Name || !p.Date.Equal(tm) {
+					t.Errorf("productServiceServer.Create() stored %v", p)
+				}
+			}
 		})
 	}
 }
 
 func Test_productServiceServer_Read(t *testing.T) {
 	ctx := context.Background()
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-	s := NewProductServiceServer(db)
 	tm := time.Now().In(time.UTC)
 	date, _ := ptypes.TimestampProto(tm)
+	repo := newFakeRepository(repository.Product{
+		ID:          1,
+		Name:        "name",
+		Price:       money.Amount{Currency: "EUR", Minor: 500},
+		Creator:     "Marty",
+		Unit:        "kg",
+		CategoryID:  5,
+		Description: "description",
+		Date:        tm,
+		Revision:    2,
+	})
 
-	type args struct {
-		ctx context.Context
-		req *v1.ReadRequest
-	}
 	tests := []struct {
-		name    string
-		s       v1.ProductServiceServer
-		args    args
-		mock    func()
-		want    *v1.ReadResponse
-		wantErr bool
+		name     string
+		repo     *fakeRepository
+		req      *v1.ReadRequest
+		want     *v1.ReadResponse
+		wantCode codes.Code
 	}{
 		{
 			name: "OK",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.ReadRequest{
-					Api: "v1",
-					Id:  1,
-				},
-			},
-			mock: func() {
-				rows := sqlmock.NewRows([]string{"ID", "Name", "Description", "Date"}).
-					AddRow(1, "name", "description", tm)
-				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).WillReturnRows(rows)
+			repo: repo,
+			req: &v1.ReadRequest{
+				Api: "v1",
+				Id:  1,
 			},
 			want: &v1.ReadResponse{
 				Api: "v1",
 				Product: &v1.ProductProto{
 					Id:          1,
 					Name:        "name",
+					Price:       &v1.Money{CurrencyCode: "EUR", Units: 5},
+					Creator:     "Marty",
+					Unit:        v1.UnitOfMeasure_KILOGRAM,
+					CategoryId:  5,
 					Description: "description",
 					Date:        date,
+					Revision:    2,
 				},
 			},
 		},
 		{
 			name: "Unsupported API",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.ReadRequest{
-					Api: "v1",
-					Id:  1,
-				},
+			repo: repo,
+			req: &v1.ReadRequest{
+				Api: "v1000",
+				Id:  1,
 			},
-			mock:    func() {},
-			wantErr: true,
+			wantCode: codes.Unimplemented,
 		},
 		{
-			name: "SELECT failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.ReadRequest{
-					Api: "v1",
-					Id:  1,
-				},
-			},
-			mock: func() {
-				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).
-					WillReturnError(errors.New("SELECT failed"))
+			name: "Not found",
+			repo: repo,
+			req: &v1.ReadRequest{
+				Api: "v1",
+				Id:  2,
 			},
-			wantErr: true,
+			wantCode: codes.NotFound,
 		},
 		{
-			name: "Not found",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.ReadRequest{
-					Api: "v1",
-					Id:  1,
-				},
-			},
-			mock: func() {
-				rows := sqlmock.NewRows([]string{"ID", "Name", "Description", "Date"})
-				mock.ExpectQuery("SELECT (.+) FROM Product").WithArgs(1).WillReturnRows(rows)
+			name: "Repository failed",
+			repo: &fakeRepository{err: errors.New("failed to select from Product-> SELECT failed")},
+			req: &v1.ReadRequest{
+				Api: "v1",
+				Id:  1,
 			},
-			wantErr: true,
+			wantCode: codes.Unknown,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-			got, err := tt.s.Read(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("productServiceServer.Read() error = %v, wantErr %v", err, tt.wantErr)
+			s := NewProductServiceServer(tt.repo, nil, []byte("secret"), nil)
+			got, err := s.Read(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Read() error = %v, wantCode %v", err, tt.wantCode)
 				return
 			}
-
-			if err == nil && !reflect.DeepEqual(got, tt.want) {
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("productServiceServer.Read() = %v, want %v", got, tt.want)
 			}
 		})
@@ -267,164 +249,157 @@ func Test_productServiceServer_Read(t *testing.T) {
 
 func Test_productServiceServer_Update(t *testing.T) {
 	ctx := context.Background()
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-	s := NewProductServiceServer(db)
 	tm := time.Now().In(time.UTC)
 	date, _ := ptypes.TimestampProto(tm)
+	old := repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 500}, Description: "description", Revision: 3}
 
-	type args struct {
-		ctx context.Context
-		req *v1.UpdateRequest
-	}
 	tests := []struct {
-		name    string
-		s       v1.ProductServiceServer
-		args    args
-		mock    func()
-		want    *v1.UpdateResponse
-		wantErr bool
+		name     string
+		req      *v1.UpdateRequest
+		want     *repository.Product
+		wantCode codes.Code
 	}{
 		{
 			name: "OK",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date:        date,
-					},
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:          1,
+					Name:        "new name",
+					Description: "new description",
+					Date:        date,
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("UPDATE Product").WithArgs("new name", "new description", tm, 1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-			},
-			want: &v1.UpdateResponse{
-				Api:     "v1",
-				Updated: 1,
+			want: &repository.Product{ID: 1, Name: "new name", Description: "new description", Date: tm, Revision: 4},
+		},
+		{
+			name: "Partial update",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:    1,
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 6},
+					Name:  "ignored",
+				},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"price"}},
 			},
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 600}, Description: "description", Revision: 4},
 		},
 		{
-			name: "Unsupported API",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date:        date,
-					},
+			name: "Partial update of date",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:          1,
+					Description: "new description",
+					Date:        date,
 				},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"description", "date"}},
 			},
-			mock:    func() {},
-			wantErr: true,
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 500}, Description: "new description", Date: tm, Revision: 4},
 		},
 		{
-			name: "Invalid Date field format",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date: &timestamp.Timestamp{
-							Seconds: 1,
-							Nanos:   -1,
-						},
-					},
+			name: "Expected revision",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:    1,
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 6},
 				},
+				UpdateMask:       &field_mask.FieldMask{Paths: []string{"price"}},
+				ExpectedRevision: 3,
 			},
-			mock:    func() {},
-			wantErr: true,
+			want: &repository.Product{ID: 1, Name: "name", Price: money.Amount{Currency: "EUR", Minor: 600}, Description: "description", Revision: 4},
 		},
 		{
-			name: "UPDATE failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date:        date,
-					},
+			name: "Revision mismatch",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:    1,
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 6},
 				},
+				UpdateMask:       &field_mask.FieldMask{Paths: []string{"price"}},
+				ExpectedRevision: 2,
 			},
-			mock: func() {
-				mock.ExpectExec("UPDATE Product").WithArgs("new name", "new description", tm, 1).
-					WillReturnError(errors.New("UPDATE failed"))
+			wantCode: codes.Aborted,
+		},
+		{
+			name: "Not found",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:    2,
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 6},
+				},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"price"}},
 			},
-			wantErr: true,
+			wantCode: codes.NotFound,
 		},
 		{
-			name: "RowsAffected failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date:        date,
-					},
+			name: "Unknown update mask path",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:    1,
+					Price: &v1.Money{CurrencyCode: "EUR", Units: 6},
 				},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"price", "id"}},
 			},
-			mock: func() {
-				mock.ExpectExec("UPDATE Product").WithArgs("new name", "new description", tm, 1).
-					WillReturnResult(sqlmock.NewErrorResult(errors.New("RowsAffected failed")))
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Missing product",
+			req: &v1.UpdateRequest{
+				Api: "v1",
 			},
-			wantErr: true,
+			wantCode: codes.InvalidArgument,
 		},
 		{
-			name: "Not Found",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.UpdateRequest{
-					Api: "v1",
-					Product: &v1.ProductProto{
-						Id:          1,
-						Name:        "new name",
-						Description: "new description",
-						Date:        date,
+			name: "Invalid Date field format",
+			req: &v1.UpdateRequest{
+				Api: "v1",
+				Product: &v1.ProductProto{
+					Id:   1,
+					Name: "new name",
+					Date: &timestamp.Timestamp{
+						Seconds: 1,
+						Nanos:   -1,
 					},
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("UPDATE Product").WithArgs("new name", "new description", tm, 1).
-					WillReturnResult(sqlmock.NewResult(1, 0))
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unsupported API",
+			req: &v1.UpdateRequest{
+				Api: "v1000",
+				Product: &v1.ProductProto{
+					Id:   1,
+					Name: "new name",
+					Date: date,
+				},
 			},
-			wantErr: true,
+			wantCode: codes.Unimplemented,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-			got, err := tt.s.Update(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("productServiceServer.Update() error = %v, wantErr %v", err, tt.wantErr)
+			repo := newFakeRepository(old)
+			s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+			got, err := s.Update(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Update() error = %v, wantCode %v", err, tt.wantCode)
 				return
 			}
-			if err == nil && !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("productServiceServer.Update() = %v, want %v", got, tt.want)
+			if err != nil {
+				return
+			}
+			if want := (&v1.UpdateResponse{Api: "v1", Updated: 1}); !reflect.DeepEqual(got, want) {
+				t.Errorf("productServiceServer.Update() = %v, want %v", got, want)
+			}
+			if p, _ := repo.Get(ctx, 1); !reflect.DeepEqual(p, tt.want) {
+				t.Errorf("productServiceServer.Update() stored %v, want %v", p, tt.want)
 			}
 		})
 	}
@@ -432,221 +407,794 @@ func Test_productServiceServer_Update(t *testing.T) {
 
 func Test_productServiceServer_Delete(t *testing.T) {
 	ctx := context.Background()
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+
+	tests := []struct {
+		name     string
+		req      *v1.DeleteRequest
+		wantCode codes.Code
+	}{
+		{
+			name: "OK",
+			req: &v1.DeleteRequest{
+				Api: "v1",
+				Id:  1,
+			},
+		},
+		{
+			name: "Expected revision",
+			req: &v1.DeleteRequest{
+				Api:              "v1",
+				Id:               1,
+				ExpectedRevision: 2,
+			},
+		},
+		{
+			name: "Revision mismatch",
+			req: &v1.DeleteRequest{
+				Api:              "v1",
+				Id:               1,
+				ExpectedRevision: 1,
+			},
+			wantCode: codes.Aborted,
+		},
+		{
+			name: "Not found",
+			req: &v1.DeleteRequest{
+				Api: "v1",
+				Id:  2,
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Unsupported API",
+			req: &v1.DeleteRequest{
+				Api: "v1000",
+				Id:  1,
+			},
+			wantCode: codes.Unimplemented,
+		},
 	}
-	defer db.Close()
-	s := NewProductServiceServer(db)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository(repository.Product{ID: 1, Revision: 2})
+			s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+			got, err := s.Delete(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Delete() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if want := (&v1.DeleteResponse{Api: "v1", Deleted: 1}); !reflect.DeepEqual(got, want) {
+				t.Errorf("productServiceServer.Delete() = %v, want %v", got, want)
+			}
+			if _, err := repo.Get(ctx, 1); err == nil {
+				t.Errorf("productServiceServer.Delete() didn't delete Product")
+			}
+		})
+	}
+}
 
-	type args struct {
-		ctx context.Context
-		req *v1.DeleteRequest
+func Test_productServiceServer_Undelete(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	repo := newFakeRepository(
+		repository.Product{ID: 1, Name: "name 1", Date: tm, Revision: 1},
+		repository.Product{ID: 2, Name: "name 2", Date: tm, Revision: 1},
+	)
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	if _, err := s.Delete(ctx, &v1.DeleteRequest{Api: "v1", Id: 1}); err != nil {
+		t.Fatalf("productServiceServer.Delete() error = %v", err)
 	}
+	if _, err := s.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("productServiceServer.Read() error = %v, wantCode %v", err, codes.NotFound)
+	}
+
+	// deleted Products are listed on request only
+	got, err := s.ReadAll(ctx, &v1.ReadAllRequest{Api: "v1"})
+	if err != nil || len(got.Products) != 1 || got.TotalSize != 1 || got.Products[0].Id != 2 {
+		t.Errorf("productServiceServer.ReadAll() = %v, %v, want Product 2", got, err)
+	}
+	got, err = s.ReadAll(ctx, &v1.ReadAllRequest{Api: "v1", PageSize: 1, ShowDeleted: true})
+	if err != nil || len(got.Products) != 1 || got.TotalSize != 2 || got.Products[0].DeleteTime == nil {
+		t.Fatalf("productServiceServer.ReadAll() = %v, %v, want deleted Product 1", got, err)
+	}
+	if _, err := s.ReadAll(ctx, &v1.ReadAllRequest{Api: "v1", PageToken: got.NextPageToken}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("productServiceServer.ReadAll() error = %v, want InvalidArgument for page token of listing with deleted Products", err)
+	}
+
 	tests := []struct {
-		name    string
-		s       v1.ProductServiceServer
-		args    args
-		mock    func()
-		want    *v1.DeleteResponse
-		wantErr bool
+		name     string
+		req      *v1.UndeleteRequest
+		want     *v1.UndeleteResponse
+		wantCode codes.Code
 	}{
 		{
 			name: "OK",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.DeleteRequest{
-					Api: "v1",
-					Id:  1,
+			req:  &v1.UndeleteRequest{Api: "v1", Id: 1},
+			want: &v1.UndeleteResponse{Api: "v1", Product: &v1.ProductProto{Id: 1, Name: "name 1", Date: date, Revision: 3}},
+		},
+		{
+			name:     "Not deleted",
+			req:      &v1.UndeleteRequest{Api: "v1", Id: 2},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "Invalid ID",
+			req:      &v1.UndeleteRequest{Api: "v1"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unsupported API",
+			req:      &v1.UndeleteRequest{Api: "v1000", Id: 1},
+			wantCode: codes.Unimplemented,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Undelete(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Undelete() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.Undelete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_productServiceServer_ReadAll(t *testing.T) {
+	ctx := context.Background()
+	key := []byte("secret")
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	repo := newFakeRepository(
+		repository.Product{ID: 1, Name: "name 1", CategoryID: 5, Date: tm, Revision: 1},
+		repository.Product{ID: 2, Name: "name 2", CategoryID: 7, Date: tm, Revision: 1},
+		repository.Product{ID: 3, Name: "name 3", CategoryID: 5, Date: tm, Revision: 1},
+	)
+	s := NewProductServiceServer(repo, nil, key, nil)
+	product := func(id int64, name string, category int64) *v1.ProductProto {
+		return &v1.ProductProto{Id: id, Name: name, CategoryId: category, Date: date, Revision: 1}
+	}
+	byID, _ := parseOrderBy("")
+	byDateDesc, _ := parseOrderBy("date desc")
+	from, _ := ptypes.TimestampProto(tm)
+	filter := &v1.ProductFilter{
+		CategoryId: 5,
+		Creator:    "Marty",
+		DateFrom:   from,
+		NamePrefix: "50%_",
+	}
+
+	tests := []struct {
+		name      string
+		req       *v1.ReadAllRequest
+		want      *v1.ReadAllResponse
+		wantQuery *repository.ListQuery
+		wantCode  codes.Code
+	}{
+		{
+			name: "OK",
+			req: &v1.ReadAllRequest{
+				Api: "v1",
+			},
+			want: &v1.ReadAllResponse{
+				Api: "v1",
+				Products: []*v1.ProductProto{
+					product(1, "name 1", 5),
+					product(2, "name 2", 7),
+					product(3, "name 3", 5),
 				},
+				TotalSize: 3,
 			},
-			mock: func() {
-				mock.ExpectExec("DELETE FROM Product").WithArgs(1).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+			wantQuery: &repository.ListQuery{Order: byID, Limit: defaultPageSize + 1},
+		},
+		{
+			name: "First page",
+			req: &v1.ReadAllRequest{
+				Api:      "v1",
+				PageSize: 1,
 			},
-			want: &v1.DeleteResponse{
-				Api:     "v1",
-				Deleted: 1,
+			want: &v1.ReadAllResponse{
+				Api:           "v1",
+				Products:      []*v1.ProductProto{product(1, "name 1", 5)},
+				NextPageToken: encodePageToken(key, pageToken{Query: queryFingerprint(nil, false, byID), LastID: 1}),
+				TotalSize:     3,
 			},
 		},
 		{
-			name: "Unsupported API",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.DeleteRequest{
-					Api: "v1",
-					Id:  1,
-				},
+			name: "Middle page",
+			req: &v1.ReadAllRequest{
+				Api:       "v1",
+				PageSize:  1,
+				PageToken: encodePageToken(key, pageToken{Query: queryFingerprint(nil, false, byID), LastID: 1}),
+			},
+			want: &v1.ReadAllResponse{
+				Api:           "v1",
+				Products:      []*v1.ProductProto{product(2, "name 2", 7)},
+				NextPageToken: encodePageToken(key, pageToken{Query: queryFingerprint(nil, false, byID), LastID: 2}),
+				TotalSize:     3,
 			},
-			mock:    func() {},
-			wantErr: true,
+			wantQuery: &repository.ListQuery{Order: byID, After: &repository.Product{ID: 1}, Limit: 2},
 		},
 		{
-			name: "DELETE failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.DeleteRequest{
-					Api: "v1",
-					Id:  1,
-				},
+			name: "Last page",
+			req: &v1.ReadAllRequest{
+				Api:       "v1",
+				PageSize:  1,
+				PageToken: encodePageToken(key, pageToken{Query: queryFingerprint(nil, false, byID), LastID: 2}),
 			},
-			mock: func() {
-				mock.ExpectExec("DELETE FROM Product").WithArgs(1).
-					WillReturnError(errors.New("DELETE failed"))
+			want: &v1.ReadAllResponse{
+				Api:       "v1",
+				Products:  []*v1.ProductProto{product(3, "name 3", 5)},
+				TotalSize: 3,
 			},
-			wantErr: true,
 		},
 		{
-			name: "RowsAffected failed",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.DeleteRequest{
-					Api: "v1",
-					Id:  1,
-				},
+			name: "Filter and order",
+			req: &v1.ReadAllRequest{
+				Api:      "v1",
+				PageSize: 1,
+				Filter:   filter,
+				OrderBy:  "Date DESC",
 			},
-			mock: func() {
-				mock.ExpectExec("DELETE FROM Product").WithArgs(1).
-					WillReturnResult(sqlmock.NewErrorResult(errors.New("RowsAffected failed")))
+			want: &v1.ReadAllResponse{
+				Api:      "v1",
+				Products: []*v1.ProductProto{product(1, "name 1", 5)},
+				NextPageToken: encodePageToken(key, pageToken{
+					Query:     queryFingerprint(filter, false, byDateDesc),
+					LastID:    1,
+					LastValue: tm.Format(time.RFC3339Nano),
+				}),
+				TotalSize: 2,
+			},
+			wantQuery: &repository.ListQuery{
+				Filter: repository.Filter{CategoryID: 5, Creator: "Marty", DateFrom: tm, NamePrefix: "50%_"},
+				Order:  repository.Order{Field: repository.FieldDate, Desc: true},
+				Limit:  2,
 			},
-			wantErr: true,
 		},
 		{
-			name: "Not Found",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.DeleteRequest{
-					Api: "v1",
-					Id:  1,
+			name: "Filter and order next page",
+			req: &v1.ReadAllRequest{
+				Api:      "v1",
+				PageSize: 1,
+				Filter:   filter,
+				OrderBy:  "date desc",
+				PageToken: encodePageToken(key, pageToken{
+					Query:     queryFingerprint(filter, false, byDateDesc),
+					LastID:    1,
+					LastValue: tm.Format(time.RFC3339Nano),
+				}),
+			},
+			want: &v1.ReadAllResponse{
+				Api:       "v1",
+				Products:  []*v1.ProductProto{product(3, "name 3", 5)},
+				TotalSize: 2,
+			},
+			wantQuery: &repository.ListQuery{
+				Filter: repository.Filter{CategoryID: 5, Creator: "Marty", DateFrom: tm, NamePrefix: "50%_"},
+				Order:  repository.Order{Field: repository.FieldDate, Desc: true},
+				After:  &repository.Product{ID: 1, Date: tm},
+				Limit:  2,
+			},
+		},
+		{
+			name: "Page token of another query",
+			req: &v1.ReadAllRequest{
+				Api:       "v1",
+				OrderBy:   "name",
+				PageToken: encodePageToken(key, pageToken{Query: queryFingerprint(nil, false, byID), LastID: 2}),
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unsupported order field",
+			req: &v1.ReadAllRequest{
+				Api:     "v1",
+				OrderBy: "description; DROP TABLE Product",
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Price range in different currencies",
+			req: &v1.ReadAllRequest{
+				Api: "v1",
+				Filter: &v1.ProductFilter{
+					PriceFrom: &v1.Money{CurrencyCode: "EUR", Units: 1},
+					PriceTo:   &v1.Money{CurrencyCode: "USD", Units: 2},
 				},
 			},
-			mock: func() {
-				mock.ExpectExec("DELETE FROM Product").WithArgs(1).
-					WillReturnResult(sqlmock.NewResult(1, 0))
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid order direction",
+			req: &v1.ReadAllRequest{
+				Api:     "v1",
+				OrderBy: "name up",
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid page token",
+			req: &v1.ReadAllRequest{
+				Api:       "v1",
+				PageToken: "not-a-token",
 			},
-			wantErr: true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Page token signed with another key",
+			req: &v1.ReadAllRequest{
+				Api:       "v1",
+				PageToken: encodePageToken([]byte("another secret"), pageToken{Query: queryFingerprint(nil, false, byID), LastID: 2}),
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Negative page size",
+			req: &v1.ReadAllRequest{
+				Api:      "v1",
+				PageSize: -1,
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unsupported API",
+			req: &v1.ReadAllRequest{
+				Api: "v1000",
+			},
+			wantCode: codes.Unimplemented,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-			got, err := tt.s.Delete(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("productServiceServer.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := s.ReadAll(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.ReadAll() error = %v, wantCode %v", err, tt.wantCode)
 				return
 			}
-			if err == nil && !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("productServiceServer.Delete() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productServiceServer.ReadAll() = %v, want %v", got, tt.want)
+			}
+			if tt.wantQuery != nil && !reflect.DeepEqual(repo.query, *tt.wantQuery) {
+				t.Errorf("productServiceServer.ReadAll() queried %+v, want %+v", repo.query, *tt.wantQuery)
 			}
 		})
 	}
 }
 
-func Test_productServiceServer_ReadAll(t *testing.T) {
+// productStreamMock collects responses sent by StreamProducts
+type productStreamMock struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sendErr error
+	sent    []*v1.StreamProductsResponse
+}
+
+func (m *productStreamMock) Context() context.Context {
+	return m.ctx
+}
+
+func (m *productStreamMock) Send(res *v1.StreamProductsResponse) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.sent = append(m.sent, res)
+	return nil
+}
+
+func Test_productServiceServer_ReadAll_pricePer(t *testing.T) {
 	ctx := context.Background()
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	eur := func(minor int64) money.Amount { return money.Amount{Currency: "EUR", Minor: minor} }
+	s := NewProductServiceServer(newFakeRepository(
+		repository.Product{ID: 1, Name: "potato", Price: eur(199), Unit: "kg"},
+		repository.Product{ID: 2, Name: "cheese", Price: eur(2), Unit: "g"},
+		repository.Product{ID: 3, Name: "saffron", Price: eur(1), Unit: "g"},
+		repository.Product{ID: 4, Name: "milk", Price: eur(99), Unit: "l"},
+		repository.Product{ID: 5, Name: "onion", Price: eur(99), Unit: "Kilo"},
+		repository.Product{ID: 6, Name: "carrot", Unit: "kg"},
+	), nil, []byte("secret"), nil)
+
+	tests := []struct {
+		name     string
+		pricePer v1.UnitOfMeasure
+		want     []*v1.Money
+		wantCode codes.Code
+	}{
+		{
+			name:     "Per kilogram",
+			pricePer: v1.UnitOfMeasure_KILOGRAM,
+			want: []*v1.Money{
+				{CurrencyCode: "EUR", Units: 1, Nanos: 990000000},
+				{CurrencyCode: "EUR", Units: 20},
+				{CurrencyCode: "EUR", Units: 10},
+				nil, nil, nil,
+			},
+		},
+		{
+			name:     "Per gram",
+			pricePer: v1.UnitOfMeasure_GRAM,
+			want: []*v1.Money{
+				{CurrencyCode: "EUR", Nanos: 1990000},
+				{CurrencyCode: "EUR", Nanos: 20000000},
+				{CurrencyCode: "EUR", Nanos: 10000000},
+				nil, nil, nil,
+			},
+		},
+		{
+			name: "Not requested",
+			want: []*v1.Money{nil, nil, nil, nil, nil, nil},
+		},
+		{
+			name:     "Unknown unit",
+			pricePer: v1.UnitOfMeasure(100),
+			wantCode: codes.InvalidArgument,
+		},
 	}
-	defer db.Close()
-	s := NewProductServiceServer(db)
-	tm1 := time.Now().In(time.UTC)
-	date1, _ := ptypes.TimestampProto(tm1)
-	tm2 := time.Now().In(time.UTC)
-	date2, _ := ptypes.TimestampProto(tm2)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ReadAll(ctx, &v1.ReadAllRequest{Api: "v1", PricePer: tt.pricePer})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.ReadAll() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if err != nil {
+				return
+			}
+			var prices []*v1.Money
+			for _, p := range got.Products {
+				prices = append(prices, p.UnitPrice)
+			}
+			if !reflect.DeepEqual(prices, tt.want) {
+				t.Errorf("productServiceServer.ReadAll() unit prices = %v, want %v", prices, tt.want)
+			}
+		})
+	}
+}
 
-	type args struct {
-		ctx context.Context
-		req *v1.ReadAllRequest
+func Test_unitCodes(t *testing.T) {
+	for u, code := range unitCodes {
+		if _, ok := measure.Lookup(code); !ok {
+			t.Errorf("unit of measure %v has unknown code '%s'", u, code)
+		}
+		if got := unitToProto(code); got != u {
+			t.Errorf("unitToProto(%s) = %v, want %v", code, got, u)
+		}
+	}
+	if len(unitCodes) != len(v1.UnitOfMeasure_name)-1 {
+		t.Errorf("unitCodes has %d units, want all %d specified units", len(unitCodes), len(v1.UnitOfMeasure_name)-1)
 	}
+}
+
+func Test_productServiceServer_StreamProducts(t *testing.T) {
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	repo := newFakeRepository(
+		repository.Product{ID: 1, Name: "name 1", CategoryID: 5, Date: tm, Revision: 1},
+		repository.Product{ID: 2, Name: "name 2", CategoryID: 7, Date: tm, Revision: 1},
+		repository.Product{ID: 3, Name: "name 3", CategoryID: 5, Date: tm, Revision: 1},
+	)
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
 	tests := []struct {
-		name    string
-		s       v1.ProductServiceServer
-		args    args
-		mock    func()
-		want    *v1.ReadAllResponse
-		wantErr bool
+		name      string
+		req       *v1.StreamProductsRequest
+		stream    *productStreamMock
+		want      []*v1.StreamProductsResponse
+		wantQuery *repository.ListQuery
+		wantCode  codes.Code
 	}{
 		{
 			name: "OK",
-			s:    s,
-			args: args{
-				ctx: ctx,
-				req: &v1.ReadAllRequest{
-					Api: "v1",
+			req: &v1.StreamProductsRequest{
+				Api:     "v1",
+				Filter:  &v1.ProductFilter{CategoryId: 5},
+				OrderBy: "name",
+			},
+			stream: &productStreamMock{ctx: ctx},
+			want: []*v1.StreamProductsResponse{
+				{
+					Api:     "v1",
+					Product: &v1.ProductProto{Id: 1, Name: "name 1", CategoryId: 5, Date: date, Revision: 1},
+				},
+				{
+					Api:     "v1",
+					Product: &v1.ProductProto{Id: 3, Name: "name 3", CategoryId: 5, Date: date, Revision: 1},
 				},
 			},
-			mock: func() {
-				rows := sqlmock.NewRows([]string{"ID", "Name", "Description", "Date"}).
-					AddRow(1, "name 1", "description 1", tm1).
-					AddRow(2, "name 2", "description 2", tm2)
-				mock.ExpectQuery("SELECT (.+) FROM Product").WillReturnRows(rows)
+			wantQuery: &repository.ListQuery{
+				Filter: repository.Filter{CategoryID: 5},
+				Order:  repository.Order{Field: repository.FieldName},
 			},
-			want: &v1.ReadAllResponse{
+		},
+		{
+			name: "Send failed",
+			req: &v1.StreamProductsRequest{
 				Api: "v1",
-				Products: []*v1.ProductProto{
-					{
-						Id:          1,
-						Name:        "name 1",
-						Description: "description 1",
-						Date:        date1,
-					},
-					{
-						Id:          2,
-						Name:        "name 2",
-						Description: "description 2",
-						Date:        date2,
-					},
+			},
+			stream:   &productStreamMock{ctx: ctx, sendErr: status.Error(codes.Unavailable, "Send failed")},
+			wantCode: codes.Unavailable,
+		},
+		{
+			name: "Canceled",
+			req: &v1.StreamProductsRequest{
+				Api: "v1",
+			},
+			stream:   &productStreamMock{ctx: canceled},
+			wantCode: codes.Canceled,
+		},
+		{
+			name: "Unsupported order field",
+			req: &v1.StreamProductsRequest{
+				Api:     "v1",
+				OrderBy: "description",
+			},
+			stream:   &productStreamMock{ctx: ctx},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unsupported API",
+			req: &v1.StreamProductsRequest{
+				Api: "v1000",
+			},
+			stream:   &productStreamMock{ctx: ctx},
+			wantCode: codes.Unimplemented,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.StreamProducts(tt.req, tt.stream)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.StreamProducts() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(tt.stream.sent, tt.want) {
+				t.Errorf("productServiceServer.StreamProducts() sent %v, want %v", tt.stream.sent, tt.want)
+			}
+			if tt.wantQuery != nil && !reflect.DeepEqual(repo.query, *tt.wantQuery) {
+				t.Errorf("productServiceServer.StreamProducts() queried %+v, want %+v", repo.query, *tt.wantQuery)
+			}
+		})
+	}
+}
+
+// watchStreamMock collects events sent by Watch and cancels watching after limit events
+type watchStreamMock struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	limit  int
+	sent   []*v1.WatchResponse
+}
+
+func (m *watchStreamMock) Context() context.Context {
+	return m.ctx
+}
+
+func (m *watchStreamMock) Send(res *v1.WatchResponse) error {
+	m.sent = append(m.sent, res)
+	if len(m.sent) >= m.limit {
+		m.cancel()
+	}
+	return nil
+}
+
+func newWatchStreamMock(limit int) *watchStreamMock {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watchStreamMock{ctx: ctx, cancel: cancel, limit: limit}
+}
+
+func Test_productServiceServer_Watch(t *testing.T) {
+	s := NewProductServiceServer(nil, nil, []byte("secret"), nil).(*productServiceServer)
+	s.changes.append(v1.ChangeType_CREATED, &v1.ProductProto{Id: 1, Name: "name 1", Revision: 1})
+	s.changes.append(v1.ChangeType_UPDATED, &v1.ProductProto{Id: 1, Name: "new name 1", Revision: 2})
+	s.changes.append(v1.ChangeType_DELETED, &v1.ProductProto{Id: 1, Revision: 3})
+
+	events, _, err := s.changes.since(1)
+	if err != nil {
+		t.Fatalf("changeLog.since() error = %v", err)
+	}
+
+	type args struct {
+		req    *v1.WatchRequest
+		stream *watchStreamMock
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     []*v1.WatchResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "Resume",
+			args: args{
+				req: &v1.WatchRequest{
+					Api:         "v1",
+					ResumeToken: events[0].ResumeToken,
 				},
+				stream: newWatchStreamMock(2),
 			},
+			want:     events[1:],
+			wantCode: codes.Canceled,
 		},
 		{
-			name: "Empty",
-			s:    s,
+			name: "Resume after the last event",
 			args: args{
-				ctx: ctx,
-				req: &v1.ReadAllRequest{
-					Api: "v1",
+				req: &v1.WatchRequest{
+					Api:         "v1",
+					ResumeToken: events[2].ResumeToken,
 				},
+				stream: func() *watchStreamMock {
+					m := newWatchStreamMock(1)
+					m.cancel()
+					return m
+				}(),
 			},
-			mock: func() {
-				rows := sqlmock.NewRows([]string{"ID", "Name", "Description", "Date"})
-				mock.ExpectQuery("SELECT (.+) FROM Prodcut").WillReturnRows(rows)
+			wantCode: codes.Canceled,
+		},
+		{
+			name: "Token from another server",
+			args: args{
+				req: &v1.WatchRequest{
+					Api:         "v1",
+					ResumeToken: newChangeLog(changeLogSize).token(1),
+				},
+				stream: newWatchStreamMock(1),
 			},
-			want: &v1.ReadAllResponse{
-				Api:      "v1",
-				Products: []*v1.ProductProto{},
+			wantCode: codes.OutOfRange,
+		},
+		{
+			name: "Malformed token",
+			args: args{
+				req: &v1.WatchRequest{
+					Api:         "v1",
+					ResumeToken: "token",
+				},
+				stream: newWatchStreamMock(1),
 			},
+			wantCode: codes.InvalidArgument,
 		},
 		{
 			name: "Unsupported API",
-			s:    s,
 			args: args{
-				ctx: ctx,
-				req: &v1.ReadAllRequest{
-					Api: "v1",
+				req: &v1.WatchRequest{
+					Api: "v1000",
 				},
+				stream: newWatchStreamMock(1),
 			},
-			mock:    func() {},
-			wantErr: true,
+			wantCode: codes.Unimplemented,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
-			got, err := tt.s.ReadAll(tt.args.ctx, tt.args.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("productServiceServer.ReadAll() error = %v, wantErr %v", err, tt.wantErr)
+			err := s.Watch(tt.args.req, tt.args.stream)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("productServiceServer.Watch() error = %v, wantCode %v", err, tt.wantCode)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("productServiceServer.ReadAll() = %v, want %v", got, tt.want)
+			if !reflect.DeepEqual(tt.args.stream.sent, tt.want) {
+				t.Errorf("productServiceServer.Watch() sent %v, want %v", tt.args.stream.sent, tt.want)
 			}
 		})
 	}
 }
+
+func Test_productServiceServer_changes(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Now().In(time.UTC)
+	date, _ := ptypes.TimestampProto(tm)
+	s := NewProductServiceServer(newFakeRepository(), nil, []byte("secret"), nil).(*productServiceServer)
+
+	if _, err := s.Create(ctx, &v1.CreateRequest{Product: &v1.ProductProto{Name: "name", Date: date}}); err != nil {
+		t.Fatalf("productServiceServer.Create() error = %v", err)
+	}
+	if _, err := s.Update(ctx, &v1.UpdateRequest{
+		Product:    &v1.ProductProto{Id: 1, Price: &v1.Money{CurrencyCode: "EUR", Units: 5}},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"price"}},
+	}); err != nil {
+		t.Fatalf("productServiceServer.Update() error = %v", err)
+	}
+	if _, err := s.Delete(ctx, &v1.DeleteRequest{Id: 1}); err != nil {
+		t.Fatalf("productServiceServer.Delete() error = %v", err)
+	}
+	// failed requests don't change anything
+	if _, err := s.Delete(ctx, &v1.DeleteRequest{Id: 1}); err == nil {
+		t.Fatalf("productServiceServer.Delete() of deleted Product succeeded")
+	}
+
+	events, _, err := s.changes.since(1)
+	if err != nil {
+		t.Fatalf("changeLog.since() error = %v", err)
+	}
+	want := []struct {
+		t v1.ChangeType
+		p *v1.ProductProto
+	}{
+		{v1.ChangeType_CREATED, &v1.ProductProto{Id: 1, Name: "name", Date: date, Revision: 1}},
+		{v1.ChangeType_UPDATED, &v1.ProductProto{Id: 1, Name: "name", Price: &v1.Money{CurrencyCode: "EUR", Units: 5}, Date: date, Revision: 2}},
+		{v1.ChangeType_DELETED, &v1.ProductProto{Id: 1, Revision: 3}},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("productServiceServer logged %d changes, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Type != want[i].t || !reflect.DeepEqual(e.Product, want[i].p) {
+			t.Errorf("change %d = %v %v, want %v %v", i, e.Type, e.Product, want[i].t, want[i].p)
+		}
+	}
+}
+
+// delayedRepository returns from writes later than it commits them,
+// the earlier the write is committed the later it returns
+type delayedRepository struct {
+	repository.ProductRepository
+	writes int64
+}
+
+func (r *delayedRepository) delay() {
+	time.Sleep(time.Duration(20-atomic.AddInt64(&r.writes, 1)) * time.Millisecond)
+}
+
+func (r *delayedRepository) Update(ctx context.Context, p *repository.Product, fields []repository.Field,
+	expectedRevision int64) (*repository.Product, error) {
+	updated, err := r.ProductRepository.Update(ctx, p, fields, expectedRevision)
+	r.delay()
+	return updated, err
+}
+
+func (r *delayedRepository) Delete(ctx context.Context, id int64, expectedRevision int64) (int64, error) {
+	revision, err := r.ProductRepository.Delete(ctx, id, expectedRevision)
+	r.delay()
+	return revision, err
+}
+
+func Test_productServiceServer_changes_concurrent(t *testing.T) {
+	ctx := context.Background()
+	r, err := memory.NewRepository("")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	s := NewProductServiceServer(&delayedRepository{ProductRepository: r}, nil, []byte("secret"), nil).(*productServiceServer)
+	if _, err := s.Create(ctx, &v1.CreateRequest{Product: &v1.ProductProto{Name: "name", Date: ptypes.TimestampNow()}}); err != nil {
+		t.Fatalf("productServiceServer.Create() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 5 {
+				s.Delete(ctx, &v1.DeleteRequest{Id: 1})
+				return
+			}
+			s.Update(ctx, &v1.UpdateRequest{
+				Product:    &v1.ProductProto{Id: 1, Description: "description"},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"description"}},
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	events, _, err := s.changes.since(1)
+	if err != nil {
+		t.Fatalf("changeLog.since() error = %v", err)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Product.Revision <= events[i-1].Product.Revision {
+			t.Fatalf("change %d has revision %d after revision %d", i, events[i].Product.Revision, events[i-1].Product.Revision)
+		}
+	}
+	if last := events[len(events)-1]; last.Type != v1.ChangeType_DELETED {
+		t.Errorf("last change = %v, want DELETED", last)
+	}
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// RunPurge permanently removes Products deleted longer than retention ago, unless retention is 0,
+// and expired idempotency keys. They are purged at start and then every interval until ctx is done,
+// failures are logged with logger of ctx and retried on the next run.
+func RunPurge(ctx context.Context, repo repository.ProductRepository, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if retention > 0 {
+			purgeDeleted(ctx, repo, retention)
+		}
+		purgeIdempotencyKeys(ctx, repo)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeDeleted removes Products deleted longer than retention ago
+func purgeDeleted(ctx context.Context, repo repository.ProductRepository, retention time.Duration) {
+	log := ctxzap.Extract(ctx)
+	purged, err := repo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Error("failed to purge deleted Products", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		log.Info("purged deleted Products", zap.Int64("count", purged))
+	}
+}
+
+// purgeIdempotencyKeys removes expired idempotency keys
+func purgeIdempotencyKeys(ctx context.Context, repo repository.ProductRepository) {
+	log := ctxzap.Extract(ctx)
+	purged, err := repo.PurgeIdempotencyKeys(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to purge expired idempotency keys", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		log.Info("purged expired idempotency keys", zap.Int64("count", purged))
+	}
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func TestRunPurge(t *testing.T) {
+	now := time.Now()
+	repo := newFakeRepository(
+		repository.Product{ID: 1, Name: "expired", Revision: 2, DeletedAt: now.Add(-2 * time.Hour)},
+		repository.Product{ID: 2, Name: "retained", Revision: 2, DeletedAt: now.Add(-time.Minute)},
+		repository.Product{ID: 3, Name: "live", Revision: 1},
+	)
+	repo.keys["expired"] = repository.IdempotencyKey{RequestID: "expired", ExpiresAt: now.Add(-time.Minute)}
+	repo.keys["live"] = repository.IdempotencyKey{RequestID: "live", ExpiresAt: now.Add(time.Hour)}
+
+	// purge runs once before it notices that ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	RunPurge(ctx, repo, time.Hour, time.Hour)
+
+	for id, want := range map[int64]bool{1: false, 2: true, 3: true} {
+		if _, ok := repo.products[id]; ok != want {
+			t.Errorf("RunPurge() kept Product %d = %t, want %t", id, ok, want)
+		}
+	}
+	for requestID, want := range map[string]bool{"expired": false, "live": true} {
+		if _, ok := repo.keys[requestID]; ok != want {
+			t.Errorf("RunPurge() kept idempotency key %s = %t, want %t", requestID, ok, want)
+		}
+	}
+}
+
+func TestRunPurge_keysOnly(t *testing.T) {
+	repo := newFakeRepository(repository.Product{ID: 1, Name: "deleted", Revision: 2, DeletedAt: time.Now().Add(-time.Hour)})
+	repo.keys["expired"] = repository.IdempotencyKey{RequestID: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	// retention 0 keeps deleted Products forever
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	RunPurge(ctx, repo, 0, time.Hour)
+
+	if _, ok := repo.products[1]; !ok {
+		t.Errorf("RunPurge() purged Product with retention 0")
+	}
+	if _, ok := repo.keys["expired"]; ok {
+		t.Errorf("RunPurge() kept expired idempotency key")
+	}
+}
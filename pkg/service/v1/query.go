@@ -0,0 +1,330 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/measure"
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// sortableFields is whitelist of fields ReadAll can sort by
+var sortableFields = map[string]repository.Field{
+	"id":    repository.FieldID,
+	"name":  repository.FieldName,
+	"date":  repository.FieldDate,
+	"price": repository.FieldPrice,
+}
+
+// parseOrderBy parses order_by clause in format "<field> [asc|desc]"
+// Empty clause means sorting by ID in ascending order.
+func parseOrderBy(s string) (repository.Order, error) {
+	parts := strings.Fields(strings.ToLower(s))
+	if len(parts) == 0 {
+		return repository.Order{Field: repository.FieldID}, nil
+	}
+	if len(parts) > 2 {
+		return repository.Order{}, fmt.Errorf("order_by '%s' must be in format '<field> [asc|desc]'", s)
+	}
+
+	field, ok := sortableFields[parts[0]]
+	if !ok {
+		return repository.Order{}, fmt.Errorf("order_by field '%s' is not supported, use one of id, name, date, price", parts[0])
+	}
+
+	o := repository.Order{Field: field}
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "asc":
+		case "desc":
+			o.Desc = true
+		default:
+			return repository.Order{}, fmt.Errorf("order_by direction '%s' must be asc or desc", parts[1])
+		}
+	}
+	return o, nil
+}
+
+// sortValue returns value of the sort field of the Product to be saved into page token
+func sortValue(o repository.Order, p *repository.Product) string {
+	switch o.Field {
+	case repository.FieldName:
+		return p.Name
+	case repository.FieldPrice:
+		// Products are sorted by currency first
+		return p.Price.Currency + ":" + strconv.FormatInt(p.Price.Minor, 10)
+	case repository.FieldDate:
+		return p.Date.Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// afterProduct restores the last Product of the previous page from page token
+func afterProduct(o repository.Order, t pageToken) (*repository.Product, error) {
+	p := &repository.Product{ID: t.LastID}
+	switch o.Field {
+	case repository.FieldName:
+		p.Name = t.LastValue
+	case repository.FieldPrice:
+		i := strings.IndexByte(t.LastValue, ':')
+		if i < 0 {
+			return nil, errors.New("malformed page token")
+		}
+		minor, err := strconv.ParseInt(t.LastValue[i+1:], 10, 64)
+		if err != nil {
+			return nil, errors.New("malformed page token")
+		}
+		p.Price = money.Amount{Currency: t.LastValue[:i], Minor: minor}
+	case repository.FieldDate:
+		date, err := time.Parse(time.RFC3339Nano, t.LastValue)
+		if err != nil {
+			return nil, errors.New("malformed page token")
+		}
+		p.Date = date
+	}
+	return p, nil
+}
+
+// productFilter converts filter of the request to repository filter
+func productFilter(f *v1.ProductFilter) (repository.Filter, error) {
+	if f == nil {
+		return repository.Filter{}, nil
+	}
+
+	filter := repository.Filter{
+		CategoryID: f.CategoryId,
+		Creator:    f.Creator,
+		NamePrefix: f.NamePrefix,
+	}
+	var err error
+	if filter.Unit, err = unitFromProto(f.Unit); err != nil {
+		return repository.Filter{}, errors.New("filter.unit field is invalid-> " + err.Error())
+	}
+	if f.DateFrom != nil {
+		if filter.DateFrom, err = ptypes.Timestamp(f.DateFrom); err != nil {
+			return repository.Filter{}, errors.New("filter.date_from field has invalid format-> " + err.Error())
+		}
+	}
+	if f.DateTo != nil {
+		if filter.DateTo, err = ptypes.Timestamp(f.DateTo); err != nil {
+			return repository.Filter{}, errors.New("filter.date_to field has invalid format-> " + err.Error())
+		}
+	}
+	if filter.PriceFrom, err = moneyFromProto(f.PriceFrom); err != nil {
+		return repository.Filter{}, errors.New("filter.price_from field is invalid-> " + err.Error())
+	}
+	if filter.PriceTo, err = moneyFromProto(f.PriceTo); err != nil {
+		return repository.Filter{}, errors.New("filter.price_to field is invalid-> " + err.Error())
+	}
+	if !filter.PriceFrom.IsZero() && !filter.PriceTo.IsZero() && filter.PriceFrom.Currency != filter.PriceTo.Currency {
+		return repository.Filter{}, errors.New("filter.price_from and filter.price_to must have the same currency")
+	}
+	return filter, nil
+}
+
+// queryFingerprint identifies filter, listing of deleted Products and sort order, so that page token
+// can't be used to continue a listing with different parameters
+func queryFingerprint(f *v1.ProductFilter, showDeleted bool, o repository.Order) string {
+	h := sha256.New()
+	if f != nil {
+		b, _ := proto.Marshal(f)
+		h.Write(b)
+	}
+	fmt.Fprintf(h, "|%s|%t", o.Field, o.Desc)
+	if showDeleted {
+		// tokens of listings without deleted Products stay valid
+		h.Write([]byte("|deleted"))
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:8])
+}
+
+// updatableFields maps ProductProto fields accepted in update_mask to Product fields
+var updatableFields = map[string]repository.Field{
+	"name":        repository.FieldName,
+	"price":       repository.FieldPrice,
+	"unit":        repository.FieldUnit,
+	"category_id": repository.FieldCategoryID,
+	"creator":     repository.FieldCreator,
+	"description": repository.FieldDescription,
+	"date":        repository.FieldDate,
+}
+
+// updateFields translates update_mask paths to Product fields,
+// empty mask or "*" means all fields
+func updateFields(mask *field_mask.FieldMask) ([]repository.Field, error) {
+	paths := mask.GetPaths()
+	if len(paths) == 0 || (len(paths) == 1 && paths[0] == "*") {
+		return nil, nil
+	}
+
+	fields := make([]repository.Field, 0, len(paths))
+	for _, path := range paths {
+		field, ok := updatableFields[path]
+		if !ok {
+			return nil, fmt.Errorf("update_mask path '%s' is not supported", path)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// hasField checks if the Product field is written by update of fields
+func hasField(fields []repository.Field, field repository.Field) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// productFromProto converts Product from API to repository representation,
+// date is converted only if withDate is set
+func productFromProto(p *v1.ProductProto, withDate bool) (*repository.Product, error) {
+	if p == nil {
+		return nil, errors.New("product field is required")
+	}
+
+	price, err := moneyFromProto(p.Price)
+	if err != nil {
+		return nil, errors.New("price field is invalid-> " + err.Error())
+	}
+	unit, err := unitFromProto(p.Unit)
+	if err != nil {
+		return nil, errors.New("unit field is invalid-> " + err.Error())
+	}
+
+	product := &repository.Product{
+		ID:          p.Id,
+		Name:        p.Name,
+		Price:       price,
+		Creator:     p.Creator,
+		Unit:        unit,
+		CategoryID:  p.CategoryId,
+		Description: p.Description,
+		Revision:    p.Revision,
+	}
+	if withDate {
+		date, err := ptypes.Timestamp(p.Date)
+		if err != nil {
+			return nil, errors.New("date field has invalid format-> " + err.Error())
+		}
+		product.Date = date
+	}
+	return product, nil
+}
+
+// productToProto converts Product from repository to API representation
+func productToProto(p *repository.Product) (*v1.ProductProto, error) {
+	date, err := ptypes.TimestampProto(p.Date)
+	if err != nil {
+		return nil, errors.New("date field has invalid format-> " + err.Error())
+	}
+	td := &v1.ProductProto{
+		Id:          p.ID,
+		Name:        p.Name,
+		Price:       moneyToProto(p.Price),
+		Creator:     p.Creator,
+		Unit:        unitToProto(p.Unit),
+		CategoryId:  p.CategoryID,
+		Description: p.Description,
+		Date:        date,
+		Revision:    p.Revision,
+	}
+	if !p.DeletedAt.IsZero() {
+		if td.DeleteTime, err = ptypes.TimestampProto(p.DeletedAt); err != nil {
+			return nil, errors.New("deletion time has invalid format-> " + err.Error())
+		}
+	}
+	return td, nil
+}
+
+// moneyFromProto converts Money from API to amount in minor units, nil Money is zero amount
+func moneyFromProto(m *v1.Money) (money.Amount, error) {
+	if m == nil {
+		return money.Amount{}, nil
+	}
+	return money.FromUnits(m.CurrencyCode, m.Units, m.Nanos)
+}
+
+// moneyToProto converts amount in minor units to Money of API, zero amount is nil Money
+func moneyToProto(a money.Amount) *v1.Money {
+	if a.IsZero() {
+		return nil
+	}
+	units, nanos := a.Units()
+	return &v1.Money{CurrencyCode: a.Currency, Units: units, Nanos: nanos}
+}
+
+// unitCodes maps units of measure of API to codes of units stored with Products
+var unitCodes = map[v1.UnitOfMeasure]string{
+	v1.UnitOfMeasure_GRAM:       "g",
+	v1.UnitOfMeasure_KILOGRAM:   "kg",
+	v1.UnitOfMeasure_MILLILITER: "ml",
+	v1.UnitOfMeasure_LITER:      "l",
+	v1.UnitOfMeasure_PIECE:      "pc",
+	v1.UnitOfMeasure_DOZEN:      "dozen",
+	v1.UnitOfMeasure_MILLIMETER: "mm",
+	v1.UnitOfMeasure_CENTIMETER: "cm",
+	v1.UnitOfMeasure_METER:      "m",
+}
+
+// unitFromProto converts unit of measure from API to unit code, unspecified unit is empty code
+func unitFromProto(u v1.UnitOfMeasure) (string, error) {
+	if u == v1.UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED {
+		return "", nil
+	}
+	code, ok := unitCodes[u]
+	if !ok {
+		return "", fmt.Errorf("unit of measure %d is not supported", u)
+	}
+	return code, nil
+}
+
+// unitToProto converts unit code to unit of measure of API,
+// legacy units which are not known are unspecified
+func unitToProto(code string) v1.UnitOfMeasure {
+	for u, c := range unitCodes {
+		if c == code {
+			return u
+		}
+	}
+	return v1.UnitOfMeasure_UNIT_OF_MEASURE_UNSPECIFIED
+}
+
+// unitPrice returns price of the Product per unit, nil if Product has no price
+// or its unit can't be converted to the unit
+func unitPrice(p *repository.Product, per string) *v1.Money {
+	from, ok := measure.Lookup(p.Unit)
+	if !ok || p.Price.IsZero() {
+		return nil
+	}
+	to, ok := measure.Lookup(per)
+	if !ok {
+		return nil
+	}
+	// price per unit is price of all Product units in the unit
+	r, err := measure.Ratio(to, from)
+	if err != nil {
+		return nil
+	}
+	units, nanos, err := p.Price.Mul(r)
+	if err != nil {
+		return nil
+	}
+	return &v1.Money{CurrencyCode: p.Price.Currency, Units: units, Nanos: nanos}
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MartyKuentzel/projectX/pkg/money"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_afterProduct(t *testing.T) {
+	byPrice := repository.Order{Field: repository.FieldPrice}
+	for _, p := range []*repository.Product{
+		{ID: 1, Price: money.Amount{Currency: "EUR", Minor: 250}},
+		{ID: 2},
+	} {
+		got, err := afterProduct(byPrice, pageToken{LastID: p.ID, LastValue: sortValue(byPrice, p)})
+		if err != nil || !reflect.DeepEqual(got, p) {
+			t.Errorf("afterProduct() = %v, %v, want %v", got, err, p)
+		}
+	}
+
+	if _, err := afterProduct(byPrice, pageToken{LastID: 1, LastValue: "250"}); err == nil {
+		t.Errorf("afterProduct() of price without currency error = nil, want error")
+	}
+}
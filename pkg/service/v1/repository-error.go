@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+// errorDomain is domain of google.rpc.ErrorInfo details attached to database errors
+const errorDomain = "projectx.v1"
+
+// delays suggested to clients by google.rpc.RetryInfo details
+const (
+	// conflictRetryDelay is delay before retry of transaction aborted by deadlock or lock timeout
+	conflictRetryDelay = 100 * time.Millisecond
+	// unavailableRetryDelay is delay before retry of request failed to connect to the database
+	unavailableRetryDelay = time.Second
+)
+
+// storageStatus describes how failure of the database is reported to clients
+type storageStatus struct {
+	code    codes.Code
+	message string
+	// retryDelay is sent in google.rpc.RetryInfo details, the failure is not retryable if it is 0
+	retryDelay time.Duration
+}
+
+// storageStatuses maps kinds of database failures to gRPC status
+var storageStatuses = map[repository.ErrorKind]storageStatus{
+	repository.KindUnknown:          {code: codes.Unknown, message: "database request failed"},
+	repository.KindDuplicate:        {code: codes.AlreadyExists, message: "resource already exists"},
+	repository.KindConflict:         {code: codes.Aborted, message: "transaction is aborted by concurrent transaction", retryDelay: conflictRetryDelay},
+	repository.KindUnavailable:      {code: codes.Unavailable, message: "database is unavailable", retryDelay: unavailableRetryDelay},
+	repository.KindCanceled:         {code: codes.Canceled, message: "request is canceled"},
+	repository.KindDeadlineExceeded: {code: codes.DeadlineExceeded, message: "request deadline is exceeded"},
+}
+
+// repositoryError converts error returned by repository to gRPC status error.
+// Failures of the database are logged with the raw error of database driver,
+// clients get generic message with google.rpc.ErrorInfo and google.rpc.RetryInfo details.
+func repositoryError(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var notFound *repository.NotFoundError
+	var mismatch *repository.RevisionMismatchError
+	var categoryNotFound *repository.CategoryNotFoundError
+	var categoryInUse *repository.CategoryInUseError
+	var categoryCycle *repository.CategoryCycleError
+	switch {
+	case errors.As(err, &notFound), errors.As(err, &categoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &mismatch):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &categoryInUse), errors.As(err, &categoryCycle):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	kind := errorKind(err)
+	ctxzap.Extract(ctx).Error("database request failed", zap.Stringer("kind", kind), zap.Error(err))
+	return storageError(kind)
+}
+
+// errorKind classifies failure of repository, errors of context are not classified by all repositories
+func errorKind(err error) repository.ErrorKind {
+	var storageErr *repository.StorageError
+	switch {
+	case errors.As(err, &storageErr):
+		return storageErr.Kind
+	case errors.Is(err, context.Canceled):
+		return repository.KindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return repository.KindDeadlineExceeded
+	}
+	return repository.KindUnknown
+}
+
+// storageError returns status error of the database failure with its details
+func storageError(kind repository.ErrorKind) error {
+	s := storageStatuses[kind]
+	details := []proto.Message{&errdetails.ErrorInfo{Type: kind.String(), Domain: errorDomain}}
+	if s.retryDelay > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(s.retryDelay)})
+	}
+
+	st := status.New(s.code, s.message)
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+)
+
+func Test_repositoryError(t *testing.T) {
+	ctx := context.Background()
+	driverErr := errors.New("Error 1213: Deadlock found when trying to get lock; try restarting transaction")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantType   string
+		wantRetry  bool
+		wantDetail bool
+	}{
+		{
+			name:       "Duplicate key",
+			err:        &repository.StorageError{Kind: repository.KindDuplicate, Op: "failed to insert into Product", Err: driverErr},
+			wantCode:   codes.AlreadyExists,
+			wantType:   "DUPLICATE",
+			wantDetail: true,
+		},
+		{
+			name:       "Deadlock",
+			err:        &repository.StorageError{Kind: repository.KindConflict, Op: "failed to update Product", Err: driverErr},
+			wantCode:   codes.Aborted,
+			wantType:   "CONFLICT",
+			wantRetry:  true,
+			wantDetail: true,
+		},
+		{
+			name:       "Connection refused",
+			err:        &repository.StorageError{Kind: repository.KindUnavailable, Op: "failed to begin transaction", Err: driverErr},
+			wantCode:   codes.Unavailable,
+			wantType:   "UNAVAILABLE",
+			wantRetry:  true,
+			wantDetail: true,
+		},
+		{
+			name:       "Context canceled",
+			err:        context.Canceled,
+			wantCode:   codes.Canceled,
+			wantType:   "CANCELED",
+			wantDetail: true,
+		},
+		{
+			name:       "Deadline exceeded",
+			err:        &repository.StorageError{Kind: repository.KindDeadlineExceeded, Op: "failed to select from Product", Err: driverErr},
+			wantCode:   codes.DeadlineExceeded,
+			wantType:   "DEADLINE_EXCEEDED",
+			wantDetail: true,
+		},
+		{
+			name:       "Unknown",
+			err:        &repository.StorageError{Op: "failed to select from Product", Err: driverErr},
+			wantCode:   codes.Unknown,
+			wantType:   "UNKNOWN",
+			wantDetail: true,
+		},
+		{
+			name:     "Not found",
+			err:      &repository.NotFoundError{ID: 1},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "Status error",
+			err:      status.Error(codes.InvalidArgument, "invalid"),
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(repositoryError(ctx, tt.err))
+			if st.Code() != tt.wantCode {
+				t.Errorf("repositoryError() code = %v, want %v", st.Code(), tt.wantCode)
+			}
+			if strings.Contains(st.Message(), "Deadlock") {
+				t.Errorf("repositoryError() message = %q, it leaks driver error", st.Message())
+			}
+
+			var info *errdetails.ErrorInfo
+			var retry *errdetails.RetryInfo
+			for _, d := range st.Details() {
+				switch d := d.(type) {
+				case *errdetails.ErrorInfo:
+					info = d
+				case *errdetails.RetryInfo:
+					retry = d
+				}
+			}
+			if (info != nil) != tt.wantDetail {
+				t.Fatalf("repositoryError() ErrorInfo = %v, want %v", info, tt.wantDetail)
+			}
+			if info != nil && (info.Type != tt.wantType || info.Domain != errorDomain) {
+				t.Errorf("repositoryError() ErrorInfo = %v, want type %v", info, tt.wantType)
+			}
+			if (retry != nil) != tt.wantRetry {
+				t.Errorf("repositoryError() RetryInfo = %v, want %v", retry, tt.wantRetry)
+			}
+			if retry != nil {
+				if d, err := ptypes.Duration(retry.RetryDelay); err != nil || d <= 0 {
+					t.Errorf("repositoryError() retry delay = %v, %v, want positive", d, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_productServiceServer_repositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	repo.err = &repository.StorageError{Kind: repository.KindDuplicate, Op: "failed to insert into Product",
+		Err: errors.New("Error 1062: Duplicate entry '1' for key 'PRIMARY'")}
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	_, err := s.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 1})
+	if st := status.Convert(err); st.Code() != codes.AlreadyExists || st.Message() != "resource already exists" {
+		t.Errorf("productServiceServer.Read() error = %v, want AlreadyExists without driver message", err)
+	}
+}
@@ -0,0 +1,276 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+	"github.com/MartyKuentzel/projectX/pkg/repository"
+	"github.com/MartyKuentzel/projectX/pkg/search"
+)
+
+// maximum lengths of Product fields in characters, they match columns of Product table
+const (
+	maxNameLength        = 200
+	maxCreatorLength     = 200
+	maxDescriptionLength = 1024
+)
+
+// maxQueryLength is maximum length of search query in characters
+const maxQueryLength = 200
+
+// maxRequestIDLength is maximum length of client-supplied request ID, it matches column of IdempotencyKey table
+const maxRequestIDLength = 128
+
+// minDate and maxDate are range of Product date, it is range of MySQL TIMESTAMP column,
+// the narrowest of supported databases
+var (
+	minDate = time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+	maxDate = time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC)
+)
+
+// violations collects invalid fields of request,
+// field paths are prefixed to locate fields of batch items
+type violations struct {
+	prefix string
+	list   []*errdetails.BadRequest_FieldViolation
+}
+
+// add reports invalid field
+func (v *violations) add(field string, format string, args ...interface{}) {
+	v.list = append(v.list, &errdetails.BadRequest_FieldViolation{
+		Field:       v.prefix + field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns InvalidArgument status error with google.rpc.BadRequest details listing invalid fields,
+// nil if all fields are valid
+func (v *violations) err() error {
+	if len(v.list) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(v.list))
+	for i, fv := range v.list {
+		msgs[i] = fv.Field + ": " + fv.Description
+	}
+	st := status.New(codes.InvalidArgument, "invalid request: "+strings.Join(msgs, "; "))
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v.list})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// length checks that text field is not longer than max characters
+func (v *violations) length(field string, s string, max int) {
+	if n := utf8.RuneCountInString(s); n > max {
+		v.add(field, "must be at most %d characters, but it has %d", max, n)
+	}
+}
+
+// id checks that ID field references existing row
+func (v *violations) id(field string, id int64) {
+	if id <= 0 {
+		v.add(field, "must be positive")
+	}
+}
+
+// timestamp checks that timestamp field is valid and returns it, zero time if it is not set or invalid
+func (v *violations) timestamp(field string, ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		v.add(field, "is invalid timestamp: %v", err)
+		return time.Time{}
+	}
+	return t
+}
+
+// product checks the fields of Product written by create or update
+func (v *violations) product(field string, p *v1.ProductProto, fields []repository.Field) {
+	if p == nil {
+		v.add(field, "is required")
+		return
+	}
+
+	if hasField(fields, repository.FieldName) {
+		if len(strings.TrimSpace(p.Name)) == 0 {
+			v.add(field+".name", "is required")
+		}
+		v.length(field+".name", p.Name, maxNameLength)
+	}
+	if hasField(fields, repository.FieldCreator) {
+		v.length(field+".creator", p.Creator, maxCreatorLength)
+	}
+	if hasField(fields, repository.FieldDescription) {
+		v.length(field+".description", p.Description, maxDescriptionLength)
+	}
+	if hasField(fields, repository.FieldPrice) {
+		if _, err := moneyFromProto(p.Price); err != nil {
+			v.add(field+".price", "%v", err)
+		}
+	}
+	if hasField(fields, repository.FieldUnit) {
+		if _, err := unitFromProto(p.Unit); err != nil {
+			v.add(field+".unit", "%v", err)
+		}
+	}
+	if hasField(fields, repository.FieldCategoryID) && p.CategoryId < 0 {
+		v.add(field+".category_id", "must not be negative")
+	}
+	if hasField(fields, repository.FieldDate) {
+		if p.Date == nil {
+			v.add(field+".date", "is required")
+		} else if date := v.timestamp(field+".date", p.Date); !date.IsZero() && (date.Before(minDate) || date.After(maxDate)) {
+			v.add(field+".date", "must be between %s and %s", minDate.Format(time.RFC3339), maxDate.Format(time.RFC3339))
+		}
+	}
+}
+
+// filter checks the filter of Products
+func (v *violations) filter(field string, f *v1.ProductFilter) {
+	if f == nil {
+		return
+	}
+
+	v.length(field+".name_prefix", f.NamePrefix, maxNameLength)
+	v.length(field+".creator", f.Creator, maxCreatorLength)
+	if f.CategoryId < 0 {
+		v.add(field+".category_id", "must not be negative")
+	}
+	if _, err := unitFromProto(f.Unit); err != nil {
+		v.add(field+".unit", "%v", err)
+	}
+
+	from := v.timestamp(field+".date_from", f.DateFrom)
+	to := v.timestamp(field+".date_to", f.DateTo)
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		v.add(field+".date_to", "must be after date_from")
+	}
+
+	priceFrom, err := moneyFromProto(f.PriceFrom)
+	if err != nil {
+		v.add(field+".price_from", "%v", err)
+	}
+	priceTo, err := moneyFromProto(f.PriceTo)
+	if err != nil {
+		v.add(field+".price_to", "%v", err)
+	}
+	if !priceFrom.IsZero() && !priceTo.IsZero() && priceFrom.Currency != priceTo.Currency {
+		v.add(field+".price_to", "must have the same currency as price_from")
+	}
+}
+
+// orderBy checks the order_by clause
+func (v *violations) orderBy(field string, s string) {
+	if _, err := parseOrderBy(s); err != nil {
+		v.add(field, "%v", err)
+	}
+}
+
+// validateCreate checks fields of create request, prefix locates the request in batch
+func validateCreate(prefix string, req *v1.CreateRequest) error {
+	v := &violations{prefix: prefix}
+	v.product("product", req.Product, nil)
+	v.length("request_id", req.RequestId, maxRequestIDLength)
+	return v.err()
+}
+
+// validateRead checks fields of read request, prefix locates the request in batch
+func validateRead(prefix string, req *v1.ReadRequest) error {
+	v := &violations{prefix: prefix}
+	v.id("id", req.Id)
+	return v.err()
+}
+
+// validateUpdate checks fields of update request, only the fields of update_mask are checked,
+// prefix locates the request in batch
+func validateUpdate(prefix string, req *v1.UpdateRequest) error {
+	v := &violations{prefix: prefix}
+	fields, err := updateFields(req.UpdateMask)
+	if err != nil {
+		v.add("update_mask", "%v", err)
+	}
+	if req.Product != nil {
+		v.id("product.id", req.Product.Id)
+	}
+	if err == nil {
+		v.product("product", req.Product, fields)
+	}
+	if req.ExpectedRevision < 0 {
+		v.add("expected_revision", "must not be negative")
+	}
+	v.length("request_id", req.RequestId, maxRequestIDLength)
+	return v.err()
+}
+
+// validateDelete checks fields of delete request, prefix locates the request in batch
+func validateDelete(prefix string, req *v1.DeleteRequest) error {
+	v := &violations{prefix: prefix}
+	v.id("id", req.Id)
+	if req.ExpectedRevision < 0 {
+		v.add("expected_revision", "must not be negative")
+	}
+	return v.err()
+}
+
+// validateUndelete checks fields of undelete request
+func validateUndelete(req *v1.UndeleteRequest) error {
+	v := &violations{}
+	v.id("id", req.Id)
+	return v.err()
+}
+
+// validateReadAll checks fields of ReadAll request
+func validateReadAll(req *v1.ReadAllRequest) error {
+	v := &violations{}
+	if req.PageSize < 0 {
+		v.add("page_size", "must not be negative")
+	}
+	v.orderBy("order_by", req.OrderBy)
+	v.filter("filter", req.Filter)
+	if _, err := unitFromProto(req.PricePer); err != nil {
+		v.add("price_per", "%v", err)
+	}
+	return v.err()
+}
+
+// validateSearch checks fields of Search request
+func validateSearch(req *v1.SearchRequest) error {
+	v := &violations{}
+	if len(search.Terms(req.Query)) == 0 {
+		v.add("query", "must contain words to search")
+	}
+	v.length("query", req.Query, maxQueryLength)
+	if req.PageSize < 0 {
+		v.add("page_size", "must not be negative")
+	}
+	return v.err()
+}
+
+// validateStreamProducts checks fields of StreamProducts request
+func validateStreamProducts(req *v1.StreamProductsRequest) error {
+	v := &violations{}
+	v.orderBy("order_by", req.OrderBy)
+	v.filter("filter", req.Filter)
+	return v.err()
+}
+
+// fieldError returns InvalidArgument status error with the only invalid field
+func fieldError(field string, err error) error {
+	v := &violations{}
+	v.add(field, "%v", err)
+	return v.err()
+}
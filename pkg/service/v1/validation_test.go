@@ -0,0 +1,202 @@
+package v1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/MartyKuentzel/projectX/pkg/api/v1"
+)
+
+// violatedFields returns fields listed in google.rpc.BadRequest details of InvalidArgument error
+func violatedFields(t *testing.T, err error) []string {
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, fv := range br.FieldViolations {
+				fields = append(fields, fv.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func Test_validateCreate(t *testing.T) {
+	date, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	tooLate, _ := ptypes.TimestampProto(time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name   string
+		req    *v1.CreateRequest
+		want   []string
+		prefix string
+	}{
+		{
+			name: "OK",
+			req: &v1.CreateRequest{Product: &v1.ProductProto{
+				Name:        strings.Repeat("ä", maxNameLength),
+				Description: strings.Repeat("d", maxDescriptionLength),
+				Date:        date,
+			}},
+		},
+		{
+			name: "No product",
+			req:  &v1.CreateRequest{},
+			want: []string{"product"},
+		},
+		{
+			name: "Empty name and no date",
+			req:  &v1.CreateRequest{Product: &v1.ProductProto{Name: " "}},
+			want: []string{"product.name", "product.date"},
+		},
+		{
+			name: "Too long",
+			req: &v1.CreateRequest{Product: &v1.ProductProto{
+				Name:        strings.Repeat("n", maxNameLength+1),
+				Creator:     strings.Repeat("c", maxCreatorLength+1),
+				Description: strings.Repeat("d", maxDescriptionLength+1),
+				Date:        date,
+			}, RequestId: strings.Repeat("r", maxRequestIDLength+1)},
+			want: []string{"product.name", "product.creator", "product.description", "request_id"},
+		},
+		{
+			name: "Invalid values",
+			req: &v1.CreateRequest{Product: &v1.ProductProto{
+				Name:       "name",
+				Price:      &v1.Money{CurrencyCode: "XYZ", Units: 1},
+				Unit:       v1.UnitOfMeasure(100),
+				CategoryId: -1,
+				Date:       &timestamp.Timestamp{Nanos: -1},
+			}},
+			want: []string{"product.price", "product.unit", "product.category_id", "product.date"},
+		},
+		{
+			name: "Date out of range",
+			req:  &v1.CreateRequest{Product: &v1.ProductProto{Name: "name", Date: tooLate}},
+			want: []string{"product.date"},
+		},
+		{
+			name:   "Batch item",
+			req:    &v1.CreateRequest{Product: &v1.ProductProto{Date: date}},
+			prefix: "requests[1].",
+			want:   []string{"requests[1].product.name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCreate(tt.prefix, tt.req)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("validateCreate() error = %v, want nil", err)
+				}
+				return
+			}
+			if got := violatedFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateCreate() violated %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *v1.UpdateRequest
+		want []string
+	}{
+		{
+			name: "Partial update",
+			req: &v1.UpdateRequest{
+				Product:    &v1.ProductProto{Id: 1, Description: "description"},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"description"}},
+			},
+		},
+		{
+			name: "Fields of mask only",
+			req: &v1.UpdateRequest{
+				Product:    &v1.ProductProto{Id: 1, Description: strings.Repeat("d", maxDescriptionLength+1)},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"name", "description"}},
+			},
+			want: []string{"product.name", "product.description"},
+		},
+		{
+			name: "Unknown mask path",
+			req: &v1.UpdateRequest{
+				Product:          &v1.ProductProto{},
+				UpdateMask:       &field_mask.FieldMask{Paths: []string{"revision"}},
+				ExpectedRevision: -1,
+			},
+			want: []string{"update_mask", "product.id", "expected_revision"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdate("", tt.req)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("validateUpdate() error = %v, want nil", err)
+				}
+				return
+			}
+			if got := violatedFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateUpdate() violated %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validateReadAll(t *testing.T) {
+	from, _ := ptypes.TimestampProto(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC))
+	to, _ := ptypes.TimestampProto(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	err := validateReadAll(&v1.ReadAllRequest{
+		PageSize: -1,
+		OrderBy:  "creator",
+		Filter: &v1.ProductFilter{
+			NamePrefix: strings.Repeat("n", maxNameLength+1),
+			DateFrom:   from,
+			DateTo:     to,
+			PriceFrom:  &v1.Money{CurrencyCode: "EUR", Units: 1},
+			PriceTo:    &v1.Money{CurrencyCode: "USD", Units: 2},
+		},
+		PricePer: v1.UnitOfMeasure(100),
+	})
+	want := []string{"page_size", "order_by", "filter.name_prefix", "filter.date_to", "filter.price_to", "price_per"}
+	if got := violatedFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("validateReadAll() violated %v, want %v", got, want)
+	}
+}
+
+func Test_productServiceServer_validation(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	s := NewProductServiceServer(repo, nil, []byte("secret"), nil)
+
+	// nil product is rejected before it is converted
+	_, err := s.Create(ctx, &v1.CreateRequest{Api: "v1"})
+	if got := violatedFields(t, err); !reflect.DeepEqual(got, []string{"product"}) {
+		t.Errorf("productServiceServer.Create() violated %v, want [product]", got)
+	}
+
+	// all-or-nothing batch keeps field violations of the failed request
+	_, err = s.BatchDelete(ctx, &v1.BatchDeleteRequest{Api: "v1", Requests: []*v1.DeleteRequest{{Id: 0}}})
+	if got := violatedFields(t, err); !reflect.DeepEqual(got, []string{"requests[0].id"}) {
+		t.Errorf("productServiceServer.BatchDelete() violated %v, want [requests[0].id]", got)
+	}
+	if msg := status.Convert(err).Message(); !strings.HasPrefix(msg, "requests[0]: ") {
+		t.Errorf("productServiceServer.BatchDelete() message = %q, want requests[0] prefix", msg)
+	}
+}
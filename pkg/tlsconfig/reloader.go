@@ -0,0 +1,215 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MartyKuentzel/projectX/pkg/logger"
+)
+
+// Reloader keeps server certificate and client CA certificates loaded from files,
+// and loads them again when the files change, so that certificates are rotated without restart
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu sync.RWMutex
+	// cert is server certificate
+	cert *tls.Certificate
+	// clientCAs verify client certificates, nil if mutual TLS is disabled
+	clientCAs *x509.CertPool
+	// modTimes are modification times of the loaded files
+	modTimes []time.Time
+}
+
+// NewReloader loads server certificate of certFile and keyFile, and CA certificates of clientCAFile
+// to verify client certificates, clients aren't required to present certificates if clientCAFile is empty
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	modTimes, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTimes); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// files returns the files to watch
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if len(r.clientCAFile) > 0 {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+// stat returns modification times of the files
+func (r *Reloader) stat() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, f := range r.files() {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return nil, errors.New("failed to stat TLS file-> " + err.Error())
+		}
+		modTimes = append(modTimes, fi.ModTime())
+	}
+	return modTimes, nil
+}
+
+// load reads the files and replaces loaded certificates
+func (r *Reloader) load(modTimes []time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errors.New("failed to load server certificate-> " + err.Error())
+	}
+	var clientCAs *x509.CertPool
+	if len(r.clientCAFile) > 0 {
+		if clientCAs, err = loadCertPool(r.clientCAFile); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert, r.clientCAs, r.modTimes = &cert, clientCAs, modTimes
+	return nil
+}
+
+// changed returns true if any file is modified after it was loaded
+func (r *Reloader) changed(modTimes []time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, t := range modTimes {
+		if !t.Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload loads the files again if they are modified. Certificates loaded before are kept if loading fails,
+// e.g. if certificate is replaced but key isn't yet, and the files are loaded again by the next call.
+func (r *Reloader) Reload() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+	if !r.changed(modTimes) {
+		return nil
+	}
+	if err := r.load(modTimes); err != nil {
+		return err
+	}
+	logger.Log.Info("reloaded TLS certificates", zap.Strings("files", r.files()))
+	return nil
+}
+
+// Run checks the files for changes every interval until ctx is done
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				logger.Log.Error("failed to reload TLS certificates", zap.Error(err))
+			}
+		}
+	}
+}
+
+// certificate returns the current server certificate
+func (r *Reloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// verifyClient verifies client certificate by the current client CA certificates.
+// Server certificate is accepted too, it is presented by connections of the server to itself.
+func (r *Reloader) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("client certificate is required")
+	}
+	if isOwn(r.certificate(), rawCerts[0]) {
+		return nil
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	intermediates := x509.NewCertPool()
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return errors.New("failed to parse client certificate-> " + err.Error())
+		}
+		certs[i] = cert
+		if i > 0 {
+			intermediates.AddCert(cert)
+		}
+	}
+
+	r.mu.RLock()
+	roots := r.clientCAs
+	r.mu.RUnlock()
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// isOwn returns true if raw certificate is the leaf of server certificate
+func isOwn(own *tls.Certificate, raw []byte) bool {
+	return len(own.Certificate) > 0 && bytes.Equal(own.Certificate[0], raw)
+}
+
+// ServerConfig returns TLS configuration of server presenting the current certificate,
+// and requiring client certificate signed by the current client CA if mutual TLS is enabled
+func (r *Reloader) ServerConfig(minVersion uint16) *tls.Config {
+	c := &tls.Config{
+		MinVersion: minVersion,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+	}
+	if len(r.clientCAFile) > 0 {
+		// client certificate is verified by verifyClient, because ClientCAs can't be replaced after start
+		c.ClientAuth = tls.RequireAnyClientCert
+		c.VerifyPeerCertificate = r.verifyClient
+	}
+	return c
+}
+
+// LoopbackConfig returns TLS configuration of connections of the server to itself, e.g. of REST gateway.
+// Connection presents server certificate as client certificate and trusts server certificate only,
+// so that it needs no client certificate and no CA of server certificate.
+func (r *Reloader) LoopbackConfig(minVersion uint16) *tls.Config {
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+		// server certificate is verified by VerifyPeerCertificate instead of host name and CA
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !isOwn(r.certificate(), rawCerts[0]) {
+				return errors.New("server presented unexpected certificate")
+			}
+			return nil
+		},
+	}
+}
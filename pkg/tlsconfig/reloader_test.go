@@ -0,0 +1,207 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MartyKuentzel/projectX/pkg/logger"
+)
+
+// testCert is certificate and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert issues certificate by parent, self-signed one if parent is nil
+func newTestCert(t *testing.T, serial int64, parent *testCert, isCA bool, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"localhost"},
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if !isCA {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// write writes certificate and key to PEM files of dir and returns their names
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+// handshake connects client to server and returns serial number of server certificate
+func handshake(server, client *tls.Config) (int64, error) {
+	s, c := net.Pipe()
+	defer s.Close()
+	defer c.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	s.SetDeadline(deadline)
+	c.SetDeadline(deadline)
+
+	go func() {
+		conn := tls.Server(s, server)
+		if err := conn.Handshake(); err != nil {
+			s.Close()
+			return
+		}
+		// client learns that its certificate is accepted by TLS 1.3 server when it reads
+		conn.Write([]byte("ok"))
+	}()
+
+	conn := tls.Client(c, client)
+	if _, err := conn.Read(make([]byte, 2)); err != nil {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestReloader(t *testing.T) {
+	logger.Log = zap.NewNop()
+	dir, err := ioutil.TempDir("", "tlsconfig")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, 1, nil, true, 0)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := newTestCert(t, 2, ca, false, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+	clientCert, clientKey := newTestCert(t, 3, ca, false, x509.ExtKeyUsageClientAuth).write(t, dir, "client")
+	otherCert, otherKey := newTestCert(t, 4, newTestCert(t, 5, nil, true, 0), false, x509.ExtKeyUsageClientAuth).
+		write(t, dir, "other")
+
+	r, err := NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	server := r.ServerConfig(tls.VersionTLS12)
+
+	clientConfig := func(certFile, keyFile string) *tls.Config {
+		c, err := ClientConfig(certFile, keyFile, caFile, tls.VersionTLS12)
+		if err != nil {
+			t.Fatalf("ClientConfig() error = %v", err)
+		}
+		c.ServerName = "localhost"
+		return c
+	}
+	tests := []struct {
+		name    string
+		client  *tls.Config
+		wantErr bool
+	}{
+		{name: "Client certificate of client CA", client: clientConfig(clientCert, clientKey)},
+		{name: "No client certificate", client: clientConfig("", ""), wantErr: true},
+		{name: "Client certificate of other CA", client: clientConfig(otherCert, otherKey), wantErr: true},
+		{name: "Loopback", client: r.LoopbackConfig(tls.VersionTLS12)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serial, err := handshake(server, tt.client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handshake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && serial != 2 {
+				t.Errorf("handshake() server certificate = %d, want 2", serial)
+			}
+		})
+	}
+
+	// rotated certificate is presented after reload, loopback trusts it
+	newTestCert(t, 6, ca, false, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+	later := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, later, later); err != nil {
+			t.Fatalf("failed to touch %s: %v", f, err)
+		}
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reloader.Reload() error = %v", err)
+	}
+	for _, client := range []*tls.Config{clientConfig(clientCert, clientKey), r.LoopbackConfig(tls.VersionTLS12)} {
+		if serial, err := handshake(server, client); err != nil || serial != 6 {
+			t.Errorf("handshake() after reload = %d, %v, want 6", serial, err)
+		}
+	}
+
+	// certificate is kept if the files don't match
+	if err := ioutil.WriteFile(keyFile, []byte("broken"), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	if err := os.Chtimes(keyFile, later.Add(time.Minute), later.Add(time.Minute)); err != nil {
+		t.Fatalf("failed to touch %s: %v", keyFile, err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Errorf("Reloader.Reload() of broken key error = nil, want error")
+	}
+	if serial, err := handshake(server, clientConfig(clientCert, clientKey)); err != nil || serial != 6 {
+		t.Errorf("handshake() after failed reload = %d, %v, want 6", serial, err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    uint16
+		wantErr bool
+	}{
+		{s: "1.2", want: tls.VersionTLS12},
+		{s: "1.3", want: tls.VersionTLS13},
+		{s: "TLS1.2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseVersion(tt.s)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseVersion() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
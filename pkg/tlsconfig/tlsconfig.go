@@ -0,0 +1,61 @@
+// Package tlsconfig builds TLS configurations of servers and clients from PEM files.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// versions are supported minimum TLS versions
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion parses TLS version in format "1.2"
+func ParseVersion(s string) (uint16, error) {
+	v, ok := versions[s]
+	if !ok {
+		return 0, fmt.Errorf("unsupported TLS version '%s', use 1.0, 1.1, 1.2 or 1.3", s)
+	}
+	return v, nil
+}
+
+// loadCertPool reads PEM encoded certificates of the file
+func loadCertPool(file string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.New("failed to read CA certificates-> " + err.Error())
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no CA certificates found in '%s'", file)
+	}
+	return pool, nil
+}
+
+// ClientConfig returns TLS configuration of client. Server certificate is verified by CA certificates of caFile,
+// by system CAs if it is empty. Client presents certificate of certFile and keyFile for mutual TLS unless they are empty.
+func ClientConfig(certFile, keyFile, caFile string, minVersion uint16) (*tls.Config, error) {
+	c := &tls.Config{MinVersion: minVersion}
+	if len(caFile) > 0 {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+	if len(certFile) > 0 || len(keyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.New("failed to load client certificate-> " + err.Error())
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
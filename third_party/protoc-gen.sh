@@ -0,0 +1,11 @@
+#!/bin/sh
+# Generates gRPC code, REST gateway, OpenAPI document and its Go constant from api/proto/v1,
+# run it from the repository root. Requires protoc with protoc-gen-go, protoc-gen-grpc-gateway
+# and protoc-gen-swagger of the versions in go.mod.
+set -e
+
+protoc --proto_path=api/proto/v1 --proto_path=third_party --go_out=plugins=grpc:pkg/api/v1 category-service.proto
+protoc --proto_path=api/proto/v1 --proto_path=third_party --go_out=plugins=grpc:pkg/api/v1 product-service.proto
+protoc --proto_path=api/proto/v1 --proto_path=third_party --grpc-gateway_out=logtostderr=true:pkg/api/v1 product-service.proto
+protoc --proto_path=api/proto/v1 --proto_path=third_party --swagger_out=logtostderr=true:api/swagger/v1 product-service.proto
+go run pkg/api/v1/gen-swagger.go api/swagger/v1/product-service.swagger.json pkg/api/v1/product-service.swagger.go